@@ -0,0 +1,130 @@
+package agora
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Sentinel errors matched by APIError with errors.Is.
+//
+// @since v0.13.0
+var (
+	// The requested resource, e.g. a recording session or an agent, does not exist
+	ErrNotFound = errors.New("agora: not found")
+	// Too many requests were sent
+	ErrRateLimited = errors.New("agora: rate limited")
+	// The credential is missing, invalid or not allowed to access the resource
+	ErrUnauthorized = errors.New("agora: unauthorized")
+	// The resource, e.g. a cloud recording resource ID, has expired
+	ErrResourceExpired = errors.New("agora: resource expired")
+)
+
+// @brief APIError is returned when the Agora REST API responds with an unsuccessful HTTP status code
+//
+// @note Use errors.Is with ErrNotFound, ErrRateLimited, ErrUnauthorized and ErrResourceExpired to check the kind of the error,
+// and errors.As to read the details.
+//
+// @since v0.13.0
+type APIError struct {
+	// HTTP status code
+	HttpStatusCode int
+	// Error code returned by the service, 0 if the response carries no error code
+	Code int
+	// Reason for the error returned by the service
+	Reason string
+	// Error details returned by the service
+	Detail string
+	// Request ID, see BaseResponse.GetRequestID
+	RequestID string
+	// Name of the API operation, e.g. "cloudRecording:start"
+	Endpoint string
+	// Number of HTTP attempts made for the call
+	Attempts int
+	// Sentinel error matched by errors.Is in addition to the ones derived from HttpStatusCode.(Optional)
+	//
+	// Set by services whose error codes identify the kind of the error, e.g. ErrResourceExpired.
+	Kind error
+	// Underlying error, e.g. a GatewayErr.(Optional)
+	Err error
+}
+
+// @brief NewAPIError creates an APIError from an unsuccessful response
+//
+// @note The error code, reason and details are read from the "code", "reason", "detail" and "message" fields of a JSON body.
+//
+// @since v0.13.0
+func NewAPIError(response *BaseResponse, endpoint string, attempts int) *APIError {
+	apiErr := &APIError{
+		Endpoint: endpoint,
+		Attempts: attempts,
+	}
+	if response == nil {
+		return apiErr
+	}
+
+	apiErr.HttpStatusCode = response.HttpStatusCode
+	apiErr.RequestID = response.GetRequestID()
+	if !gjson.ValidBytes(response.RawBody) {
+		return apiErr
+	}
+	if code := gjson.GetBytes(response.RawBody, "code"); code.Type == gjson.Number {
+		apiErr.Code = int(code.Int())
+	}
+	apiErr.Reason = gjson.GetBytes(response.RawBody, "reason").String()
+	apiErr.Detail = gjson.GetBytes(response.RawBody, "detail").String()
+	if apiErr.Detail == "" {
+		apiErr.Detail = gjson.GetBytes(response.RawBody, "message").String()
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "endpoint:%s,http status code:%d", e.Endpoint, e.HttpStatusCode)
+	if e.Code != 0 {
+		fmt.Fprintf(&b, ",code:%d", e.Code)
+	}
+	if e.Reason != "" {
+		fmt.Fprintf(&b, ",reason:%s", e.Reason)
+	}
+	if e.Detail != "" {
+		fmt.Fprintf(&b, ",detail:%s", e.Detail)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, ",request id:%s", e.RequestID)
+	}
+	fmt.Fprintf(&b, ",attempts:%d", e.Attempts)
+	if e.Err != nil {
+		fmt.Fprintf(&b, ",err:%s", e.Err)
+	}
+
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the kind of target, one of ErrNotFound, ErrRateLimited, ErrUnauthorized and ErrResourceExpired.
+func (e *APIError) Is(target error) bool {
+	if e.Kind != nil && e.Kind == target {
+		return true
+	}
+
+	switch target {
+	case ErrNotFound:
+		return e.HttpStatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.HttpStatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.HttpStatusCode == http.StatusUnauthorized || e.HttpStatusCode == http.StatusForbidden
+	default:
+		return false
+	}
+}
@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// @brief CredentialProvider returns the credential used by each request
+//
+// @note Use it instead of a fixed Credential when the credential rotates, the client does not need to be recreated.
+//
+// @since v0.13.0
+type CredentialProvider interface {
+	Credential(ctx context.Context) (Credential, error)
+}
+
+// @brief CredentialProviderFunc adapts a callback to CredentialProvider
+//
+// @note The callback is called for each request, cache the credential in the callback if obtaining it is expensive.
+//
+// @since v0.13.0
+type CredentialProviderFunc func(ctx context.Context) (Credential, error)
+
+func (f CredentialProviderFunc) Credential(ctx context.Context) (Credential, error) {
+	return f(ctx)
+}
+
+// @brief Create a CredentialProvider that always returns the same credential
+//
+// @since v0.13.0
+func NewStaticCredentialProvider(credential Credential) CredentialProvider {
+	return CredentialProviderFunc(func(ctx context.Context) (Credential, error) {
+		return credential, nil
+	})
+}
+
+// @brief Create a CredentialProvider that reads the customer ID and customer secret from environment variables for each request
+//
+// @param customerIDEnv Name of the environment variable holding the customer ID
+//
+// @param customerSecretEnv Name of the environment variable holding the customer secret
+//
+// @return Returns a provider of BasicAuthCredential
+//
+// @since v0.13.0
+func NewEnvCredentialProvider(customerIDEnv string, customerSecretEnv string) CredentialProvider {
+	return CredentialProviderFunc(func(ctx context.Context) (Credential, error) {
+		customerID := os.Getenv(customerIDEnv)
+		customerSecret := os.Getenv(customerSecretEnv)
+		if customerID == "" || customerSecret == "" {
+			return nil, fmt.Errorf("environment variable %s or %s is not set", customerIDEnv, customerSecretEnv)
+		}
+		return NewBasicAuthCredential(customerID, customerSecret), nil
+	})
+}
+
+// @brief Parses the content of a credential file, see NewFileCredentialProvider
+//
+// @since v0.13.0
+type CredentialParser func(content []byte) (Credential, error)
+
+// @brief Parses a JSON credential file
+//
+// @note The file contains either {"customerId": "...", "customerSecret": "..."} for basic authentication,
+// or {"token": "..."} for token authentication.
+//
+// @since v0.13.0
+func ParseJSONCredential(content []byte) (Credential, error) {
+	var file struct {
+		CustomerID     string `json:"customerId"`
+		CustomerSecret string `json:"customerSecret"`
+		Token          string `json:"token"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case file.CustomerID != "" && file.CustomerSecret != "":
+		return NewBasicAuthCredential(file.CustomerID, file.CustomerSecret), nil
+	case file.Token != "":
+		return NewTokenCredential(file.Token), nil
+	default:
+		return nil, errors.New("credential file contains neither customerId and customerSecret nor token")
+	}
+}
+
+// @brief Option of NewFileCredentialProvider
+//
+// @since v0.13.0
+type FileCredentialProviderOption func(p *fileCredentialProvider)
+
+// @brief Set the parser of the credential file. The default value is ParseJSONCredential.
+//
+// @since v0.13.0
+func WithCredentialParser(parser CredentialParser) FileCredentialProviderOption {
+	return func(p *fileCredentialProvider) {
+		p.parser = parser
+	}
+}
+
+// @brief Set the minimum interval between checks of the credential file for changes. The default value is 1 second.
+//
+// @since v0.13.0
+func WithCheckInterval(interval time.Duration) FileCredentialProviderOption {
+	return func(p *fileCredentialProvider) {
+		p.checkInterval = interval
+	}
+}
+
+// @brief Set the handler of the errors that occur when the changed credential file is reloaded
+//
+// @note The last successfully loaded credential is kept when the file cannot be reloaded, e.g. when it cannot be parsed,
+// requests are not affected. The handler is called by the request that checks the file.
+//
+// @since v0.13.0
+func WithReloadErrorHandler(handler func(err error)) FileCredentialProviderOption {
+	return func(p *fileCredentialProvider) {
+		p.onReloadError = handler
+	}
+}
+
+const defaultCheckInterval = time.Second
+
+type fileCredentialProvider struct {
+	path          string
+	parser        CredentialParser
+	checkInterval time.Duration
+	onReloadError func(err error)
+
+	locker     sync.Mutex
+	credential Credential
+	modTime    time.Time
+	size       int64
+	lastCheck  time.Time
+}
+
+// @brief Create a CredentialProvider that reads the credential from a file, e.g. one written by a secret manager sidecar
+//
+// @note The file is checked for changes when a request is sent, at most once per check interval, and reloaded when its modification time or size changes.
+// The last successfully loaded credential is kept when the changed file cannot be parsed, see WithReloadErrorHandler.
+//
+// @param path Path of the credential file
+//
+// @param options Options, see WithCredentialParser, WithCheckInterval and WithReloadErrorHandler
+//
+// @return Returns the provider, or an error if the file cannot be loaded
+//
+// @since v0.13.0
+func NewFileCredentialProvider(path string, options ...FileCredentialProviderOption) (CredentialProvider, error) {
+	p := &fileCredentialProvider{
+		path:          path,
+		parser:        ParseJSONCredential,
+		checkInterval: defaultCheckInterval,
+	}
+	for _, option := range options {
+		option(p)
+	}
+
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *fileCredentialProvider) Credential(ctx context.Context) (Credential, error) {
+	p.locker.Lock()
+	var err error
+	if time.Since(p.lastCheck) >= p.checkInterval {
+		err = p.load()
+	}
+	credential := p.credential
+	p.locker.Unlock()
+
+	// The handler is called without holding the lock, it may use the provider
+	if err != nil && p.onReloadError != nil {
+		p.onReloadError(err)
+	}
+	return credential, nil
+}
+
+// load reloads the credential file if it has changed since it was last loaded.
+func (p *fileCredentialProvider) load() error {
+	p.lastCheck = time.Now()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+	if p.credential != nil && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return nil
+	}
+
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+	credential, err := p.parser(content)
+	if err != nil {
+		return fmt.Errorf("parse credential file %s failed:%w", p.path, err)
+	}
+
+	p.credential = credential
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+	return nil
+}
@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// authorization returns the Authorization header set by the credential.
+func authorization(t *testing.T, credential Credential) string {
+	t.Helper()
+
+	if credential == nil {
+		t.Fatal("credential = nil")
+	}
+	r, _ := http.NewRequest(http.MethodGet, "https://api.agora.io", nil)
+	credential.SetAuth(r)
+	return r.Header.Get("Authorization")
+}
+
+func basicAuthorization(username string, password string) string {
+	r, _ := http.NewRequest(http.MethodGet, "https://api.agora.io", nil)
+	r.SetBasicAuth(username, password)
+	return r.Header.Get("Authorization")
+}
+
+func TestStaticCredentialProvider(t *testing.T) {
+	credential := NewBasicAuthCredential("customer", "secret")
+	got, err := NewStaticCredentialProvider(credential).Credential(context.Background())
+	if err != nil || got != credential {
+		t.Errorf("Credential() = %v, %v, want the static credential", got, err)
+	}
+}
+
+func TestCredentialProviderFunc(t *testing.T) {
+	errUnavailable := errors.New("vault unavailable")
+	tokens := []string{"first", "second"}
+	calls := 0
+	provider := CredentialProviderFunc(func(ctx context.Context) (Credential, error) {
+		calls++
+		if calls > len(tokens) {
+			return nil, errUnavailable
+		}
+		return NewTokenCredential(tokens[calls-1]), nil
+	})
+
+	// Called for each request, the credential rotates without recreating the client
+	for _, token := range tokens {
+		credential, err := provider.Credential(context.Background())
+		if err != nil {
+			t.Fatalf("Credential() error = %v", err)
+		}
+		if got := authorization(t, credential); got != "agora token="+token {
+			t.Errorf("Authorization = %s, want agora token=%s", got, token)
+		}
+	}
+	if _, err := provider.Credential(context.Background()); !errors.Is(err, errUnavailable) {
+		t.Errorf("Credential() error = %v, want %v", err, errUnavailable)
+	}
+}
+
+func TestEnvCredentialProvider(t *testing.T) {
+	provider := NewEnvCredentialProvider("TEST_AGORA_CUSTOMER_ID", "TEST_AGORA_CUSTOMER_SECRET")
+
+	t.Setenv("TEST_AGORA_CUSTOMER_ID", "customer")
+	t.Setenv("TEST_AGORA_CUSTOMER_SECRET", "")
+	if _, err := provider.Credential(context.Background()); err == nil || !strings.Contains(err.Error(), "TEST_AGORA_CUSTOMER_SECRET") {
+		t.Errorf("Credential() error = %v, want the unset variable reported", err)
+	}
+
+	t.Setenv("TEST_AGORA_CUSTOMER_SECRET", "secret")
+	credential, err := provider.Credential(context.Background())
+	if err != nil {
+		t.Fatalf("Credential() error = %v", err)
+	}
+	if got := authorization(t, credential); got != basicAuthorization("customer", "secret") {
+		t.Errorf("Authorization = %s, want the basic authorization of customer:secret", got)
+	}
+
+	// Read for each request
+	t.Setenv("TEST_AGORA_CUSTOMER_SECRET", "rotated")
+	credential, _ = provider.Credential(context.Background())
+	if got := authorization(t, credential); got != basicAuthorization("customer", "rotated") {
+		t.Errorf("Authorization = %s, want the basic authorization of customer:rotated", got)
+	}
+}
+
+func TestParseJSONCredential(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "basic", content: `{"customerId":"customer","customerSecret":"secret"}`, want: basicAuthorization("customer", "secret")},
+		{name: "token", content: `{"token":"007token"}`, want: "agora token=007token"},
+		{name: "basic preferred", content: `{"customerId":"customer","customerSecret":"secret","token":"007token"}`, want: basicAuthorization("customer", "secret")},
+		{name: "customer secret missing", content: `{"customerId":"customer"}`, wantErr: true},
+		{name: "malformed", content: `{"customerId":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credential, err := ParseJSONCredential([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJSONCredential() error = %v, want error %t", err, tt.wantErr)
+			}
+			if err == nil {
+				if got := authorization(t, credential); got != tt.want {
+					t.Errorf("Authorization = %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// writeCredentialFile writes content to path with a modification time different from the previous one.
+func writeCredentialFile(t *testing.T, path string, content string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileCredentialProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credential.json")
+	modTime := time.Now().Add(-time.Hour)
+	writeCredentialFile(t, path, `{"customerId":"customer","customerSecret":"secret"}`, modTime)
+
+	var (
+		locker       sync.Mutex
+		reloadErrors []error
+	)
+	provider, err := NewFileCredentialProvider(path, WithCheckInterval(0), WithReloadErrorHandler(func(err error) {
+		locker.Lock()
+		reloadErrors = append(reloadErrors, err)
+		locker.Unlock()
+	}))
+	if err != nil {
+		t.Fatalf("NewFileCredentialProvider() error = %v", err)
+	}
+	ctx := context.Background()
+
+	credential, err := provider.Credential(ctx)
+	if err != nil || authorization(t, credential) != basicAuthorization("customer", "secret") {
+		t.Fatalf("Credential() = %v, %v, want the credential of the file", credential, err)
+	}
+
+	// Rotation
+	writeCredentialFile(t, path, `{"token":"rotated"}`, modTime.Add(time.Minute))
+	credential, err = provider.Credential(ctx)
+	if err != nil || authorization(t, credential) != "agora token=rotated" {
+		t.Fatalf("Credential() = %v, %v, want the rotated credential", credential, err)
+	}
+
+	// A malformed file keeps the last credential and reports the error
+	writeCredentialFile(t, path, `{"token":`, modTime.Add(2*time.Minute))
+	credential, err = provider.Credential(ctx)
+	if err != nil || authorization(t, credential) != "agora token=rotated" {
+		t.Errorf("Credential() = %v, %v, want the last loaded credential", credential, err)
+	}
+	// A removed file keeps the last credential and reports the error
+	if err = os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	credential, err = provider.Credential(ctx)
+	if err != nil || authorization(t, credential) != "agora token=rotated" {
+		t.Errorf("Credential() = %v, %v, want the last loaded credential", credential, err)
+	}
+
+	locker.Lock()
+	defer locker.Unlock()
+	if len(reloadErrors) != 2 || !strings.Contains(reloadErrors[0].Error(), "parse credential file") || !errors.Is(reloadErrors[1], os.ErrNotExist) {
+		t.Errorf("reload errors = %v, want the parse error and the missing file", reloadErrors)
+	}
+}
+
+func TestFileCredentialProviderCheckInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credential.json")
+	modTime := time.Now().Add(-time.Hour)
+	writeCredentialFile(t, path, `{"token":"first"}`, modTime)
+
+	provider, err := NewFileCredentialProvider(path, WithCheckInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("NewFileCredentialProvider() error = %v", err)
+	}
+	writeCredentialFile(t, path, `{"token":"second"}`, modTime.Add(time.Minute))
+
+	credential, _ := provider.Credential(context.Background())
+	if got := authorization(t, credential); got != "agora token=first" {
+		t.Errorf("Authorization = %s, want the file not checked again within the interval", got)
+	}
+}
+
+func TestFileCredentialProviderInitialLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := NewFileCredentialProvider(filepath.Join(dir, "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("NewFileCredentialProvider() error = %v, want os.ErrNotExist", err)
+	}
+
+	path := filepath.Join(dir, "malformed.json")
+	writeCredentialFile(t, path, `not json`, time.Now())
+	if _, err := NewFileCredentialProvider(path); err == nil {
+		t.Error("NewFileCredentialProvider() error = nil, want the parse error")
+	}
+
+	path = filepath.Join(dir, "custom")
+	writeCredentialFile(t, path, "customer:secret", time.Now())
+	provider, err := NewFileCredentialProvider(path, WithCredentialParser(func(content []byte) (Credential, error) {
+		parts := strings.SplitN(string(content), ":", 2)
+		return NewBasicAuthCredential(parts[0], parts[1]), nil
+	}))
+	if err != nil {
+		t.Fatalf("NewFileCredentialProvider() error = %v", err)
+	}
+	credential, _ := provider.Credential(context.Background())
+	if got := authorization(t, credential); got != basicAuthorization("customer", "secret") {
+		t.Errorf("Authorization = %s, want the credential of the custom parser", got)
+	}
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"net/http"
+)
+
+type TokenCredential struct {
+	token string
+}
+
+// Ensure TokenCredential implements Credential
+var _ Credential = (*TokenCredential)(nil)
+
+// @brief Create a new TokenCredential instance
+//
+// @note RTC or RTM token used for token authentication, the request carries the header "Authorization: agora token=<token>".
+// Only the APIs that accept token authentication can be called with it.
+//
+// @param token RTC or RTM token generated with the App ID and App certificate
+//
+// @return Returns the TokenCredential instance
+//
+// @since v0.13.0
+func NewTokenCredential(token string) *TokenCredential {
+	return &TokenCredential{
+		token: token,
+	}
+}
+
+func (t *TokenCredential) Name() string {
+	return "token"
+}
+
+func (t *TokenCredential) SetAuth(r *http.Request) {
+	r.Header.Set("Authorization", "agora token="+t.token)
+}
@@ -0,0 +1,287 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// @brief Policy of the circuit breakers
+//
+// @since v0.13.0
+type Policy struct {
+	// Number of consecutive failures after which the circuit opens.
+	//
+	// The circuit never opens when it is 0.
+	FailureThreshold int
+	// Duration the circuit stays open before requests are let through again to probe the host
+	OpenDuration time.Duration
+	// Maximum number of probe requests in flight while the circuit is half-open.
+	//
+	// Values less than 1 are treated as 1.
+	HalfOpenRequests int
+}
+
+// @brief Returns the default circuit breaker policy
+//
+// @note 5 consecutive failures open the circuit for 10 seconds, then 1 probe request is let through at a time.
+//
+// @return Returns a new Policy instance that can be modified freely.
+//
+// @since v0.13.0
+func DefaultPolicy() *Policy {
+	return &Policy{
+		FailureThreshold: 5,
+		OpenDuration:     10 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// @brief State of a circuit breaker
+//
+// @since v0.13.0
+type State int
+
+const (
+	// Requests are let through
+	StateClosed State = iota
+	// Requests fail fast without being sent
+	StateOpen
+	// A limited number of probe requests are let through, see Policy.HalfOpenRequests
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// @brief Admission of a request let through by Breaker.Allow
+//
+// @note Pass it to Breaker.Record or Breaker.Cancel. The outcome of a request admitted before the last state change,
+// e.g. one admitted while the circuit was closed and completed after it went half-open, is ignored.
+//
+// @since v0.13.0
+type Admission struct {
+	// state of the breaker when the request was admitted
+	generation uint64
+	// whether the request holds a probe slot of the half-open state
+	probe bool
+}
+
+// @brief Circuit breaker of a host
+//
+// @note A request let through by Allow must be followed by either Record or Cancel with its Admission.
+//
+// @since v0.13.0
+type Breaker struct {
+	policy *Policy
+
+	locker              *sync.Mutex
+	state               State
+	consecutiveFailures int
+	openUntil           time.Time
+	probes              int
+	// incremented by every state change, see Admission
+	generation uint64
+}
+
+// @brief Creates a circuit breaker
+//
+// @param policy Policy of the breaker, DefaultPolicy() is used when it is nil. See Policy for details.
+//
+// @return Returns the Breaker instance.
+//
+// @since v0.13.0
+func New(policy *Policy) *Breaker {
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	return &Breaker{
+		policy: policy,
+		locker: &sync.Mutex{},
+	}
+}
+
+// @brief Reports whether a request can be sent, and lets it through
+//
+// @return Returns the admission of the request, and true if the request can be sent.
+//
+// @since v0.13.0
+func (b *Breaker) Allow() (Admission, bool) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+
+	b.advance(time.Now())
+	admission := Admission{generation: b.generation}
+	switch b.state {
+	case StateOpen:
+		return admission, false
+	case StateHalfOpen:
+		maxProbes := b.policy.HalfOpenRequests
+		if maxProbes < 1 {
+			maxProbes = 1
+		}
+		if b.probes >= maxProbes {
+			return admission, false
+		}
+		b.probes++
+		admission.probe = true
+	}
+	return admission, true
+}
+
+// @brief Records the outcome of a request let through by Allow
+//
+// @param admission Admission returned by Allow. The outcome is ignored when the state changed since the request was admitted.
+//
+// @param success Whether a response is received, a transport error or a 5xx status code is a failure
+//
+// @return Returns true if the outcome opened the circuit.
+//
+// @since v0.13.0
+func (b *Breaker) Record(admission Admission, success bool) (opened bool) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+
+	now := time.Now()
+	b.advance(now)
+	if admission.generation != b.generation {
+		return false
+	}
+	if admission.probe && b.probes > 0 {
+		b.probes--
+	}
+
+	if success {
+		b.consecutiveFailures = 0
+		if b.state == StateHalfOpen {
+			b.setState(StateClosed)
+		}
+		return false
+	}
+
+	b.consecutiveFailures++
+	switch b.state {
+	case StateHalfOpen:
+		b.open(now)
+		return true
+	case StateClosed:
+		if b.policy.FailureThreshold > 0 && b.consecutiveFailures >= b.policy.FailureThreshold {
+			b.open(now)
+			return true
+		}
+	}
+	return false
+}
+
+// @brief Releases a request let through by Allow whose outcome says nothing about the host, e.g. it was canceled by the caller
+//
+// @param admission Admission returned by Allow
+//
+// @since v0.13.0
+func (b *Breaker) Cancel(admission Admission) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+
+	if admission.probe && admission.generation == b.generation && b.probes > 0 {
+		b.probes--
+	}
+}
+
+// @brief Returns the state of the breaker, and the end of the open state
+//
+// @since v0.13.0
+func (b *Breaker) State() (state State, openUntil time.Time) {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+
+	b.advance(time.Now())
+	return b.state, b.openUntil
+}
+
+func (b *Breaker) open(now time.Time) {
+	b.setState(StateOpen)
+	b.openUntil = now.Add(b.policy.OpenDuration)
+}
+
+// advance moves an open circuit to half-open once the open duration has elapsed.
+func (b *Breaker) advance(now time.Time) {
+	if b.state == StateOpen && !now.Before(b.openUntil) {
+		b.setState(StateHalfOpen)
+	}
+}
+
+// setState changes the state, the requests admitted before no longer count.
+func (b *Breaker) setState(state State) {
+	b.state = state
+	b.probes = 0
+	b.generation++
+}
+
+// @brief Group holds the circuit breaker of every host
+//
+// @since v0.13.0
+type Group struct {
+	policy   *Policy
+	locker   *sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// @brief Creates a group of circuit breakers sharing a policy
+//
+// @param policy Policy of the breakers, DefaultPolicy() is used when it is nil. See Policy for details.
+//
+// @return Returns the Group instance.
+//
+// @since v0.13.0
+func NewGroup(policy *Policy) *Group {
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	return &Group{
+		policy:   policy,
+		locker:   &sync.Mutex{},
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// @brief Returns the circuit breaker of a host, it is created on first use
+//
+// @since v0.13.0
+func (g *Group) Get(host string) *Breaker {
+	g.locker.Lock()
+	defer g.locker.Unlock()
+
+	b, ok := g.breakers[host]
+	if !ok {
+		b = New(g.policy)
+		g.breakers[host] = b
+	}
+	return b
+}
+
+// @brief Returns the state of the circuit breaker of every host used so far
+//
+// @since v0.13.0
+func (g *Group) States() map[string]State {
+	g.locker.Lock()
+	breakers := make(map[string]*Breaker, len(g.breakers))
+	for host, b := range g.breakers {
+		breakers[host] = b
+	}
+	g.locker.Unlock()
+
+	states := make(map[string]State, len(breakers))
+	for host, b := range breakers {
+		states[host], _ = b.State()
+	}
+	return states
+}
@@ -0,0 +1,353 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+type stepKind int
+
+const (
+	stepAllow stepKind = iota
+	stepSuccess
+	stepFailure
+	stepCancel
+	// stepElapse ends the open duration
+	stepElapse
+)
+
+// step of a scenario. Record and Cancel use the admission of the last allowed request that has not completed,
+// Record lets a new request through when there is none.
+type step struct {
+	kind stepKind
+	// Result of Allow or Record
+	want bool
+	// State after the step
+	state State
+}
+
+func allow(want bool, state State) step {
+	return step{kind: stepAllow, want: want, state: state}
+}
+
+func success(state State) step {
+	return step{kind: stepSuccess, state: state}
+}
+
+func failure(opened bool, state State) step {
+	return step{kind: stepFailure, want: opened, state: state}
+}
+
+func cancel(state State) step {
+	return step{kind: stepCancel, state: state}
+}
+
+func elapse(state State) step {
+	return step{kind: stepElapse, state: state}
+}
+
+func TestBreaker(t *testing.T) {
+	threshold3 := &Policy{FailureThreshold: 3, OpenDuration: time.Hour, HalfOpenRequests: 1}
+
+	tests := []struct {
+		name   string
+		policy *Policy
+		steps  []step
+	}{
+		{
+			name:   "failures below the threshold",
+			policy: threshold3,
+			steps: []step{
+				allow(true, StateClosed), failure(false, StateClosed),
+				allow(true, StateClosed), failure(false, StateClosed),
+				allow(true, StateClosed),
+			},
+		},
+		{
+			name:   "threshold reached",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+				allow(false, StateOpen),
+			},
+		},
+		{
+			name:   "success resets the consecutive failures",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), success(StateClosed),
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+			},
+		},
+		{
+			name:   "never opens without a threshold",
+			policy: &Policy{OpenDuration: time.Hour},
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(false, StateClosed),
+				failure(false, StateClosed), failure(false, StateClosed), allow(true, StateClosed),
+			},
+		},
+		{
+			name:   "half-open after the open duration",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+				elapse(StateHalfOpen),
+			},
+		},
+		{
+			name:   "successful probe closes the circuit",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+				elapse(StateHalfOpen), allow(true, StateHalfOpen), success(StateClosed),
+				allow(true, StateClosed), allow(true, StateClosed),
+			},
+		},
+		{
+			name:   "failed probe opens the circuit again",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+				elapse(StateHalfOpen), allow(true, StateHalfOpen), failure(true, StateOpen),
+				allow(false, StateOpen),
+			},
+		},
+		{
+			name:   "one probe at a time",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+				elapse(StateHalfOpen), allow(true, StateHalfOpen), allow(false, StateHalfOpen),
+			},
+		},
+		{
+			name:   "probe limit",
+			policy: &Policy{FailureThreshold: 1, OpenDuration: time.Hour, HalfOpenRequests: 2},
+			steps: []step{
+				failure(true, StateOpen), elapse(StateHalfOpen),
+				allow(true, StateHalfOpen), allow(true, StateHalfOpen), allow(false, StateHalfOpen),
+			},
+		},
+		{
+			name:   "probe limit below 1 is 1",
+			policy: &Policy{FailureThreshold: 1, OpenDuration: time.Hour, HalfOpenRequests: 0},
+			steps: []step{
+				failure(true, StateOpen), elapse(StateHalfOpen),
+				allow(true, StateHalfOpen), allow(false, StateHalfOpen),
+			},
+		},
+		{
+			name:   "canceled probe frees its slot",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+				elapse(StateHalfOpen), allow(true, StateHalfOpen), cancel(StateHalfOpen),
+				allow(true, StateHalfOpen), allow(false, StateHalfOpen),
+			},
+		},
+		{
+			name:   "cancel does not free more slots than in flight",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+				elapse(StateHalfOpen), cancel(StateHalfOpen), cancel(StateHalfOpen),
+				allow(true, StateHalfOpen), allow(false, StateHalfOpen),
+			},
+		},
+		{
+			name:   "cancel does not close the circuit",
+			policy: threshold3,
+			steps: []step{
+				failure(false, StateClosed), failure(false, StateClosed), failure(true, StateOpen),
+				allow(false, StateOpen), cancel(StateOpen),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.policy)
+			var pending []Admission
+			// completed returns the admission of the request that completes, it lets one through if needed
+			completed := func(i int, record bool) Admission {
+				if len(pending) == 0 {
+					if !record {
+						return Admission{}
+					}
+					admission, ok := b.Allow()
+					if !ok {
+						t.Fatalf("step %d: Allow() = false, want a request let through to record its outcome", i)
+					}
+					return admission
+				}
+				admission := pending[len(pending)-1]
+				pending = pending[:len(pending)-1]
+				return admission
+			}
+			for i, s := range tt.steps {
+				switch s.kind {
+				case stepAllow:
+					admission, got := b.Allow()
+					if got != s.want {
+						t.Fatalf("step %d: Allow() = %t, want %t", i, got, s.want)
+					}
+					if got {
+						pending = append(pending, admission)
+					}
+				case stepSuccess:
+					if got := b.Record(completed(i, true), true); got {
+						t.Fatalf("step %d: Record(true) = %t, want false", i, got)
+					}
+				case stepFailure:
+					if got := b.Record(completed(i, true), false); got != s.want {
+						t.Fatalf("step %d: Record(false) = %t, want %t", i, got, s.want)
+					}
+				case stepCancel:
+					b.Cancel(completed(i, false))
+				case stepElapse:
+					b.locker.Lock()
+					b.openUntil = time.Now().Add(-time.Millisecond)
+					b.locker.Unlock()
+				}
+				if got, _ := b.State(); got != s.state {
+					t.Fatalf("step %d: State() = %s, want %s", i, got, s.state)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerStaleOutcome(t *testing.T) {
+	b := New(&Policy{FailureThreshold: 1, OpenDuration: time.Hour, HalfOpenRequests: 1})
+	elapse := func() {
+		b.locker.Lock()
+		b.openUntil = time.Now().Add(-time.Millisecond)
+		b.locker.Unlock()
+	}
+
+	// Admitted while closed, completed after the circuit went half-open
+	slowSuccess, _ := b.Allow()
+	slowFailure, _ := b.Allow()
+	failure, _ := b.Allow()
+	if !b.Record(failure, false) {
+		t.Fatal("Record(false) = false, want the circuit opened")
+	}
+	elapse()
+	probe, ok := b.Allow()
+	if !ok {
+		t.Fatal("Allow() = false, want a probe let through")
+	}
+
+	if b.Record(slowFailure, false) {
+		t.Error("Record(false) of a request admitted while closed opened the circuit")
+	}
+	b.Record(slowSuccess, true)
+	b.Cancel(slowSuccess)
+	if state, _ := b.State(); state != StateHalfOpen {
+		t.Fatalf("State() = %s, want %s", state, StateHalfOpen)
+	}
+	// The probe slot is still held
+	if _, ok = b.Allow(); ok {
+		t.Error("Allow() = true, want the probe slot held by the probe")
+	}
+
+	b.Record(probe, true)
+	if state, _ := b.State(); state != StateClosed {
+		t.Errorf("State() = %s, want %s", state, StateClosed)
+	}
+
+	// A probe completed after the circuit closed does not count either
+	b = New(&Policy{FailureThreshold: 1, OpenDuration: time.Hour, HalfOpenRequests: 2})
+	failure, _ = b.Allow()
+	b.Record(failure, false)
+	elapse()
+	first, _ := b.Allow()
+	second, _ := b.Allow()
+	b.Record(first, true)
+	if b.Record(second, false) {
+		t.Error("Record(false) of a probe completed after the circuit closed opened the circuit")
+	}
+	if state, _ := b.State(); state != StateClosed {
+		t.Errorf("State() = %s, want %s", state, StateClosed)
+	}
+}
+
+func TestBreakerOpenDuration(t *testing.T) {
+	b := New(&Policy{FailureThreshold: 1, OpenDuration: 30 * time.Millisecond})
+
+	admission, _ := b.Allow()
+	before := time.Now()
+	if !b.Record(admission, false) {
+		t.Fatal("Record(false) = false, want the circuit opened")
+	}
+	state, openUntil := b.State()
+	if state != StateOpen {
+		t.Fatalf("State() = %s, want %s", state, StateOpen)
+	}
+	if openUntil.Before(before.Add(30*time.Millisecond)) || openUntil.After(time.Now().Add(30*time.Millisecond)) {
+		t.Errorf("openUntil = %v, want 30ms after the failure", openUntil)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if state, _ = b.State(); state != StateHalfOpen {
+		t.Errorf("State() = %s, want %s", state, StateHalfOpen)
+	}
+}
+
+func TestStateString(t *testing.T) {
+	tests := []struct {
+		state State
+		want  string
+	}{
+		{state: StateClosed, want: "closed"},
+		{state: StateOpen, want: "open"},
+		{state: StateHalfOpen, want: "half-open"},
+		{state: State(-1), want: "unknown"},
+		{state: State(3), want: "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.state.String(); got != tt.want {
+			t.Errorf("State(%d).String() = %q, want %q", int(tt.state), got, tt.want)
+		}
+	}
+}
+
+func TestNewNilPolicy(t *testing.T) {
+	b := New(nil)
+	for i := 0; i < 4; i++ {
+		admission, _ := b.Allow()
+		if b.Record(admission, false) {
+			t.Fatalf("Record(false) %d opened the circuit, want it opened after 5 failures", i+1)
+		}
+	}
+	admission, _ := b.Allow()
+	if !b.Record(admission, false) {
+		t.Fatal("Record(false) 5 = false, want the circuit opened")
+	}
+}
+
+func TestGroup(t *testing.T) {
+	g := NewGroup(&Policy{FailureThreshold: 1, OpenDuration: time.Hour})
+
+	if g.Get("api.agora.io") != g.Get("api.agora.io") {
+		t.Error("Get() returned a new breaker for the same host")
+	}
+	for host, success := range map[string]bool{"api.agora.io": false, "api.sd-rtn.com": true} {
+		admission, _ := g.Get(host).Allow()
+		g.Get(host).Record(admission, success)
+	}
+
+	states := g.States()
+	want := map[string]State{
+		"api.agora.io":   StateOpen,
+		"api.sd-rtn.com": StateClosed,
+	}
+	if len(states) != len(want) {
+		t.Fatalf("States() = %v, want %v", states, want)
+	}
+	for host, state := range want {
+		if states[host] != state {
+			t.Errorf("States()[%s] = %s, want %s", host, states[host], state)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package agora
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tidwall/gjson"
+)
+
+// @brief Doer sends a request and retries it, it is implemented by the client of the agora/client package
+//
+// @since v0.13.0
+type Doer interface {
+	DoRESTWithRetry(ctx context.Context, request *Request) (*BaseResponse, error)
+}
+
+// @brief NoBody is the request body type of Call for requests without a body, and the successful response type of Call
+// for responses whose body is ignored
+//
+// @note A pointer to a NoBody value is sent as an empty JSON object.
+//
+// @since v0.13.0
+type NoBody struct{}
+
+// @brief Result of Call
+//
+// @since v0.13.0
+type Result[Succ any, Err any] struct {
+	// HTTP base response, see BaseResponse for details
+	Response *BaseResponse
+	// Successful response, set when IsSuccess returns true
+	SuccessRes Succ
+	// Error response of the service, set when IsSuccess returns false
+	ErrResponse Err
+}
+
+// @brief Determines whether the response is successful, i.e. its HTTP status code is 200
+//
+// @since v0.13.0
+func (r *Result[Succ, Err]) IsSuccess() bool {
+	return r.Response != nil && r.Response.HttpStatusCode == http.StatusOK
+}
+
+// @brief Sends a request with Doer.DoRESTWithRetry and decodes its response
+//
+// @param body Request body, no body is sent when it is nil.
+//
+// @return Returns the result holding either the decoded successful response or the decoded error response of the service.
+//
+// @return Returns an error object if the request fails, if a successful response cannot be decoded,
+// or if an unsuccessful response is not returned by the service, e.g. an empty or non-JSON body returned by a gateway.
+// The error is an *APIError wrapping a *GatewayErr in the latter case.
+//
+// @note An empty successful response body is decoded as the zero value of Succ.
+// RawBody of the response is dropped once it is decoded when Config.DiscardRawBody is set.
+//
+// @since v0.13.0
+func Call[Req any, Succ any, Err any](ctx context.Context, doer Doer, request *Request, body *Req) (*Result[Succ, Err], error) {
+	r := *request
+	if body != nil {
+		r.Body = body
+	}
+
+	responseData, err := doer.DoRESTWithRetry(ctx, &r)
+	if err != nil {
+		var internalErr *InternalErr
+		if !errors.As(err, &internalErr) {
+			// The retries of a retryable HTTP status code are exhausted
+			if responseData != nil && responseData.Err() == err && !isServiceError(responseData.RawBody, r.ErrorFields) {
+				return nil, newGatewayAPIError(r.Module, responseData)
+			}
+			return nil, err
+		}
+	}
+
+	if responseData == nil {
+		if err == nil {
+			err = fmt.Errorf("%s: no response", r.Module)
+		}
+		return nil, err
+	}
+
+	result := &Result[Succ, Err]{Response: responseData}
+	defer responseData.releaseRawBody()
+
+	if result.IsSuccess() {
+		if _, noBody := any(&result.SuccessRes).(*NoBody); noBody || len(responseData.RawBody) == 0 {
+			return result, nil
+		}
+		if err = responseData.UnmarshalToTarget(&result.SuccessRes); err != nil {
+			return nil, fmt.Errorf("decode %s response: %w", r.Module, err)
+		}
+		return result, nil
+	}
+
+	if !isServiceError(responseData.RawBody, r.ErrorFields) {
+		return nil, newGatewayAPIError(r.Module, responseData)
+	}
+	if err = responseData.UnmarshalToTarget(&result.ErrResponse); err != nil {
+		return nil, fmt.Errorf("decode %s error response: %w", r.Module, err)
+	}
+
+	return result, nil
+}
+
+// isServiceError reports whether an unsuccessful response body is an error response of the service.
+func isServiceError(body []byte, errorFields []string) bool {
+	if !json.Valid(body) {
+		return false
+	}
+	parsed := gjson.ParseBytes(body)
+	if !parsed.IsObject() {
+		return false
+	}
+	if len(errorFields) == 0 {
+		return true
+	}
+	for _, field := range errorFields {
+		if parsed.Get(field).Exists() {
+			return true
+		}
+	}
+	return false
+}
+
+// newGatewayAPIError returns the error of an unsuccessful response that is not returned by the service, but by the gateway.
+func newGatewayAPIError(module string, response *BaseResponse) error {
+	var apiErr APIError
+
+	if e, ok := response.Err().(*APIError); ok {
+		apiErr = *e
+	} else {
+		apiErr = *NewAPIError(response, module, 0)
+	}
+	apiErr.Err = NewGatewayErr(response.HttpStatusCode, string(response.RawBody))
+
+	return &apiErr
+}
@@ -0,0 +1,128 @@
+package agora
+
+import (
+	"net/http"
+	"time"
+)
+
+// @brief Options of a single API call, see CallOption
+//
+// @since v0.13.0
+type CallOptions struct {
+	// Total time of the call, see WithCallTimeout. 0 means not set.
+	Timeout time.Duration
+	// Time of each HTTP attempt of the call, see WithAttemptTimeout. 0 means not set.
+	AttemptTimeout time.Duration
+	// Maximum number of attempts, including the first one. 0 means the one of the retry policy.
+	MaxAttempts int
+	// Headers added to every HTTP attempt of the call
+	Header http.Header
+	// Region prefix the call is sent to, e.g. "api-us-west-1". Empty means the best endpoint.
+	RegionPrefix string
+	// Value of the Idempotency-Key header. Empty means no header.
+	IdempotencyKey string
+}
+
+// @brief CallOption sets an option of a single API call
+//
+// @note Every method of the service clients and of the scenarios accepts call options.
+//
+// @since v0.13.0
+type CallOption func(*CallOptions)
+
+// @brief Applies the call options
+//
+// @return Returns the options. See CallOptions for details.
+//
+// @since v0.13.0
+func NewCallOptions(opts ...CallOption) *CallOptions {
+	o := &CallOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// @brief Limits the total time of the call, including all attempts and the delays between them
+//
+// @note It overrides the call timeout set on the context by agora.WithCallTimeout.
+// The deadline of the context still applies, the earlier one of the limit and the deadline takes effect.
+//
+// @since v0.13.0
+func Timeout(timeout time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.Timeout = timeout
+	}
+}
+
+// @brief Limits the time of each HTTP attempt of the call
+//
+// @note It overrides the attempt timeout set on the context by agora.WithAttemptTimeout.
+//
+// @since v0.13.0
+func AttemptTimeout(timeout time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.AttemptTimeout = timeout
+	}
+}
+
+// @brief Sets the maximum number of attempts of the call, including the first one
+//
+// @note It takes precedence over retry.Policy.MaxAttempts. Values less than 1 are treated as 1.
+//
+// @since v0.13.0
+func MaxAttempts(attempts int) CallOption {
+	return func(o *CallOptions) {
+		if attempts < 1 {
+			attempts = 1
+		}
+		o.MaxAttempts = attempts
+	}
+}
+
+// @brief Sends the call once, without any retry
+//
+// @since v0.13.0
+func NoRetry() CallOption {
+	return MaxAttempts(1)
+}
+
+// @brief Adds a header to every HTTP attempt of the call, e.g. a tenant ID
+//
+// @note The headers set by the REST Client, e.g. Authorization, cannot be overridden.
+//
+// @since v0.13.0
+func Header(key string, value string) CallOption {
+	return func(o *CallOptions) {
+		if o.Header == nil {
+			o.Header = http.Header{}
+		}
+		o.Header.Add(key, value)
+	}
+}
+
+// @brief Sends the call to a region, instead of the best endpoint
+//
+// @param regionPrefix Region prefix of the domain area of the client, e.g. "api-us-west-1". See domain.Domain for details.
+//
+// @note It is ignored when the client uses a fixed base URL. The call fails if the region prefix is not in the domain area.
+//
+// @since v0.13.0
+func Region(regionPrefix string) CallOption {
+	return func(o *CallOptions) {
+		o.RegionPrefix = regionPrefix
+	}
+}
+
+// @brief Sends the Idempotency-Key header with every HTTP attempt of the call, e.g. for proxies that deduplicate requests
+//
+// @note The Agora services do not deduplicate requests, the call is retried according to Request.Idempotent.
+//
+// @since v0.13.0
+func IdempotencyKey(key string) CallOption {
+	return func(o *CallOptions) {
+		o.IdempotencyKey = key
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+)
+
+// callBudget is the time and attempt budget of an API call, it is shared by both retry layers.
+type callBudget struct {
+	// zero if the call has no deadline
+	deadline time.Time
+	// zero if an attempt is only limited by the deadline
+	attemptTimeout time.Duration
+	maxAttempts    int
+	attempts       int
+}
+
+// withCallTimeout applies the call timeout set by agora.WithCallTimeout to ctx.
+func withCallTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if timeout, ok := agora.CallTimeoutFromContext(ctx); ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (c *Impl) newCallBudget(ctx context.Context, options *agora.CallOptions) *callBudget {
+	b := &callBudget{
+		maxAttempts: c.retryPolicy.Attempts(),
+	}
+	if options.MaxAttempts > 0 {
+		b.maxAttempts = options.MaxAttempts
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		b.deadline = deadline
+	}
+	if timeout, ok := agora.AttemptTimeoutFromContext(ctx); ok {
+		b.attemptTimeout = timeout
+	} else if b.deadline.IsZero() {
+		b.attemptTimeout = c.timeout
+	}
+
+	return b
+}
+
+// exhausted reports whether no more attempts can be made.
+func (b *callBudget) exhausted(ctx context.Context) bool {
+	return ctx.Err() != nil || b.attempts >= b.maxAttempts
+}
+
+// fits reports whether an attempt can still be made after waiting for delay.
+func (b *callBudget) fits(delay time.Duration) bool {
+	return b.deadline.IsZero() || time.Until(b.deadline) > delay
+}
+
+// attemptContext returns the context of the next attempt.
+func (b *callBudget) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	b.attempts++
+	if b.attemptTimeout > 0 {
+		return context.WithTimeout(ctx, b.attemptTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (b *callBudget) String() string {
+	deadline := "none"
+	if !b.deadline.IsZero() {
+		deadline = time.Until(b.deadline).String()
+	}
+	attemptTimeout := "remaining time"
+	if b.attemptTimeout > 0 {
+		attemptTimeout = b.attemptTimeout.String()
+	}
+
+	return fmt.Sprintf("deadline:%s,attempt timeout:%s,max attempts:%d", deadline, attemptTimeout, b.maxAttempts)
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
+)
+
+func TestNewCallBudget(t *testing.T) {
+	c := &Impl{timeout: 10 * time.Second, retryPolicy: &retry.Policy{MaxAttempts: 3}}
+
+	tests := []struct {
+		name               string
+		ctx                func() (context.Context, context.CancelFunc)
+		options            []agora.CallOption
+		wantDeadline       bool
+		wantAttemptTimeout time.Duration
+		wantMaxAttempts    int
+	}{
+		{
+			name:               "http timeout per attempt without a deadline",
+			ctx:                func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			wantAttemptTimeout: 10 * time.Second,
+			wantMaxAttempts:    3,
+		},
+		{
+			name: "attempts limited by the deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Minute)
+			},
+			wantDeadline:    true,
+			wantMaxAttempts: 3,
+		},
+		{
+			name: "attempt timeout of the context",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				return agora.WithAttemptTimeout(ctx, time.Second), cancel
+			},
+			wantDeadline:       true,
+			wantAttemptTimeout: time.Second,
+			wantMaxAttempts:    3,
+		},
+		{
+			name:               "max attempts of the call",
+			ctx:                func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			options:            []agora.CallOption{agora.MaxAttempts(5)},
+			wantAttemptTimeout: 10 * time.Second,
+			wantMaxAttempts:    5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			b := c.newCallBudget(ctx, agora.NewCallOptions(tt.options...))
+			if !b.deadline.IsZero() != tt.wantDeadline {
+				t.Errorf("deadline = %v, want a deadline: %t", b.deadline, tt.wantDeadline)
+			}
+			if b.attemptTimeout != tt.wantAttemptTimeout || b.maxAttempts != tt.wantMaxAttempts {
+				t.Errorf("attempt timeout = %s, max attempts = %d, want %s and %d",
+					b.attemptTimeout, b.maxAttempts, tt.wantAttemptTimeout, tt.wantMaxAttempts)
+			}
+		})
+	}
+}
+
+func TestCallBudget(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &callBudget{maxAttempts: 2, deadline: time.Now().Add(time.Minute)}
+
+	if !b.fits(time.Second) || b.fits(2*time.Minute) {
+		t.Errorf("fits(1s) = %t, fits(2m) = %t, want true and false", b.fits(time.Second), b.fits(2*time.Minute))
+	}
+	if !(&callBudget{}).fits(time.Hour) {
+		t.Error("fits(1h) = false without a deadline, want true")
+	}
+
+	for i := 0; i < 2; i++ {
+		if b.exhausted(ctx) {
+			t.Fatalf("exhausted() = true after %d attempts, want false", i)
+		}
+		_, attemptCancel := b.attemptContext(ctx)
+		attemptCancel()
+	}
+	if !b.exhausted(ctx) {
+		t.Error("exhausted() = false after 2 attempts, want true")
+	}
+
+	b = &callBudget{maxAttempts: 2}
+	cancel()
+	if !b.exhausted(ctx) {
+		t.Error("exhausted() = false after the context is canceled, want true")
+	}
+}
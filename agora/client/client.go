@@ -2,15 +2,23 @@ package client
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/auth"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/breaker"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/domain"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/limit"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/utils"
@@ -18,19 +26,38 @@ import (
 
 type Client interface {
 	GetAppID() string
+	GetAppCertificate() string
+	GetDomainPoolSnapshot() domain.PoolSnapshot
+	GetLimiterStats() limit.GroupStats
+	GetCircuitBreakerStates() map[string]breaker.State
 	GetLogger() log.Logger
-	DoREST(ctx context.Context, path string, method string, requestBody interface{}) (*agora.BaseResponse, error)
+	DoREST(ctx context.Context, path string, method string, requestBody interface{}, opts ...agora.CallOption) (*agora.BaseResponse, error)
+	DoRESTWithRetry(ctx context.Context, request *agora.Request) (*agora.BaseResponse, error)
+	Close() error
 }
 
 type Impl struct {
-	appID      string
-	httpClient *http.Client
-	timeout    time.Duration
-	logger     log.Logger
-	credential auth.Credential
+	appID              string
+	appCertificate     string
+	httpClient         *http.Client
+	timeout            time.Duration
+	logger             log.Logger
+	credentialProvider auth.CredentialProvider
 
-	module     string
-	domainPool *domain.Pool
+	retryPolicy     *retry.Policy
+	instrumentation agora.Instrumentation
+	hooks           []agora.Hook
+
+	maxBodySize    int64
+	enableGzip     bool
+	discardRawBody bool
+
+	module         string
+	domainPool     *domain.Pool
+	ownsDomainPool bool
+	fixedBaseURL   bool
+	limiter        *limit.Group
+	breakers       *breaker.Group
 }
 
 func (c *Impl) GetLogger() log.Logger {
@@ -41,144 +68,699 @@ var _ Client = (*Impl)(nil)
 
 const defaultHttpTimeout = 10 * time.Second
 
-func New(config *agora.Config) (*Impl, error) {
+// Option of New
+type Option func(*newOptions)
+
+type newOptions struct {
+	domainPool *domain.Pool
+}
+
+// WithDomainPool makes the client send requests through a domain pool shared with other clients,
+// see NewDomainPool. The shared pool is not closed by Close.
+func WithDomainPool(pool *domain.Pool) Option {
+	return func(o *newOptions) {
+		o.domainPool = pool
+	}
+}
+
+// NewDomainPool creates the domain pool configured by config.
+func NewDomainPool(config *agora.Config) (*domain.Pool, error) {
+	logger := config.Logger
+	if logger == nil {
+		logger = log.DefaultLogger
+	}
+
+	var poolOptions []domain.PoolOption
+	if config.Resolver != nil {
+		poolOptions = append(poolOptions, domain.WithResolver(config.Resolver))
+	}
+	if config.Domain != nil {
+		poolOptions = append(poolOptions, domain.WithDomain(*config.Domain))
+	}
+	if config.BaseURL != "" {
+		poolOptions = append(poolOptions, domain.WithBaseURL(config.BaseURL))
+	}
+	if config.HealthPolicy != nil {
+		poolOptions = append(poolOptions, domain.WithHealthPolicy(config.HealthPolicy))
+	}
+	if config.DomainRefreshInterval > 0 {
+		poolOptions = append(poolOptions, domain.WithBackgroundRefresh(config.DomainRefreshInterval))
+	}
+	if config.OnDomainResolveError != nil {
+		poolOptions = append(poolOptions, domain.WithResolveErrorHandler(config.OnDomainResolveError))
+	}
+
+	return domain.NewPool(config.DomainArea, logger, poolOptions...)
+}
+
+func New(config *agora.Config, options ...Option) (*Impl, error) {
+	var o newOptions
+	for _, option := range options {
+		option(&o)
+	}
+
 	if config.HttpTimeout == 0 {
 		config.HttpTimeout = defaultHttpTimeout
 	}
-	cc := &http.Client{
-		Timeout: config.HttpTimeout,
-	}
+	cc := newHttpClient(config)
 	if config.Logger == nil {
 		config.Logger = log.DefaultLogger
 	}
+	if config.RetryPolicy == nil {
+		config.RetryPolicy = retry.DefaultPolicy()
+	}
+	if config.Instrumentation == nil {
+		config.Instrumentation = agora.NopInstrumentation()
+	}
 
-	domainPool, err := domain.NewPool(config.DomainArea, config.Logger)
-	if err != nil {
-		return nil, err
+	domainPool, ownsDomainPool := o.domainPool, false
+	if domainPool == nil {
+		var err error
+		if domainPool, err = NewDomainPool(config); err != nil {
+			return nil, err
+		}
+		ownsDomainPool = true
 	}
 
 	return &Impl{
-		appID:      config.AppID,
-		credential: config.Credential,
-		httpClient: cc,
-		timeout:    config.HttpTimeout,
-		logger:     config.Logger,
-		module:     "http client",
-		domainPool: domainPool,
+		appID:              config.AppID,
+		appCertificate:     config.AppCertificate,
+		credentialProvider: credentialProvider(config),
+		httpClient:         cc,
+		timeout:            config.HttpTimeout,
+		logger:             config.Logger,
+		retryPolicy:        config.RetryPolicy,
+		instrumentation:    config.Instrumentation,
+		hooks:              config.Hooks,
+		maxBodySize:        config.MaxResponseBodySize,
+		enableGzip:         config.EnableGzip,
+		discardRawBody:     config.DiscardRawBody,
+		module:             "http client",
+		domainPool:         domainPool,
+		ownsDomainPool:     ownsDomainPool,
+		fixedBaseURL:       config.BaseURL != "",
+		limiter:            limit.NewGroup(config.RateLimit),
+		breakers:           breaker.NewGroup(config.CircuitBreaker),
 	}, nil
 }
 
-func (c *Impl) marshalBody(body interface{}) (io.Reader, error) {
+// newHttpClient returns a copy of the configured HTTP client, or a new one,
+// whose transport is wrapped by the configured middlewares.
+func newHttpClient(config *agora.Config) *http.Client {
+	var cc http.Client
+	if config.HttpClient != nil {
+		cc = *config.HttpClient
+	} else {
+		// The timeout of each attempt is set on its context, see callBudget
+		cc.Transport = config.Transport
+	}
+	cc.Transport = agora.ChainMiddlewares(cc.Transport, config.Middlewares...)
+
+	return &cc
+}
+
+func (c *Impl) marshalBody(body interface{}) ([]byte, error) {
 	if utils.IsNil(body) {
 		return nil, nil
 	}
 
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
+	return json.Marshal(body)
+}
+
+// redactedBody returns the body for debug logs, the values of sensitive fields are masked, see log.RedactJSON.
+func (c *Impl) redactedBody(body []byte) string {
+	if c.logger.Level() > log.DebugLevel {
+		return ""
 	}
+	return string(log.RedactJSON(body))
+}
+
+// DoREST sends an idempotent request, network errors are retried according to the retry policy.
+//
+// The call is limited by the deadline of ctx and the timeouts set by agora.WithCallTimeout and agora.WithAttemptTimeout,
+// or by the ones set by opts. See agora.CallOption for details.
+func (c *Impl) DoREST(ctx context.Context, path string,
+	method string, requestBody interface{}, opts ...agora.CallOption,
+) (resp *agora.BaseResponse, err error) {
+	options := agora.NewCallOptions(opts...)
+	ctx, cancel := withCallTimeout(withCallOptions(ctx, options))
+	defer cancel()
+
+	ctx, call := c.instrumentation.StartCall(ctx, agora.CallInfo{
+		Module: c.module,
+		Method: method,
+		Path:   path,
+	})
+	defer func() {
+		call.End(resp, err)
+	}()
 
-	c.logger.Debugf(context.Background(), c.module, "http request body:%s", jsonBody)
+	budget := c.newCallBudget(ctx, options)
+	c.logger.Debugf(ctx, c.module, "call budget:%s", budget)
 
-	return bytes.NewReader(jsonBody), nil
+	return c.doREST(ctx, &agora.Request{
+		Module:     c.module,
+		Method:     method,
+		Path:       path,
+		Body:       requestBody,
+		Idempotent: true,
+	}, options, budget, call)
 }
 
-const doRESTTimeout = 10 * time.Second
+// withCallOptions applies the timeouts set by the call options to ctx, they take precedence over the ones set on ctx.
+func withCallOptions(ctx context.Context, options *agora.CallOptions) context.Context {
+	if options.Timeout > 0 {
+		ctx = agora.WithCallTimeout(ctx, options.Timeout)
+	}
+	if options.AttemptTimeout > 0 {
+		ctx = agora.WithAttemptTimeout(ctx, options.AttemptTimeout)
+	}
+	return ctx
+}
 
-func (c *Impl) DoREST(ctx context.Context, path string,
-	method string, requestBody interface{},
+// inRegion reports whether a call forced to the region prefix can be sent to the base URL.
+// Any base URL is allowed when no region is forced, or when the pool uses a fixed base URL.
+func (c *Impl) inRegion(baseURL string, regionPrefix string) bool {
+	if regionPrefix == "" || c.fixedBaseURL {
+		return true
+	}
+	return strings.HasPrefix(baseURL, "https://"+regionPrefix+".")
+}
+
+// hostOf returns the host of a base URL, it is the key of the circuit breakers.
+func hostOf(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return baseURL
+}
+
+// circuitOpenErr returns the error of a request not sent because the circuits of all the endpoints are open.
+func (c *Impl) circuitOpenErr() error {
+	baseURL, _, _ := c.domainPool.Current()
+	host := hostOf(baseURL)
+	_, openUntil := c.breakers.Get(host).State()
+
+	return &agora.ErrCircuitOpen{Host: host, OpenUntil: openUntil}
+}
+
+// requestNotSent reports whether err proves that the request never reached the server.
+func requestNotSent(err error) bool {
+	var circuitErr *agora.ErrCircuitOpen
+	if errors.As(err, &circuitErr) {
+		return true
+	}
+	var rejected *hookErr
+	if errors.As(err, &rejected) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial" || opErr.Op == "proxyconnect"
+	}
+	return false
+}
+
+// doREST sends the request and retries network errors, each attempt consumes the budget.
+func (c *Impl) doREST(ctx context.Context, request *agora.Request, options *agora.CallOptions, budget *callBudget,
+	call agora.CallObserver,
 ) (*agora.BaseResponse, error) {
 	var (
-		err  error
-		resp *http.Response
-		req  *http.Request
+		err           error
+		resp          *http.Response
+		req           *http.Request
+		attemptCancel context.CancelFunc = func() {}
+		// releases the limiter slot of the attempt in flight
+		release func() = func() {}
+		// context and metadata of the attempt in flight, for the hooks
+		hookCtx  context.Context
+		hookInfo agora.HookInfo
 	)
-	timeoutCtx, cancel := context.WithTimeout(ctx, doRESTTimeout)
-	defer cancel()
+	defer func() {
+		attemptCancel()
+		release()
+	}()
 
-	if err = c.domainPool.SelectBestDomain(timeoutCtx); err != nil {
+	if err = c.domainPool.SelectBestDomain(ctx); err != nil {
 		return nil, err
 	}
 
+	endpoint := limit.EndpointName(request.Module)
+
 	doHttpRequest := func() error {
-		req, err = c.createRequest(timeoutCtx, path, method, requestBody)
+		var attemptCtx context.Context
+
+		// Waiting for the limiter is bounded by the call, not by the attempt timeout
+		release, err = c.limiter.Wait(ctx, endpoint)
+		if err != nil {
+			release = func() {}
+			return agora.NewRetryErr(false, err)
+		}
+
+		// The best endpoint whose circuit is not open, the next ones take over while it is open
+		var (
+			circuit   *breaker.Breaker
+			admission breaker.Admission
+		)
+		baseURL, regionPrefix, domainSuffix, ok := c.domainPool.Select(func(baseURL string) bool {
+			if !c.inRegion(baseURL, options.RegionPrefix) {
+				return false
+			}
+			b := c.breakers.Get(hostOf(baseURL))
+			a, allowed := b.Allow()
+			if !allowed {
+				return false
+			}
+			circuit, admission = b, a
+			return true
+		})
+		if !ok {
+			release()
+			release = func() {}
+			err = c.circuitOpenErr()
+			if options.RegionPrefix != "" && !c.domainPool.HasRegionPrefix(options.RegionPrefix) {
+				err = fmt.Errorf("region prefix %s is not in the domain area", options.RegionPrefix)
+			}
+			c.logger.Debugf(ctx, request.Module, "fail fast,err:%s", err)
+			return agora.NewRetryErr(false, err)
+		}
+
+		// The context of an attempt must stay alive until its response body is read
+		attemptCancel()
+		attemptCtx, attemptCancel = budget.attemptContext(ctx)
+
+		attemptInfo := agora.AttemptInfo{Attempt: budget.attempts, RegionPrefix: regionPrefix, DomainSuffix: domainSuffix}
+		attemptCtx, attempt := call.StartAttempt(attemptCtx, attemptInfo.Attempt)
+
+		req, err = c.createRequest(attemptCtx, request.Module, baseURL, request.Path, request.Method, request.Body)
 		if err != nil {
+			attempt.End(attemptInfo, err)
+			circuit.Cancel(admission)
+			release()
+			release = func() {}
 			return err
 		}
 
 		req.Header.Add("User-Agent", agora.BuildUserAgent())
+		if c.enableGzip {
+			req.Header.Set("Accept-Encoding", "gzip")
+		}
+		for key, values := range options.Header {
+			if req.Header.Get(key) != "" {
+				continue
+			}
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+		if options.IdempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", options.IdempotencyKey)
+		}
+
+		hookCtx = attemptCtx
+		hookInfo = agora.HookInfo{
+			CallInfo:   agora.CallInfo{Module: request.Module, Method: request.Method, Path: request.Path},
+			Attempt:    attemptInfo.Attempt,
+			Idempotent: request.Idempotent,
+		}
+		if err = c.beforeRequest(hookCtx, hookInfo, req); err != nil {
+			attempt.End(attemptInfo, err)
+			circuit.Cancel(admission)
+			release()
+			release = func() {}
+			return agora.NewRetryErr(false, err)
+		}
+
+		start := time.Now()
 		resp, err = c.httpClient.Do(req)
+		if ctx.Err() == nil {
+			// A call canceled by the caller says nothing about the health of the endpoint
+			success := err == nil && resp.StatusCode < http.StatusInternalServerError
+			c.domainPool.ReportResult(regionPrefix, domainSuffix, time.Since(start), success)
+			if circuit.Record(admission, success) {
+				c.logger.Warnf(ctx, c.module, "circuit breaker of %s is open", hostOf(baseURL))
+			}
+		} else {
+			circuit.Cancel(admission)
+		}
+		if resp != nil {
+			attemptInfo.HttpStatusCode = resp.StatusCode
+			attemptInfo.RequestID = resp.Header.Get("X-Request-Id")
+		}
+		attempt.End(attemptInfo, err)
+		if err != nil {
+			c.afterResponse(hookCtx, hookInfo, nil, err)
+			release()
+			release = func() {}
+		}
+		if err != nil && !request.Idempotent && !requestNotSent(err) {
+			c.logger.Debugf(ctx, request.Module, "non-idempotent request may have reached the server, no retry,err:%s", err)
+			return agora.NewRetryErr(false, err)
+		}
 		return err
 	}
-	err = retry.Do(
+	err = retry.DoWithContext(
+		ctx,
 		func(retryCount int) error {
-			c.logger.Debugf(timeoutCtx, c.module, "http retry attempt:%d", retryCount)
+			c.logger.Debugf(ctx, c.module, "http retry attempt:%d", retryCount)
 			return doHttpRequest()
 		},
 		func() bool {
-			select {
-			case <-timeoutCtx.Done():
-				return true
-			default:
-			}
-			return false
+			return budget.exhausted(ctx)
 		},
 		func(i int) time.Duration {
-			if i == 0 {
-				return 0 * time.Second
-			}
-			return 500 * time.Millisecond
+			return c.retryPolicy.Backoff(i)
 		},
 		func(err error) {
-			c.logger.Debugf(timeoutCtx, c.module, "http request err:%s", err)
-			c.domainPool.NextRegion()
+			c.logger.Debugf(ctx, c.module, "http request err:%s", err)
 		},
 	)
 	if err != nil {
+		var retryErr *agora.RetryErr
+		if errors.As(err, &retryErr) {
+			err = retryErr.Unwrap()
+		}
+		return nil, err
+	}
+	if resp == nil {
+		// The budget was exhausted before the first attempt
+		if err = ctx.Err(); err == nil {
+			err = errors.New("no attempt left in the call budget")
+		}
 		return nil, err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.readBody(request.Module, resp)
 	if err != nil {
+		c.afterResponse(hookCtx, hookInfo, nil, err)
 		return nil, err
 	}
-	c.logger.Debugf(ctx, c.module, "http response:%s", body)
+	log.Log(ctx, c.logger, log.DebugLevel, request.Module, "http response",
+		log.Int("status_code", resp.StatusCode),
+		log.String("request_id", resp.Header.Get("X-Request-Id")),
+		log.String("body", c.redactedBody(body)),
+	)
 
-	return &agora.BaseResponse{
+	baseResponse := &agora.BaseResponse{
 		RawResponse:    resp,
 		RawBody:        body,
 		HttpStatusCode: resp.StatusCode,
-	}, nil
+	}
+	baseResponse.SetDiscardRawBody(c.discardRawBody)
+	c.afterResponse(hookCtx, hookInfo, baseResponse, nil)
+
+	return baseResponse, nil
+}
+
+// readBody reads the response body, decompressing it if needed, up to the maximum response body size.
+func (c *Impl) readBody(module string, resp *http.Response) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	if c.enableGzip && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+		reader = gzipReader
+	}
+	if c.maxBodySize <= 0 {
+		return io.ReadAll(reader)
+	}
+
+	// One more byte tells a body of exactly the maximum size from a larger one
+	body, err := io.ReadAll(io.LimitReader(reader, c.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxBodySize {
+		return nil, &agora.ErrResponseTooLarge{Module: module, Limit: c.maxBodySize}
+	}
+	return body, nil
+}
+
+// hookErr is the error of a request rejected by a hook before it was sent.
+type hookErr struct {
+	err error
+}
+
+func (h *hookErr) Error() string {
+	return "before request hook: " + h.err.Error()
+}
+
+func (h *hookErr) Unwrap() error {
+	return h.err
+}
+
+func (c *Impl) beforeRequest(ctx context.Context, info agora.HookInfo, req *http.Request) error {
+	for _, hook := range c.hooks {
+		if hook == nil {
+			continue
+		}
+		if err := hook.BeforeRequest(ctx, info, req); err != nil {
+			return &hookErr{err: err}
+		}
+	}
+	return nil
+}
+
+func (c *Impl) afterResponse(ctx context.Context, info agora.HookInfo, resp *agora.BaseResponse, err error) {
+	for _, hook := range c.hooks {
+		if hook != nil {
+			hook.AfterResponse(ctx, info, resp, err)
+		}
+	}
+}
+
+// DoRESTWithRetry sends the request and retries it according to the retry policy
+// when the response has a retryable HTTP status code.
+//
+// A non-idempotent request is only retried after request.Reconcile reports that the previous attempt did not take effect.
+//
+// The attempts of both retry layers share one budget: at most retry.Policy.Attempts() attempts are made,
+// limited by the deadline of ctx and the timeouts set by agora.WithCallTimeout and agora.WithAttemptTimeout.
+// The call is not retried when the delay before the next attempt, e.g. given by a Retry-After header, exceeds its deadline.
+//
+// A response with a non-retryable and non-successful HTTP status code is returned along with an agora.InternalErr,
+// its agora.APIError is available through agora.BaseResponse.Err.
+// An agora.APIError is returned when the retries of a retryable HTTP status code are exhausted.
+func (c *Impl) DoRESTWithRetry(ctx context.Context, request *agora.Request) (*agora.BaseResponse, error) {
+	var (
+		resp      *agora.BaseResponse
+		err       error
+		lastErr   error
+		reconcile bool
+		// retry count of the last attempt, -1 before the first one, and the delay before the next attempt
+		lastRetry = -1
+		delay     time.Duration
+	)
+
+	options := agora.NewCallOptions(request.Options...)
+	ctx, cancel := withCallTimeout(withCallOptions(ctx, options))
+	defer cancel()
+
+	ctx, call := c.instrumentation.StartCall(ctx, agora.CallInfo{
+		Module: request.Module,
+		Method: request.Method,
+		Path:   request.Path,
+	})
+	defer func() {
+		call.End(resp, err)
+	}()
+
+	module := request.Module
+	budget := c.newCallBudget(ctx, options)
+	c.logger.Debugf(ctx, module, "call budget:%s", budget)
+
+	// unknownOutcome decides whether a non-idempotent request whose outcome is unknown can be retried.
+	unknownOutcome := func(err error) error {
+		if request.Reconcile == nil {
+			c.logger.Debugf(ctx, module, "non-idempotent request may have taken effect, no retry,err:%s", err)
+			return agora.NewRetryErr(false, err)
+		}
+		reconcile = true
+		return err
+	}
+
+	err = retry.DoWithContext(ctx, func(retryCount int) error {
+		lastRetry = retryCount
+		if reconcile {
+			reconcile = false
+			applied, reconcileErr := request.Reconcile(ctx)
+			if reconcileErr != nil {
+				c.logger.Debugf(ctx, module, "reconcile failed, no retry,err:%s", reconcileErr)
+				return agora.NewRetryErr(false, lastErr)
+			}
+			if applied {
+				c.logger.Debugf(ctx, module, "reconcile shows the request has taken effect, no retry")
+				return agora.NewRetryErr(false, lastErr)
+			}
+		}
+
+		var doErr error
+
+		resp, doErr = c.doREST(ctx, request, options, budget, call)
+		if doErr != nil {
+			if request.Idempotent || requestNotSent(doErr) {
+				return agora.NewRetryErr(false, doErr)
+			}
+			lastErr = doErr
+			return unknownOutcome(doErr)
+		}
+
+		statusCode := resp.HttpStatusCode
+		if statusCode == http.StatusOK || statusCode == http.StatusCreated {
+			return nil
+		}
+
+		apiErr := agora.NewAPIError(resp, module, budget.attempts)
+		if request.ErrorKind != nil {
+			apiErr.Kind = request.ErrorKind(apiErr)
+		}
+		resp.SetErr(apiErr)
+		retryable, decided := false, false
+		if request.ShouldRetry != nil {
+			retryable, decided = request.ShouldRetry(apiErr)
+		}
+		if !decided {
+			retryable = c.retryPolicy.IsRetryableStatusCode(statusCode)
+		}
+		if retryable {
+			c.logger.Debugf(ctx, module, "http status code is %d, retry,http response:%s", statusCode, c.redactedBody(resp.RawBody))
+			lastErr = apiErr
+			if request.Idempotent {
+				return lastErr
+			}
+			return unknownOutcome(lastErr)
+		}
+
+		c.logger.Debugf(ctx, module, "http status code is %d, no retry,http response:%s", statusCode, c.redactedBody(resp.RawBody))
+		return agora.NewRetryErr(
+			false,
+			agora.NewInternalErr(fmt.Sprintf("http status code is %d, no retry,http response:%s", statusCode, log.RedactJSON(resp.RawBody))),
+		)
+	}, func() bool {
+		if budget.exhausted(ctx) {
+			return true
+		}
+		if lastRetry < 0 {
+			return false
+		}
+		var header http.Header
+		if resp != nil && resp.RawResponse != nil {
+			header = resp.RawResponse.Header
+		}
+		delay = c.retryPolicy.Delay(lastRetry, header)
+		if !budget.fits(delay) {
+			c.logger.Debugf(ctx, module, "retry delay %s exceeds the call deadline, no retry", delay)
+			return true
+		}
+		return false
+	}, func(int) time.Duration {
+		return delay
+	}, func(err error) {
+		c.logger.Debugf(ctx, module, "http request err:%s,attempts:%d,call budget:%s", err, budget.attempts, budget)
+	})
+	if err == nil && resp == nil {
+		// ctx was done before the first attempt
+		err = ctx.Err()
+	}
+
+	return resp, err
 }
 
-func (c *Impl) addCredential(req *http.Request) {
-	if c.credential != nil {
-		c.credential.SetAuth(req)
+// credentialProvider returns the configured credential provider, or a provider of the configured credential.
+func credentialProvider(config *agora.Config) auth.CredentialProvider {
+	if config.CredentialProvider != nil {
+		return config.CredentialProvider
 	}
+	if config.Credential != nil {
+		return auth.NewStaticCredentialProvider(config.Credential)
+	}
+	return nil
+}
+
+func (c *Impl) addCredential(req *http.Request) error {
+	if c.credentialProvider == nil {
+		return nil
+	}
+	credential, err := c.credentialProvider.Credential(req.Context())
+	if err != nil {
+		return err
+	}
+	if credential != nil {
+		credential.SetAuth(req)
+	}
+	return nil
 }
 
 func (c *Impl) GetAppID() string {
 	return c.appID
 }
 
-func (c *Impl) createRequest(ctx context.Context, path string,
+func (c *Impl) GetAppCertificate() string {
+	return c.appCertificate
+}
+
+// GetDomainPoolSnapshot returns the endpoint health state of the domain pool, see domain.Pool.Snapshot.
+func (c *Impl) GetDomainPoolSnapshot() domain.PoolSnapshot {
+	return c.domainPool.Snapshot()
+}
+
+// GetLimiterStats returns the statistics of the rate and concurrency limiters, see limit.Group.Stats.
+func (c *Impl) GetLimiterStats() limit.GroupStats {
+	return c.limiter.Stats()
+}
+
+// GetCircuitBreakerStates returns the state of the circuit breaker of every host used so far, see breaker.Group.States.
+func (c *Impl) GetCircuitBreakerStates() map[string]breaker.State {
+	return c.breakers.States()
+}
+
+// Close stops the background domain refresh of the domain pool, unless the pool is shared, see WithDomainPool,
+// and closes the idle connections of the HTTP client.
+func (c *Impl) Close() error {
+	c.httpClient.CloseIdleConnections()
+	if !c.ownsDomainPool {
+		return nil
+	}
+	return c.domainPool.Close()
+}
+
+func (c *Impl) createRequest(ctx context.Context, module string, baseURL string, path string,
 	method string, requestBody interface{},
 ) (*http.Request, error) {
-	url := c.domainPool.GetCurrentUrl() + path
+	url := baseURL + path
 
-	c.logger.Debugf(ctx, "create request url:%s", url)
 	jsonBody, err := c.marshalBody(requestBody)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, method, url, jsonBody)
+	log.Log(ctx, c.logger, log.DebugLevel, module, "http request",
+		log.String("method", method),
+		log.String("url", url),
+		log.String("body", c.redactedBody(jsonBody)),
+	)
+
+	var body io.Reader
+	if jsonBody != nil {
+		body = bytes.NewReader(jsonBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
-	c.addCredential(req)
+	if err = c.addCredential(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", "application/json;charset=utf-8")
 
 	return req, nil
@@ -0,0 +1,1195 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/auth"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
+)
+
+const (
+	testAppID    = "appid"
+	testUsername = "customer"
+	testPassword = "secret"
+)
+
+// newTestConfig returns a configuration that sends requests to baseURL, with short retry delays.
+func newTestConfig(baseURL string) *agora.Config {
+	return &agora.Config{
+		AppID:      testAppID,
+		BaseURL:    baseURL,
+		Credential: auth.NewBasicAuthCredential(testUsername, testPassword),
+		Logger:     log.DiscardLogger,
+		RetryPolicy: &retry.Policy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+		},
+	}
+}
+
+// newTestClient returns a client sending requests to a test server run by handler.
+//
+// configure modifies the configuration before the client is created, it may be nil.
+func newTestClient(t *testing.T, handler http.Handler, configure func(config *agora.Config)) *Impl {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := newTestConfig(server.URL)
+	if configure != nil {
+		configure(config)
+	}
+	c, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	return c
+}
+
+// statusHandler responds to the n-th request, starting at 0, with statuses[n] and bodies[n],
+// the last status and body are repeated. It counts the requests.
+type statusHandler struct {
+	statuses []int
+	bodies   []string
+	header   http.Header
+
+	requests int32
+}
+
+func (h *statusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := int(atomic.AddInt32(&h.requests, 1)) - 1
+
+	for key, values := range h.header {
+		w.Header()[key] = values
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", "request-"+strconv.Itoa(n+1))
+	w.WriteHeader(h.statuses[minIndex(n, len(h.statuses))])
+	if len(h.bodies) > 0 {
+		_, _ = w.Write([]byte(h.bodies[minIndex(n, len(h.bodies))]))
+	}
+}
+
+func (h *statusHandler) count() int {
+	return int(atomic.LoadInt32(&h.requests))
+}
+
+func minIndex(n int, length int) int {
+	if n >= length {
+		return length - 1
+	}
+	return n
+}
+
+// recordingTransport records the requests sent through it before passing them to the default transport.
+type recordingTransport struct {
+	locker   sync.Mutex
+	requests []*http.Request
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.locker.Lock()
+	r.requests = append(r.requests, req)
+	r.locker.Unlock()
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (r *recordingTransport) count() int {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+
+	return len(r.requests)
+}
+
+func TestDoRESTRequest(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}), nil)
+
+	resp, err := c.DoREST(context.Background(), "/v1/path?x=1", http.MethodPost, map[string]string{"cname": "channel"})
+	if err != nil {
+		t.Fatalf("DoREST() error = %v", err)
+	}
+	if resp.HttpStatusCode != http.StatusOK || string(resp.RawBody) != `{"ok":true}` {
+		t.Errorf("response = %d %s, want 200 {\"ok\":true}", resp.HttpStatusCode, resp.RawBody)
+	}
+
+	if got.Method != http.MethodPost || got.URL.Path != "/v1/path" || got.URL.RawQuery != "x=1" {
+		t.Errorf("request = %s %s, want POST /v1/path?x=1", got.Method, got.URL)
+	}
+	if gotBody != `{"cname":"channel"}` {
+		t.Errorf("body = %s, want {\"cname\":\"channel\"}", gotBody)
+	}
+	if username, password, ok := got.BasicAuth(); !ok || username != testUsername || password != testPassword {
+		t.Errorf("basic auth = %s:%s %t, want %s:%s", username, password, ok, testUsername, testPassword)
+	}
+	if contentType := got.Header.Get("Content-Type"); contentType != "application/json;charset=utf-8" {
+		t.Errorf("Content-Type = %s, want application/json;charset=utf-8", contentType)
+	}
+	if userAgent := got.Header.Get("User-Agent"); userAgent != agora.BuildUserAgent() {
+		t.Errorf("User-Agent = %s, want %s", userAgent, agora.BuildUserAgent())
+	}
+}
+
+func TestInjectedHttpClient(t *testing.T) {
+	transport := &recordingTransport{}
+	injected := &http.Client{Transport: transport}
+	c := newTestClient(t, &statusHandler{statuses: []int{http.StatusOK}}, func(config *agora.Config) {
+		config.HttpClient = injected
+		// Ignored when HttpClient is set
+		config.Transport = agora.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			t.Error("Config.Transport used while Config.HttpClient is set")
+			return http.DefaultTransport.RoundTrip(r)
+		})
+	})
+
+	if c.httpClient == injected {
+		t.Error("the injected client is used as it is, want a copy")
+	}
+	if _, err := c.DoREST(context.Background(), "/", http.MethodGet, nil); err != nil {
+		t.Fatalf("DoREST() error = %v", err)
+	}
+	if transport.count() != 1 {
+		t.Errorf("requests through the injected client = %d, want 1", transport.count())
+	}
+	if injected.Transport != transport {
+		t.Error("the transport of the injected client is modified")
+	}
+}
+
+func TestTransport(t *testing.T) {
+	handler := &statusHandler{statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	transport := &recordingTransport{}
+	c := newTestClient(t, handler, func(config *agora.Config) {
+		config.Transport = transport
+	})
+
+	if _, err := c.DoRESTWithRetry(context.Background(), &agora.Request{
+		Module:     "test:get",
+		Method:     http.MethodGet,
+		Path:       "/",
+		Idempotent: true,
+	}); err != nil {
+		t.Fatalf("DoRESTWithRetry() error = %v", err)
+	}
+	// Every attempt goes through the transport
+	if transport.count() != 2 || handler.count() != 2 {
+		t.Errorf("requests through the transport = %d, received = %d, want 2 and 2", transport.count(), handler.count())
+	}
+}
+
+func TestMiddlewares(t *testing.T) {
+	var (
+		locker sync.Mutex
+		calls  []string
+	)
+	record := func(call string) {
+		locker.Lock()
+		calls = append(calls, call)
+		locker.Unlock()
+	}
+	middleware := func(name string) agora.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return agora.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				record(name + " request")
+				r.Header.Add("X-Middlewares", name)
+				resp, err := next.RoundTrip(r)
+				record(name + " response")
+				return resp, err
+			})
+		}
+	}
+
+	var headers []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Values("X-Middlewares")
+	}), func(config *agora.Config) {
+		config.Middlewares = []agora.Middleware{middleware("outer"), nil, middleware("inner")}
+	})
+
+	if _, err := c.DoREST(context.Background(), "/", http.MethodGet, nil); err != nil {
+		t.Fatalf("DoREST() error = %v", err)
+	}
+
+	want := []string{"outer request", "inner request", "inner response", "outer response"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+	if len(headers) != 2 || headers[0] != "outer" || headers[1] != "inner" {
+		t.Errorf("X-Middlewares = %v, want [outer inner]", headers)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	handler := &statusHandler{statuses: []int{http.StatusOK}}
+	c := newTestClient(t, handler, func(config *agora.Config) {
+		config.Middlewares = []agora.Middleware{
+			func(next http.RoundTripper) http.RoundTripper {
+				return agora.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+					rec := httptest.NewRecorder()
+					rec.WriteHeader(http.StatusAccepted)
+					_, _ = rec.WriteString(`{"cached":true}`)
+					return rec.Result(), nil
+				})
+			},
+		}
+	})
+
+	resp, err := c.DoREST(context.Background(), "/", http.MethodGet, nil)
+	if err != nil {
+		t.Fatalf("DoREST() error = %v", err)
+	}
+	if resp.HttpStatusCode != http.StatusAccepted || string(resp.RawBody) != `{"cached":true}` {
+		t.Errorf("response = %d %s, want the one of the middleware", resp.HttpStatusCode, resp.RawBody)
+	}
+	if handler.count() != 0 {
+		t.Errorf("requests received = %d, want 0", handler.count())
+	}
+}
+
+func TestRetryAfterBeyondDeadline(t *testing.T) {
+	handler := &statusHandler{
+		statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+		header:   http.Header{"Retry-After": []string{"60"}},
+	}
+	c := newTestClient(t, handler, func(config *agora.Config) {
+		config.RetryPolicy = &retry.Policy{
+			MaxAttempts:          3,
+			InitialBackoff:       time.Millisecond,
+			HonorRetryAfter:      true,
+			MaxRetryAfter:        time.Minute,
+			RetryableStatusCodes: []int{http.StatusTooManyRequests},
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	resp, err := c.DoRESTWithRetry(ctx, &agora.Request{
+		Module:     "test:get",
+		Method:     http.MethodGet,
+		Path:       "/",
+		Idempotent: true,
+	})
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("DoRESTWithRetry() took %s, want it to fail fast", elapsed)
+	}
+	var apiErr *agora.APIError
+	if !errors.As(err, &apiErr) || apiErr.HttpStatusCode != http.StatusTooManyRequests {
+		t.Fatalf("DoRESTWithRetry() error = %v, want the APIError of the 429 response", err)
+	}
+	if resp == nil || resp.HttpStatusCode != http.StatusTooManyRequests {
+		t.Errorf("response = %v, want the 429 response", resp)
+	}
+	if handler.count() != 1 {
+		t.Errorf("requests received = %d, want 1", handler.count())
+	}
+}
+
+// closeConnection makes the client fail with a network error.
+func closeConnection(t *testing.T, w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Errorf("Hijack() error = %v", err)
+		return
+	}
+	_ = conn.Close()
+}
+
+// blockUntil blocks the handler until the request is canceled or release is closed.
+func blockUntil(r *http.Request, release <-chan struct{}) {
+	select {
+	case <-r.Context().Done():
+	case <-release:
+	}
+}
+
+func TestSharedRetryBudget(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		wantStatus   int
+		wantRequests int32
+	}{
+		{name: "enough attempts", maxAttempts: 3, wantStatus: http.StatusOK, wantRequests: 3},
+		{name: "network and status retries share the attempts", maxAttempts: 2, wantStatus: http.StatusServiceUnavailable, wantRequests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch atomic.AddInt32(&requests, 1) {
+				case 1:
+					closeConnection(t, w)
+				case 2:
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}), func(config *agora.Config) {
+				config.RetryPolicy.MaxAttempts = tt.maxAttempts
+			})
+
+			resp, _ := c.DoRESTWithRetry(context.Background(), &agora.Request{
+				Module:     "test:get",
+				Method:     http.MethodGet,
+				Path:       "/",
+				Idempotent: true,
+			})
+			if resp == nil || resp.HttpStatusCode != tt.wantStatus {
+				t.Errorf("response = %v, want status %d", resp, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.wantRequests {
+				t.Errorf("requests received = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestCallerDeadline(t *testing.T) {
+	handler := &statusHandler{statuses: []int{http.StatusServiceUnavailable}}
+	c := newTestClient(t, handler, func(config *agora.Config) {
+		config.RetryPolicy = &retry.Policy{MaxAttempts: 1000, InitialBackoff: 20 * time.Millisecond}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.DoRESTWithRetry(ctx, &agora.Request{
+		Module:     "test:get",
+		Method:     http.MethodGet,
+		Path:       "/",
+		Idempotent: true,
+	})
+	if err == nil {
+		t.Fatal("DoRESTWithRetry() error = nil, want an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("DoRESTWithRetry() took %s, want it to stop at the deadline", elapsed)
+	}
+	if handler.count() < 2 || handler.count() > 10 {
+		t.Errorf("requests received = %d, want the attempts made before the deadline", handler.count())
+	}
+}
+
+func TestCallTimeout(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blockUntil(r, release)
+	}), nil)
+	t.Cleanup(func() {
+		close(release)
+	})
+
+	start := time.Now()
+	_, err := c.DoREST(agora.WithCallTimeout(context.Background(), 50*time.Millisecond), "/", http.MethodGet, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DoREST() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("DoREST() took %s, want it to stop after the call timeout", elapsed)
+	}
+}
+
+func TestAttemptTimeout(t *testing.T) {
+	release := make(chan struct{})
+	var requests int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			blockUntil(r, release)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}), nil)
+	t.Cleanup(func() {
+		close(release)
+	})
+
+	start := time.Now()
+	resp, err := c.DoREST(agora.WithAttemptTimeout(context.Background(), 50*time.Millisecond), "/", http.MethodGet, nil)
+	if err != nil {
+		t.Fatalf("DoREST() error = %v", err)
+	}
+	if resp.HttpStatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 from the second attempt", resp.HttpStatusCode)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests received = %d, want 2", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("DoREST() took %s, want the first attempt to time out after 50ms", elapsed)
+	}
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	errExpired := `{"code":435,"reason":"resource expired"}`
+	sentinels := []error{agora.ErrNotFound, agora.ErrRateLimited, agora.ErrUnauthorized, agora.ErrResourceExpired}
+
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		errorKind func(apiErr *agora.APIError) error
+		want      error
+		wantCode  int
+	}{
+		{name: "not found", status: http.StatusNotFound, body: `{"code":404,"reason":"not found"}`, want: agora.ErrNotFound, wantCode: 404},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `{"message":"too many requests"}`, want: agora.ErrRateLimited},
+		{name: "unauthorized", status: http.StatusUnauthorized, body: `{"message":"invalid authorization header"}`, want: agora.ErrUnauthorized},
+		{name: "forbidden", status: http.StatusForbidden, body: `{"message":"forbidden"}`, want: agora.ErrUnauthorized},
+		{
+			name:   "resource expired by error code",
+			status: http.StatusBadRequest,
+			body:   errExpired,
+			errorKind: func(apiErr *agora.APIError) error {
+				if apiErr.Code == 435 {
+					return agora.ErrResourceExpired
+				}
+				return nil
+			},
+			want:     agora.ErrResourceExpired,
+			wantCode: 435,
+		},
+		{name: "bad request", status: http.StatusBadRequest, body: errExpired, wantCode: 435},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &statusHandler{statuses: []int{tt.status}, bodies: []string{tt.body}}
+			c := newTestClient(t, handler, nil)
+
+			resp, err := c.DoRESTWithRetry(context.Background(), &agora.Request{
+				Module:     "test:get",
+				Method:     http.MethodGet,
+				Path:       "/",
+				Idempotent: true,
+				ErrorKind:  tt.errorKind,
+			})
+			if err == nil {
+				t.Fatal("DoRESTWithRetry() error = nil, want an error")
+			}
+
+			var apiErr *agora.APIError
+			if !errors.As(resp.Err(), &apiErr) {
+				t.Fatalf("response error = %v, want an *agora.APIError", resp.Err())
+			}
+			if apiErr.HttpStatusCode != tt.status || apiErr.Code != tt.wantCode || apiErr.Endpoint != "test:get" {
+				t.Errorf("APIError = %+v, want status %d, code %d and endpoint test:get", apiErr, tt.status, tt.wantCode)
+			}
+			wantRequestID := "request-" + strconv.Itoa(handler.count())
+			if apiErr.RequestID != wantRequestID || apiErr.Attempts != handler.count() {
+				t.Errorf("request id = %s, attempts = %d, want %s and %d", apiErr.RequestID, apiErr.Attempts, wantRequestID, handler.count())
+			}
+
+			// A retried status code is returned as the APIError itself
+			if tt.status == http.StatusTooManyRequests && !errors.Is(err, agora.ErrRateLimited) {
+				t.Errorf("errors.Is(%v, agora.ErrRateLimited) = false, want true", err)
+			}
+			for _, sentinel := range sentinels {
+				if got := errors.Is(resp.Err(), sentinel); got != (sentinel == tt.want) {
+					t.Errorf("errors.Is(%v, %v) = %t, want %t", resp.Err(), sentinel, got, sentinel == tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	// A service error code proving the request did not take effect, e.g. a network jitter
+	retryCode := func(apiErr *agora.APIError) (bool, bool) {
+		return apiErr.Code == 65, apiErr.Code == 65
+	}
+	tests := []struct {
+		name         string
+		body         string
+		idempotent   bool
+		wantRequests int
+	}{
+		{name: "retryable code", body: `{"code":65}`, idempotent: true, wantRequests: 2},
+		{name: "retryable code of a non-idempotent request", body: `{"code":65}`, idempotent: false, wantRequests: 1},
+		{name: "other code left to the status policy", body: `{"code":2}`, idempotent: true, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &statusHandler{statuses: []int{http.StatusBadRequest, http.StatusOK}, bodies: []string{tt.body, `{}`}}
+			c := newTestClient(t, handler, nil)
+
+			_, _ = c.DoRESTWithRetry(context.Background(), &agora.Request{
+				Module:      "test:post",
+				Method:      http.MethodPost,
+				Path:        "/",
+				Idempotent:  tt.idempotent,
+				ShouldRetry: retryCode,
+			})
+			if handler.count() != tt.wantRequests {
+				t.Errorf("requests received = %d, want %d", handler.count(), tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestLoggedBodiesRedacted(t *testing.T) {
+	var output bytes.Buffer
+	logger := log.NewDefaultLogger(log.DebugLevel)
+	logger.DEBUG.SetOutput(&output)
+
+	handler := &statusHandler{
+		statuses: []int{http.StatusBadRequest},
+		bodies:   []string{`{"code":2,"reason":"invalid parameter","token":"response-token"}`},
+	}
+	c := newTestClient(t, handler, func(config *agora.Config) {
+		config.Logger = logger
+	})
+
+	_, err := c.DoRESTWithRetry(context.Background(), &agora.Request{
+		Module:     "test:post",
+		Method:     http.MethodPost,
+		Path:       "/",
+		Idempotent: true,
+		Body: map[string]interface{}{
+			"cname":         "channel",
+			"storageConfig": map[string]string{"accessKey": "access-key", "secretKey": "secret-key", "bucket": "bucket"},
+		},
+	})
+	if err == nil {
+		t.Fatal("DoRESTWithRetry() error = nil, want an error")
+	}
+
+	logged := output.String() + err.Error()
+	basicAuth := base64.StdEncoding.EncodeToString([]byte(testUsername + ":" + testPassword))
+	for _, secret := range []string{"access-key", "secret-key", "response-token", basicAuth} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("%q is logged or returned in the error: %s", secret, logged)
+		}
+	}
+	for _, ordinary := range []string{`"cname":"channel"`, `"bucket":"bucket"`, `"reason":"invalid parameter"`} {
+		if !strings.Contains(logged, ordinary) {
+			t.Errorf("%s is not logged: %s", ordinary, logged)
+		}
+	}
+}
+
+// hookEvent is a call of a hook recorded by recordingHooks.
+type hookEvent struct {
+	hook   string
+	before bool
+	info   agora.HookInfo
+	// Status code of the response passed to AfterResponse, 0 if there is none
+	status int
+	err    error
+}
+
+// recordingHooks returns hooks named after names that record their calls into events.
+func recordingHooks(locker *sync.Mutex, events *[]hookEvent, names ...string) []agora.Hook {
+	hooks := make([]agora.Hook, 0, len(names))
+	for _, name := range names {
+		name := name
+		hooks = append(hooks, agora.HookFuncs{
+			Before: func(ctx context.Context, info agora.HookInfo, req *http.Request) error {
+				locker.Lock()
+				defer locker.Unlock()
+				*events = append(*events, hookEvent{hook: name, before: true, info: info})
+				req.Header.Add("X-Hooks", name)
+				return nil
+			},
+			After: func(ctx context.Context, info agora.HookInfo, resp *agora.BaseResponse, err error) {
+				locker.Lock()
+				defer locker.Unlock()
+				event := hookEvent{hook: name, info: info, err: err}
+				if resp != nil {
+					event.status = resp.HttpStatusCode
+				}
+				*events = append(*events, event)
+			},
+		})
+	}
+	return hooks
+}
+
+func TestHooks(t *testing.T) {
+	var (
+		locker  sync.Mutex
+		events  []hookEvent
+		headers [][]string
+	)
+	handler := &statusHandler{statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locker.Lock()
+		headers = append(headers, r.Header.Values("X-Hooks"))
+		locker.Unlock()
+		handler.ServeHTTP(w, r)
+	}), func(config *agora.Config) {
+		hooks := recordingHooks(&locker, &events, "first", "second")
+		// A nil hook is skipped
+		config.Hooks = []agora.Hook{hooks[0], nil, hooks[1]}
+	})
+
+	if _, err := c.DoRESTWithRetry(context.Background(), &agora.Request{
+		Module:     "test:get",
+		Method:     http.MethodGet,
+		Path:       "/path",
+		Idempotent: true,
+	}); err != nil {
+		t.Fatalf("DoRESTWithRetry() error = %v", err)
+	}
+
+	want := []hookEvent{
+		{hook: "first", before: true, info: agora.HookInfo{Attempt: 1}},
+		{hook: "second", before: true, info: agora.HookInfo{Attempt: 1}},
+		{hook: "first", info: agora.HookInfo{Attempt: 1}, status: http.StatusServiceUnavailable},
+		{hook: "second", info: agora.HookInfo{Attempt: 1}, status: http.StatusServiceUnavailable},
+		{hook: "first", before: true, info: agora.HookInfo{Attempt: 2}},
+		{hook: "second", before: true, info: agora.HookInfo{Attempt: 2}},
+		{hook: "first", info: agora.HookInfo{Attempt: 2}, status: http.StatusOK},
+		{hook: "second", info: agora.HookInfo{Attempt: 2}, status: http.StatusOK},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("hook events = %+v, want %+v", events, want)
+	}
+	for i, event := range events {
+		w := want[i]
+		w.info.CallInfo = agora.CallInfo{Module: "test:get", Method: http.MethodGet, Path: "/path"}
+		w.info.Idempotent = true
+		if event != w {
+			t.Errorf("hook event %d = %+v, want %+v", i, event, w)
+		}
+	}
+	for i, values := range headers {
+		if len(values) != 2 || values[0] != "first" || values[1] != "second" {
+			t.Errorf("X-Hooks of request %d = %v, want [first second]", i+1, values)
+		}
+	}
+}
+
+func TestHookSeesFinalRequest(t *testing.T) {
+	c := newTestClient(t, &statusHandler{statuses: []int{http.StatusOK}}, func(config *agora.Config) {
+		config.Hooks = []agora.Hook{agora.HookFuncs{
+			Before: func(ctx context.Context, info agora.HookInfo, req *http.Request) error {
+				if username, _, ok := req.BasicAuth(); !ok || username != testUsername {
+					t.Error("the credential is not set before the hook")
+				}
+				if req.Header.Get("User-Agent") == "" {
+					t.Error("the User-Agent header is not set before the hook")
+				}
+				body, err := req.GetBody()
+				if err != nil {
+					t.Fatalf("GetBody() error = %v", err)
+				}
+				if content, _ := io.ReadAll(body); string(content) != `{"cname":"channel"}` {
+					t.Errorf("body = %s, want {\"cname\":\"channel\"}", content)
+				}
+				return nil
+			},
+		}}
+	})
+
+	if _, err := c.DoREST(context.Background(), "/", http.MethodPost, map[string]string{"cname": "channel"}); err != nil {
+		t.Fatalf("DoREST() error = %v", err)
+	}
+}
+
+func TestHookRejectsRequest(t *testing.T) {
+	errRejected := errors.New("rejected by the audit hook")
+	var after int32
+	handler := &statusHandler{statuses: []int{http.StatusOK}}
+	c := newTestClient(t, handler, func(config *agora.Config) {
+		config.Hooks = []agora.Hook{agora.HookFuncs{
+			Before: func(ctx context.Context, info agora.HookInfo, req *http.Request) error {
+				return errRejected
+			},
+			After: func(ctx context.Context, info agora.HookInfo, resp *agora.BaseResponse, err error) {
+				atomic.AddInt32(&after, 1)
+			},
+		}}
+	})
+
+	_, err := c.DoRESTWithRetry(context.Background(), &agora.Request{
+		Module: "test:post",
+		Method: http.MethodPost,
+		Path:   "/",
+	})
+	if !errors.Is(err, errRejected) {
+		t.Errorf("DoRESTWithRetry() error = %v, want %v", err, errRejected)
+	}
+	// Neither sent nor retried
+	if handler.count() != 0 || atomic.LoadInt32(&after) != 0 {
+		t.Errorf("requests received = %d, AfterResponse calls = %d, want 0 and 0", handler.count(), after)
+	}
+}
+
+func TestHookAfterFailedAttempt(t *testing.T) {
+	var (
+		locker sync.Mutex
+		events []hookEvent
+	)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		closeConnection(t, w)
+	}), func(config *agora.Config) {
+		config.Hooks = recordingHooks(&locker, &events, "hook")
+		config.RetryPolicy.MaxAttempts = 1
+	})
+
+	if _, err := c.DoREST(context.Background(), "/", http.MethodGet, nil); err == nil {
+		t.Fatal("DoREST() error = nil, want the network error")
+	}
+	if len(events) != 2 || !events[0].before || events[1].before || events[1].status != 0 || events[1].err == nil {
+		t.Errorf("hook events = %+v, want BeforeRequest then AfterResponse with the error and no response", events)
+	}
+}
+
+type callSuccess struct {
+	Sid string `json:"sid"`
+}
+
+type callError struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func TestCall(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		// Whether the response is decoded, and the decoded responses when it is
+		wantResult  bool
+		wantSuccess callSuccess
+		wantErrResp callError
+		// Whether the error is a gateway error
+		wantGateway bool
+	}{
+		{name: "success", status: http.StatusOK, body: `{"sid":"sid"}`, wantResult: true, wantSuccess: callSuccess{Sid: "sid"}},
+		{name: "empty success", status: http.StatusOK, wantResult: true},
+		{name: "undecodable success", status: http.StatusOK, body: `{"sid":1}`},
+		{
+			name:        "service error",
+			status:      http.StatusBadRequest,
+			body:        `{"code":2,"reason":"invalid parameter"}`,
+			wantResult:  true,
+			wantErrResp: callError{Code: 2, Reason: "invalid parameter"},
+		},
+		{name: "JSON without an error field", status: http.StatusBadRequest, body: `{"message":"bad request"}`, wantGateway: true},
+		{name: "JSON array", status: http.StatusBadRequest, body: `[]`, wantGateway: true},
+		{name: "empty error", status: http.StatusNotFound, wantGateway: true},
+		{name: "HTML error", status: http.StatusBadGateway, body: `<html>502 Bad Gateway</html>`, wantGateway: true},
+		// The retries of 5xx status codes are exhausted
+		{name: "retried service error", status: http.StatusServiceUnavailable, body: `{"code":1,"reason":"busy"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			if tt.body != "" {
+				bodies = []string{tt.body}
+			}
+			c := newTestClient(t, &statusHandler{statuses: []int{tt.status}, bodies: bodies}, nil)
+
+			result, err := agora.Call[agora.NoBody, callSuccess, callError](context.Background(), c, &agora.Request{
+				Module:      "test:get",
+				Method:      http.MethodGet,
+				Path:        "/",
+				Idempotent:  true,
+				ErrorFields: []string{"code", "reason"},
+			}, nil)
+
+			if !tt.wantResult {
+				if err == nil {
+					t.Fatalf("Call() = %+v, want an error", result)
+				}
+				var apiErr *agora.APIError
+				var gatewayErr *agora.GatewayErr
+				isGateway := errors.As(err, &apiErr) && errors.As(err, &gatewayErr)
+				if isGateway != tt.wantGateway {
+					t.Fatalf("Call() error = %v, gateway error = %v, want %v", err, isGateway, tt.wantGateway)
+				}
+				if isGateway && (gatewayErr.Code != tt.status || gatewayErr.Msg != tt.body || apiErr.HttpStatusCode != tt.status) {
+					t.Errorf("gateway error = %+v, APIError = %+v, want status %d and body %q", gatewayErr, apiErr, tt.status, tt.body)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Call() error = %v", err)
+			}
+			if result.IsSuccess() != (tt.status == http.StatusOK) || result.Response.HttpStatusCode != tt.status {
+				t.Errorf("IsSuccess() = %v, status = %d, want status %d", result.IsSuccess(), result.Response.HttpStatusCode, tt.status)
+			}
+			if result.SuccessRes != tt.wantSuccess || result.ErrResponse != tt.wantErrResp {
+				t.Errorf("Call() = %+v and %+v, want %+v and %+v", result.SuccessRes, result.ErrResponse, tt.wantSuccess, tt.wantErrResp)
+			}
+			if string(result.Response.RawBody) != tt.body {
+				t.Errorf("RawBody = %s, want %s", result.Response.RawBody, tt.body)
+			}
+		})
+	}
+}
+
+func TestCallBody(t *testing.T) {
+	var gotBodies []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBodies = append(gotBodies, string(body))
+		_, _ = w.Write([]byte(`{"sid":"sid"}`))
+	}), nil)
+	request := &agora.Request{Module: "test:post", Method: http.MethodPost, Path: "/", Idempotent: true}
+
+	if _, err := agora.Call[callSuccess, agora.NoBody, callError](context.Background(), c, request, &callSuccess{Sid: "sid"}); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	// A NoBody successful response ignores the body
+	result, err := agora.Call[agora.NoBody, agora.NoBody, callError](context.Background(), c, request, &agora.NoBody{})
+	if err != nil || !result.IsSuccess() {
+		t.Fatalf("Call() = %+v, %v, want a successful result", result, err)
+	}
+	if request.Body != nil {
+		t.Error("Call() modified the request")
+	}
+
+	want := []string{`{"sid":"sid"}`, `{}`}
+	if len(gotBodies) != len(want) || gotBodies[0] != want[0] || gotBodies[1] != want[1] {
+		t.Errorf("request bodies = %q, want %q", gotBodies, want)
+	}
+}
+
+// gzipHandler responds with body compressed by gzip, it fails the test if the request does not accept gzip.
+func gzipHandler(t *testing.T, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gzipWriter := gzip.NewWriter(w)
+		_, _ = gzipWriter.Write([]byte(body))
+		_ = gzipWriter.Close()
+	})
+}
+
+func TestGzipResponse(t *testing.T) {
+	body := `{"sid":"` + strings.Repeat("s", 1024) + `"}`
+	c := newTestClient(t, gzipHandler(t, body), func(config *agora.Config) {
+		config.EnableGzip = true
+	})
+
+	resp, err := c.DoREST(context.Background(), "/", http.MethodGet, nil)
+	if err != nil {
+		t.Fatalf("DoREST() error = %v", err)
+	}
+	if string(resp.RawBody) != body {
+		t.Errorf("RawBody = %s, want %s", resp.RawBody, body)
+	}
+}
+
+func TestInvalidGzipResponse(t *testing.T) {
+	handler := &statusHandler{
+		statuses: []int{http.StatusOK},
+		bodies:   []string{`{"sid":"sid"}`},
+		header:   http.Header{"Content-Encoding": []string{"gzip"}},
+	}
+	c := newTestClient(t, handler, func(config *agora.Config) {
+		config.EnableGzip = true
+	})
+
+	if _, err := c.DoREST(context.Background(), "/", http.MethodGet, nil); !errors.Is(err, gzip.ErrHeader) {
+		t.Errorf("DoREST() error = %v, want %v", err, gzip.ErrHeader)
+	}
+}
+
+func TestMaxResponseBodySize(t *testing.T) {
+	const limit = 64
+	tests := []struct {
+		name    string
+		size    int
+		gzip    bool
+		wantErr bool
+	}{
+		{name: "below the limit", size: limit - 1},
+		{name: "at the limit", size: limit},
+		{name: "above the limit", size: limit + 1, wantErr: true},
+		{name: "compressed at the limit", size: limit, gzip: true},
+		// The compressed body is smaller than the limit
+		{name: "decompressed above the limit", size: 16 * limit, gzip: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := strings.Repeat("a", tt.size)
+			var requests int32
+			handler := http.Handler(&statusHandler{statuses: []int{http.StatusOK}, bodies: []string{body}})
+			if tt.gzip {
+				handler = gzipHandler(t, body)
+			}
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				handler.ServeHTTP(w, r)
+			}), func(config *agora.Config) {
+				config.MaxResponseBodySize = limit
+				config.EnableGzip = tt.gzip
+			})
+
+			resp, err := c.DoRESTWithRetry(context.Background(), &agora.Request{
+				Module:     "test:get",
+				Method:     http.MethodGet,
+				Path:       "/",
+				Idempotent: true,
+			})
+			if !tt.wantErr {
+				if err != nil || string(resp.RawBody) != body {
+					t.Errorf("DoRESTWithRetry() = %+v, %v, want a body of %d bytes", resp, err, tt.size)
+				}
+				return
+			}
+
+			var tooLarge *agora.ErrResponseTooLarge
+			if !errors.As(err, &tooLarge) || tooLarge.Module != "test:get" || tooLarge.Limit != limit {
+				t.Fatalf("DoRESTWithRetry() error = %v, want an *agora.ErrResponseTooLarge", err)
+			}
+			if n := atomic.LoadInt32(&requests); n != 1 {
+				t.Errorf("requests received = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestDiscardRawBody(t *testing.T) {
+	body := `{"sid":"sid"}`
+	for _, discard := range []bool{false, true} {
+		t.Run("discard "+strconv.FormatBool(discard), func(t *testing.T) {
+			c := newTestClient(t, &statusHandler{statuses: []int{http.StatusOK}, bodies: []string{body}}, func(config *agora.Config) {
+				config.DiscardRawBody = discard
+			})
+			request := &agora.Request{Module: "test:get", Method: http.MethodGet, Path: "/", Idempotent: true}
+
+			// The raw body is only dropped by Call
+			resp, err := c.DoRESTWithRetry(context.Background(), request)
+			if err != nil || string(resp.RawBody) != body {
+				t.Fatalf("DoRESTWithRetry() = %+v, %v, want the raw body", resp, err)
+			}
+
+			result, err := agora.Call[agora.NoBody, callSuccess, callError](context.Background(), c, request, nil)
+			if err != nil {
+				t.Fatalf("Call() error = %v", err)
+			}
+			if result.SuccessRes.Sid != "sid" {
+				t.Errorf("SuccessRes = %+v, want the decoded body", result.SuccessRes)
+			}
+			if discarded := result.Response.RawBody == nil; discarded != discard {
+				t.Errorf("RawBody = %s, want it discarded: %v", result.Response.RawBody, discard)
+			}
+		})
+	}
+}
+
+func TestTimeoutOption(t *testing.T) {
+	tests := []struct {
+		name string
+		// Call timeout set on the context by agora.WithCallTimeout, and deadline of the context
+		ctxTimeout  time.Duration
+		ctxDeadline time.Duration
+		timeout     time.Duration
+		// Bounds of the duration of the call
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "shorter than the context call timeout", ctxTimeout: time.Hour, timeout: 50 * time.Millisecond, wantMax: time.Second},
+		{
+			name:       "longer than the context call timeout",
+			ctxTimeout: 50 * time.Millisecond,
+			timeout:    300 * time.Millisecond,
+			wantMin:    300 * time.Millisecond,
+			wantMax:    2 * time.Second,
+		},
+		{name: "longer than the context deadline", ctxDeadline: 50 * time.Millisecond, timeout: time.Hour, wantMax: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := make(chan struct{})
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				blockUntil(r, release)
+			}), nil)
+			t.Cleanup(func() {
+				close(release)
+			})
+
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				ctx = agora.WithCallTimeout(ctx, tt.ctxTimeout)
+			}
+			if tt.ctxDeadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxDeadline)
+				defer cancel()
+			}
+
+			start := time.Now()
+			_, err := c.DoRESTWithRetry(ctx, &agora.Request{
+				Module:     "test:get",
+				Method:     http.MethodGet,
+				Path:       "/",
+				Idempotent: true,
+				Options:    []agora.CallOption{agora.Timeout(tt.timeout)},
+			})
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("DoRESTWithRetry() error = %v, want context.DeadlineExceeded", err)
+			}
+			if elapsed := time.Since(start); elapsed < tt.wantMin || elapsed > tt.wantMax {
+				t.Errorf("DoRESTWithRetry() took %s, want between %s and %s", elapsed, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestAttemptTimeoutOption(t *testing.T) {
+	release := make(chan struct{})
+	var requests int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			blockUntil(r, release)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}), nil)
+	t.Cleanup(func() {
+		close(release)
+	})
+
+	start := time.Now()
+	_, err := c.DoRESTWithRetry(agora.WithAttemptTimeout(context.Background(), time.Hour), &agora.Request{
+		Module:     "test:get",
+		Method:     http.MethodGet,
+		Path:       "/",
+		Idempotent: true,
+		Options:    []agora.CallOption{agora.AttemptTimeout(50 * time.Millisecond)},
+	})
+	if err != nil {
+		t.Fatalf("DoRESTWithRetry() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests received = %d, want 2", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("DoRESTWithRetry() took %s, want the first attempt to time out after 50ms", elapsed)
+	}
+}
+
+func TestMaxAttemptsOption(t *testing.T) {
+	tests := []struct {
+		name         string
+		opt          agora.CallOption
+		wantRequests int
+	}{
+		{name: "more than the policy", opt: agora.MaxAttempts(5), wantRequests: 5},
+		{name: "fewer than the policy", opt: agora.MaxAttempts(2), wantRequests: 2},
+		{name: "less than 1", opt: agora.MaxAttempts(0), wantRequests: 1},
+		{name: "no retry", opt: agora.NoRetry(), wantRequests: 1},
+		{name: "nil", wantRequests: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &statusHandler{statuses: []int{http.StatusServiceUnavailable}}
+			c := newTestClient(t, handler, nil)
+
+			_, err := c.DoRESTWithRetry(context.Background(), &agora.Request{
+				Module:     "test:get",
+				Method:     http.MethodGet,
+				Path:       "/",
+				Idempotent: true,
+				Options:    []agora.CallOption{tt.opt},
+			})
+			var apiErr *agora.APIError
+			if !errors.As(err, &apiErr) {
+				t.Errorf("DoRESTWithRetry() error = %v, want an *agora.APIError", err)
+			}
+			if handler.count() != tt.wantRequests {
+				t.Errorf("requests received = %d, want %d", handler.count(), tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestHeaderOptions(t *testing.T) {
+	var (
+		locker  sync.Mutex
+		headers []http.Header
+	)
+	handler := &statusHandler{statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locker.Lock()
+		headers = append(headers, r.Header.Clone())
+		locker.Unlock()
+		handler.ServeHTTP(w, r)
+	}), nil)
+
+	_, err := c.DoRESTWithRetry(context.Background(), &agora.Request{
+		Module:     "test:get",
+		Method:     http.MethodGet,
+		Path:       "/",
+		Idempotent: true,
+		Options: []agora.CallOption{
+			agora.Header("X-Tenant", "tenant-1"),
+			agora.Header("X-Tenant", "tenant-2"),
+			// The headers set by the client are not overridden
+			agora.Header("Authorization", "Bearer token"),
+			agora.Header("User-Agent", "agent"),
+			agora.IdempotencyKey("key"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("DoRESTWithRetry() error = %v", err)
+	}
+
+	if len(headers) != 2 {
+		t.Fatalf("requests received = %d, want 2", len(headers))
+	}
+	for i, header := range headers {
+		if tenants := header.Values("X-Tenant"); len(tenants) != 2 || tenants[0] != "tenant-1" || tenants[1] != "tenant-2" {
+			t.Errorf("X-Tenant of request %d = %v, want [tenant-1 tenant-2]", i+1, tenants)
+		}
+		if key := header.Get("Idempotency-Key"); key != "key" {
+			t.Errorf("Idempotency-Key of request %d = %q, want key", i+1, key)
+		}
+		if auth := header.Values("Authorization"); len(auth) != 1 || !strings.HasPrefix(auth[0], "Basic ") {
+			t.Errorf("Authorization of request %d = %v, want the basic auth credential", i+1, auth)
+		}
+		if userAgent := header.Values("User-Agent"); len(userAgent) != 1 || userAgent[0] != agora.BuildUserAgent() {
+			t.Errorf("User-Agent of request %d = %v, want %s", i+1, userAgent, agora.BuildUserAgent())
+		}
+	}
+}
+
+func TestRegionOption(t *testing.T) {
+	handler := &statusHandler{statuses: []int{http.StatusOK}}
+	c := newTestClient(t, handler, nil)
+
+	// The region is ignored with a fixed base URL
+	if _, err := c.DoREST(context.Background(), "/", http.MethodGet, nil, agora.Region("api-us-west-1")); err != nil {
+		t.Fatalf("DoREST() error = %v", err)
+	}
+	if handler.count() != 1 {
+		t.Errorf("requests received = %d, want 1", handler.count())
+	}
+}
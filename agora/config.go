@@ -1,32 +1,164 @@
 package agora
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/auth"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/breaker"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/domain"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/limit"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
 )
 
 type Config struct {
 	// Agora AppID
 	AppID string
-	// Timeout for HTTP requests
+	// App certificate of the project.(Optional)
+	//
+	// When it is set, an RTC or RTM token is minted for the requests that need one but are given none, see the token package.
+	AppCertificate string
+	// Timeout for each HTTP attempt of a call without a deadline. The default value is 10 seconds.
+	//
+	// Pass a context with a deadline, or use agora.WithCallTimeout and agora.WithAttemptTimeout,
+	// to control the timeouts of a single call.
 	HttpTimeout time.Duration
+	// HTTP client used to send requests.(Optional)
+	//
+	// Set it to use your own proxy, mTLS, CA bundle or connection pool settings.
+	// The client is copied, HttpTimeout and Transport are ignored when it is set.
+	HttpClient *http.Client
+	// Transport used by the default HTTP client.(Optional)
+	//
+	// Only takes effect when HttpClient is nil. The default value is http.DefaultTransport.
+	Transport http.RoundTripper
+	// Middlewares applied to every HTTP request.(Optional)
+	//
+	// The first middleware is the outermost one. See Middleware for details.
+	Middlewares []Middleware
+	// Maximum size of a response body in bytes.(Optional)
+	//
+	// A larger response fails the call with an ErrResponseTooLarge. The size is not limited when it is 0.
+	MaxResponseBodySize int64
+	// Whether to request gzip compressed responses and decompress them.(Optional)
+	//
+	// MaxResponseBodySize applies to the decompressed body.
+	EnableGzip bool
+	// Whether to drop BaseResponse.RawBody once the response is decoded, to reduce memory usage of callers that
+	// keep many responses, e.g. when polling Query.(Optional)
+	DiscardRawBody bool
+	// Hooks run around every HTTP attempt, with metadata about the service operation.(Optional)
+	//
+	// See Hook for details.
+	Hooks []Hook
 	// Credential for accessing the Agora service.
 	//
 	// Available credential types:
 	//
 	//  - BasicAuthCredential: See auth.NewBasicAuthCredential for details
+	//
+	//  - TokenCredential: See auth.NewTokenCredential for details
 	Credential auth.Credential
+	// Provider of the credential used by each request, it takes precedence over Credential.(Optional)
+	//
+	// Use it when the credential rotates. See auth.CredentialProvider for details.
+	CredentialProvider auth.CredentialProvider
 
 	// Domain area for the REST Client. See domain.Area for details.
 	DomainArea domain.Area
+	// Region prefixes and domain suffixes used instead of the ones of DomainArea.(Optional)
+	//
+	// See domain.WithDomain for details.
+	Domain *domain.Domain
+	// Resolver that selects the domain suffix.(Optional)
+	//
+	// The default resolver resolves the domain of every suffix concurrently and selects the first one resolved.
+	Resolver domain.Resolver
+	// Fixed base URL all requests are sent to, e.g. for a private deployment or a local stand-in server.(Optional)
+	//
+	// DomainArea, Domain and Resolver are ignored when it is set. See domain.WithBaseURL for details.
+	BaseURL string
+	// Policy of the endpoint health tracking, which selects the endpoint requests are sent to.(Optional)
+	//
+	// The default value is domain.DefaultHealthPolicy(). See domain.HealthPolicy for details.
+	HealthPolicy *domain.HealthPolicy
+	// Policy of the circuit breaker of every endpoint host.(Optional)
+	//
+	// Requests fail fast with an ErrCircuitOpen while the circuits of all the endpoints are open,
+	// and they are sent to another endpoint while the circuit of the best one is open.
+	//
+	// The default value is breaker.DefaultPolicy(). See breaker.Policy for details.
+	CircuitBreaker *breaker.Policy
+	// Interval of the background domain refresh.(Optional)
+	//
+	// When it is set, the domain is resolved by a background goroutine instead of when a request is sent,
+	// call Close to stop it. See domain.WithBackgroundRefresh for details.
+	DomainRefreshInterval time.Duration
+	// Handler of domain resolution errors.(Optional)
+	//
+	// The last known-good domain is kept when the resolution fails, requests are not affected.
+	OnDomainResolveError func(err error)
 
 	// Logger for the REST Client
 	//
 	// Implement the log.Logger interface in your project to output REST Client logs to your logging component.
 	//
 	// Alternatively, you can use the default logging component. See log.NewDefaultLogger for details.
+	//
+	// To output structured logs, wrap a log.StructuredLogger, e.g. log.NewSlogLogger, with log.NewLogger.
+	// The values of sensitive fields in logged request and response bodies are masked, see log.SensitiveFields.
 	Logger log.Logger
+
+	// Retry policy for failed requests.(Optional)
+	//
+	// The default value is retry.DefaultPolicy(). See retry.Policy for details.
+	RetryPolicy *retry.Policy
+
+	// Client-side rate and concurrency limits of the requests, e.g. to stay below the QPS limit of the AppID.(Optional)
+	//
+	// Nothing is limited by default. See limit.Policy for details.
+	RateLimit *limit.Policy
+
+	// Instrumentation that observes every API call and HTTP attempt, e.g. for tracing and metrics.(Optional)
+	//
+	// See the otelinstrumentation module for an OpenTelemetry implementation.
+	Instrumentation Instrumentation
+}
+
+// @brief Checks the configuration before a client is created with it
+//
+// @return Returns an error describing every invalid field, or nil if the configuration is valid.
+//
+// @since v0.13.0
+func (c *Config) Validate() error {
+	var problems []string
+	if c.AppID == "" {
+		problems = append(problems, "AppID is required")
+	}
+	if c.HttpTimeout < 0 {
+		problems = append(problems, "HttpTimeout must not be negative")
+	}
+	if c.MaxResponseBodySize < 0 {
+		problems = append(problems, "MaxResponseBodySize must not be negative")
+	}
+	if c.DomainRefreshInterval < 0 {
+		problems = append(problems, "DomainRefreshInterval must not be negative")
+	}
+	if c.BaseURL != "" {
+		if u, err := url.Parse(c.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("BaseURL %q is not an absolute URL", c.BaseURL))
+		}
+	} else if c.Domain == nil {
+		if _, ok := domain.RegionDomain[c.DomainArea]; !ok {
+			problems = append(problems, "DomainArea is required when neither Domain nor BaseURL is set")
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("agora: invalid config: %s", strings.Join(problems, "; "))
+	}
+	return nil
 }
@@ -0,0 +1,45 @@
+package agora
+
+import (
+	"context"
+	"time"
+)
+
+type callTimeoutKey struct{}
+
+type attemptTimeoutKey struct{}
+
+// @brief WithCallTimeout returns a copy of ctx that limits the total time of each API call made with it
+//
+// @note The limit covers all attempts and the delays between them. The earlier one of the limit and the deadline of ctx takes effect.
+//
+// @since v0.13.0
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+// @brief CallTimeoutFromContext returns the call timeout set by WithCallTimeout
+//
+// @since v0.13.0
+func CallTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(callTimeoutKey{}).(time.Duration)
+	return timeout, ok && timeout > 0
+}
+
+// @brief WithAttemptTimeout returns a copy of ctx that limits the time of each HTTP attempt of the API calls made with it
+//
+// @note When it is not set, an attempt of a call with a deadline may use the whole remaining time of the call,
+// and an attempt of a call without a deadline is limited by Config.HttpTimeout.
+//
+// @since v0.13.0
+func WithAttemptTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, attemptTimeoutKey{}, timeout)
+}
+
+// @brief AttemptTimeoutFromContext returns the attempt timeout set by WithAttemptTimeout
+//
+// @since v0.13.0
+func AttemptTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(attemptTimeoutKey{}).(time.Duration)
+	return timeout, ok && timeout > 0
+}
@@ -3,7 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
-	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -88,66 +88,212 @@ var RegionDomain = map[Area]Domain{
 
 type Pool struct {
 	domainArea            Area
+	baseURL               string
 	domainSuffixes        []string
 	currentDomain         string
 	regionPrefixes        []string
 	currentRegionPrefixes []string
 	locker                *sync.Mutex
 
-	resolver   Resolver
-	lastUpdate time.Time
-	logger     log.Logger
-	module     string
+	resolver     Resolver
+	healthPolicy *HealthPolicy
+	health       map[Endpoint]*endpointHealth
+	lastUpdate   time.Time
+	// held while the domain is resolved on request, see SelectBestDomain
+	resolving sync.Mutex
+
+	refreshInterval time.Duration
+	onResolveError  func(err error)
+	stopRefresher   context.CancelFunc
+	refresherDone   chan struct{}
+	closeOnce       sync.Once
+	logger          log.Logger
+	module          string
 }
 
-func NewPool(domainArea Area, logger log.Logger) (*Pool, error) {
-	if _, ok := RegionDomain[domainArea]; !ok {
-		return nil, errors.New("invalid domain area")
+type poolOptions struct {
+	resolver        Resolver
+	domain          *Domain
+	baseURL         string
+	healthPolicy    *HealthPolicy
+	refreshInterval time.Duration
+	onResolveError  func(err error)
+}
+
+// @brief Option of NewPool
+//
+// @since v0.13.0
+type PoolOption func(o *poolOptions)
+
+// @brief Set the resolver that selects the domain suffix for the current region prefix
+//
+// @note The default resolver resolves the domain of every suffix concurrently and selects the first one resolved.
+//
+// @since v0.13.0
+func WithResolver(resolver Resolver) PoolOption {
+	return func(o *poolOptions) {
+		o.resolver = resolver
+	}
+}
+
+// @brief Set the region prefixes and domain suffixes, instead of the ones of the domain area in RegionDomain
+//
+// @note The first region prefix is used first, the others are used in order when requests fail.
+//
+// @since v0.13.0
+func WithDomain(domain Domain) PoolOption {
+	return func(o *poolOptions) {
+		o.domain = &domain
+	}
+}
+
+// @brief Send all requests to a fixed base URL, e.g. "https://gateway.example.com" for a private deployment
+//
+// @note Domain resolution and region failover are disabled, the domain area is ignored.
+//
+// @since v0.13.0
+func WithBaseURL(baseURL string) PoolOption {
+	return func(o *poolOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// @brief Set the policy of the endpoint health tracking
+//
+// @note The default value is DefaultHealthPolicy(). See HealthPolicy for details.
+//
+// @since v0.13.0
+func WithHealthPolicy(policy *HealthPolicy) PoolOption {
+	return func(o *poolOptions) {
+		o.healthPolicy = policy
 	}
+}
+
+func NewPool(domainArea Area, logger log.Logger, options ...PoolOption) (*Pool, error) {
+	var o poolOptions
+	for _, option := range options {
+		option(&o)
+	}
+
 	d := &Pool{
-		domainArea:     domainArea,
-		domainSuffixes: RegionDomain[domainArea].MajorDomainSuffixes,
-		resolver:       newResolverImpl(logger),
-		logger:         logger,
-		locker:         &sync.Mutex{},
-		module:         "domain pool",
+		domainArea:      domainArea,
+		resolver:        o.resolver,
+		healthPolicy:    o.healthPolicy,
+		health:          make(map[Endpoint]*endpointHealth),
+		refreshInterval: o.refreshInterval,
+		onResolveError:  o.onResolveError,
+		logger:          logger,
+		locker:          &sync.Mutex{},
+		module:          "domain pool",
+	}
+	if d.resolver == nil {
+		d.resolver = newResolverImpl(logger)
+	}
+	if d.healthPolicy == nil {
+		d.healthPolicy = DefaultHealthPolicy()
+	} else {
+		d.healthPolicy = d.healthPolicy.normalized()
+	}
+
+	if o.baseURL != "" {
+		d.baseURL = strings.TrimSuffix(o.baseURL, "/")
+		return d, nil
+	}
+
+	domain, ok := RegionDomain[domainArea]
+	if o.domain != nil {
+		domain, ok = *o.domain, true
+	}
+	if !ok {
+		return nil, errors.New("invalid domain area")
+	}
+	if len(domain.RegionDomainPrefixes) == 0 || len(domain.MajorDomainSuffixes) == 0 {
+		return nil, errors.New("domain should have at least one region prefix and one domain suffix")
 	}
 
-	d.regionPrefixes = append(d.regionPrefixes, RegionDomain[domainArea].RegionDomainPrefixes...)
+	d.domainSuffixes = append(d.domainSuffixes, domain.MajorDomainSuffixes...)
+	d.regionPrefixes = append(d.regionPrefixes, domain.RegionDomainPrefixes...)
 
 	d.currentRegionPrefixes = d.regionPrefixes
 	d.currentDomain = d.domainSuffixes[0]
 
+	if d.refreshInterval > 0 {
+		d.startRefresher()
+	}
+
 	return d, nil
 }
 
 const updateDuration = 30 * time.Second
 
 func (d *Pool) domainNeedUpdate() bool {
+	if d.baseURL != "" || d.refreshInterval > 0 {
+		return false
+	}
+
+	d.locker.Lock()
+	defer d.locker.Unlock()
+
 	return time.Since(d.lastUpdate) > updateDuration
 }
 
+// SelectBestDomain resolves the domain when it has not been resolved for 30 seconds.
+//
+// It does nothing when the pool refreshes the domain in the background, see WithBackgroundRefresh.
+// The last known-good domain is kept when the resolution fails, the error is reported to the
+// handler set by WithResolveErrorHandler instead of being returned.
 func (d *Pool) SelectBestDomain(ctx context.Context) error {
 	if !d.domainNeedUpdate() {
 		return nil
 	}
-
-	d.locker.Lock()
-	defer d.locker.Unlock()
+	// Only one request resolves the domain, the others keep using the current one
+	if !d.resolving.TryLock() {
+		return nil
+	}
+	defer d.resolving.Unlock()
 
 	if d.domainNeedUpdate() {
 		d.logger.Debug(ctx, d.module, "need update domainPool")
-		domain, err := d.resolver.Resolve(ctx, d.domainSuffixes, d.currentRegionPrefixes[0])
-		if err != nil {
-			return err
-		}
+		d.resolve(ctx)
+	}
+	return nil
+}
+
+// resolve selects the domain suffix of the current region prefix with the resolver.
+//
+// The last known-good domain is kept when the resolution fails.
+func (d *Pool) resolve(ctx context.Context) {
+	d.locker.Lock()
+	regionPrefix := d.currentRegionPrefixes[0]
+	d.locker.Unlock()
+
+	domain, err := d.resolver.Resolve(ctx, d.domainSuffixes, regionPrefix)
+
+	d.locker.Lock()
+	d.lastUpdate = time.Now()
+	if err == nil {
 		d.logger.Debugf(ctx, d.module, "select best domain:%s", domain)
 		d.selectDomain(domain)
 	}
-	return nil
+	currentDomain := d.currentDomain
+	d.locker.Unlock()
+
+	if err != nil {
+		d.logger.Warnf(ctx, d.module, "resolve domain failed, keep using %s,err:%s", currentDomain, err)
+		if d.onResolveError != nil {
+			d.onResolveError(err)
+		}
+	}
 }
 
+// NextRegion moves the current region prefix to the end of the order of preference.
+//
+// Deprecated: Report request outcomes with ReportResult instead, endpoints are selected by their health.
 func (d *Pool) NextRegion() {
+	if d.baseURL != "" {
+		return
+	}
+
 	d.locker.Lock()
 	defer d.locker.Unlock()
 
@@ -165,11 +311,50 @@ func (d *Pool) selectDomain(domain string) {
 }
 
 func (d *Pool) GetCurrentUrl() string {
+	baseURL, _, _ := d.Current()
+
+	return baseURL
+}
+
+// Current returns the base URL, the region prefix and the domain suffix that requests are currently sent to.
+//
+// It is the best endpoint according to the outcomes reported by ReportResult, see HealthPolicy.
+//
+// The region prefix and the domain suffix are empty when the pool uses a fixed base URL, see WithBaseURL.
+func (d *Pool) Current() (baseURL string, regionPrefix string, domainSuffix string) {
+	if d.baseURL != "" {
+		return d.baseURL, "", ""
+	}
+
 	d.locker.Lock()
 	defer d.locker.Unlock()
 
-	currentRegion := d.currentRegionPrefixes[0]
-	currentDomain := d.currentDomain
+	endpoint := d.bestEndpoint(time.Now())
+	regionPrefix, domainSuffix = endpoint.RegionPrefix, endpoint.DomainSuffix
+	return endpoint.baseURL(), regionPrefix, domainSuffix
+}
+
+// Select returns the best endpoint allowed by allow, e.g. the best endpoint whose circuit breaker is not open.
+//
+// allow is called with the base URL of the endpoints from the best to the worst, until it returns true.
+// ok is false when no endpoint is allowed.
+func (d *Pool) Select(allow func(baseURL string) bool) (baseURL string, regionPrefix string, domainSuffix string, ok bool) {
+	if d.baseURL != "" {
+		return d.baseURL, "", "", allow(d.baseURL)
+	}
+
+	d.locker.Lock()
+	defer d.locker.Unlock()
+
+	for _, endpoint := range d.rankedEndpoints(time.Now()) {
+		if baseURL = endpoint.baseURL(); allow(baseURL) {
+			return baseURL, endpoint.RegionPrefix, endpoint.DomainSuffix, true
+		}
+	}
+	return "", "", "", false
+}
 
-	return fmt.Sprintf("https://%s.%s", currentRegion, currentDomain)
+// HasRegionPrefix reports whether the region prefix is one of the region prefixes of the pool.
+func (d *Pool) HasRegionPrefix(regionPrefix string) bool {
+	return utils.Contains(d.regionPrefixes, regionPrefix)
 }
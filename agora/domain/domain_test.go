@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+)
+
+// fakeResolver returns the configured domain or error, and records its calls.
+type fakeResolver struct {
+	locker sync.Mutex
+	domain string
+	err    error
+	calls  int
+	// Arguments of the last call
+	domains      []string
+	regionPrefix string
+}
+
+func (f *fakeResolver) Resolve(ctx context.Context, domains []string, regionPrefix string) (string, error) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+
+	f.calls++
+	f.domains = domains
+	f.regionPrefix = regionPrefix
+	return f.domain, f.err
+}
+
+func (f *fakeResolver) set(domain string, err error) {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+
+	f.domain, f.err = domain, err
+}
+
+func (f *fakeResolver) callCount() int {
+	f.locker.Lock()
+	defer f.locker.Unlock()
+
+	return f.calls
+}
+
+func TestCustomResolver(t *testing.T) {
+	resolver := &fakeResolver{domain: ChineseMainlandMajorDomain}
+	var resolveErrors []error
+	pool, err := NewPool(US, log.DiscardLogger, WithResolver(resolver), WithResolveErrorHandler(func(err error) {
+		resolveErrors = append(resolveErrors, err)
+	}))
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if got := pool.GetCurrentUrl(); got != "https://api-us-west-1.agora.io" {
+		t.Errorf("GetCurrentUrl() = %s before the resolution, want the first domain suffix", got)
+	}
+	if err = pool.SelectBestDomain(ctx); err != nil {
+		t.Fatalf("SelectBestDomain() error = %v", err)
+	}
+	if got := pool.GetCurrentUrl(); got != "https://api-us-west-1.sd-rtn.com" {
+		t.Errorf("GetCurrentUrl() = %s, want the resolved domain suffix", got)
+	}
+	if !reflect.DeepEqual(resolver.domains, []string{OverseaMajorDomain, ChineseMainlandMajorDomain}) || resolver.regionPrefix != USWestRegionDomainPrefix {
+		t.Errorf("Resolve(%v, %s), want the domain suffixes and the first region prefix of US", resolver.domains, resolver.regionPrefix)
+	}
+
+	// Not resolved again within 30 seconds
+	_ = pool.SelectBestDomain(ctx)
+	if resolver.callCount() != 1 {
+		t.Errorf("resolver calls = %d, want 1", resolver.callCount())
+	}
+
+	// A failed resolution keeps the last known-good domain
+	errResolve := errors.New("resolve failed")
+	resolver.set("", errResolve)
+	pool.resolve(ctx)
+	if got := pool.GetCurrentUrl(); got != "https://api-us-west-1.sd-rtn.com" {
+		t.Errorf("GetCurrentUrl() = %s after a failed resolution, want the last known-good domain", got)
+	}
+	if len(resolveErrors) != 1 || !errors.Is(resolveErrors[0], errResolve) {
+		t.Errorf("resolve errors = %v, want [%v]", resolveErrors, errResolve)
+	}
+
+	// A domain that is not one of the suffixes is ignored
+	resolver.set("example.com", nil)
+	pool.resolve(ctx)
+	if got := pool.GetCurrentUrl(); got != "https://api-us-west-1.sd-rtn.com" {
+		t.Errorf("GetCurrentUrl() = %s after resolving an unknown domain, want the last known-good domain", got)
+	}
+}
+
+func TestCustomDomain(t *testing.T) {
+	resolver := &fakeResolver{domain: "example.cn"}
+	pool, err := NewPool(CN, log.DiscardLogger, WithResolver(resolver), WithDomain(Domain{
+		RegionDomainPrefixes: []string{"api-private-1", "api-private-2"},
+		MajorDomainSuffixes:  []string{"example.com", "example.cn"},
+	}))
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+
+	if got := pool.GetCurrentUrl(); got != "https://api-private-1.example.com" {
+		t.Errorf("GetCurrentUrl() = %s, want the first custom region prefix and domain suffix", got)
+	}
+	_ = pool.SelectBestDomain(context.Background())
+	if !reflect.DeepEqual(resolver.domains, []string{"example.com", "example.cn"}) || resolver.regionPrefix != "api-private-1" {
+		t.Errorf("Resolve(%v, %s), want the custom domain suffixes and region prefix", resolver.domains, resolver.regionPrefix)
+	}
+	if got := pool.GetCurrentUrl(); got != "https://api-private-1.example.cn" {
+		t.Errorf("GetCurrentUrl() = %s, want the resolved custom domain suffix", got)
+	}
+	if !pool.HasRegionPrefix("api-private-2") || pool.HasRegionPrefix(CNEastRegionDomainPrefix) {
+		t.Error("HasRegionPrefix() does not match the custom region prefixes")
+	}
+
+	snapshot := pool.Snapshot()
+	var endpoints []Endpoint
+	for _, endpoint := range snapshot.Endpoints {
+		endpoints = append(endpoints, endpoint.Endpoint)
+	}
+	want := []Endpoint{
+		{RegionPrefix: "api-private-1", DomainSuffix: "example.cn"},
+		{RegionPrefix: "api-private-1", DomainSuffix: "example.com"},
+		{RegionPrefix: "api-private-2", DomainSuffix: "example.cn"},
+		{RegionPrefix: "api-private-2", DomainSuffix: "example.com"},
+	}
+	if !reflect.DeepEqual(endpoints, want) {
+		t.Errorf("endpoints = %v, want %v", endpoints, want)
+	}
+}
+
+func TestInvalidDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		area    Area
+		options []PoolOption
+	}{
+		{name: "unknown area", area: Area(-1)},
+		{name: "no region prefix", area: US, options: []PoolOption{WithDomain(Domain{MajorDomainSuffixes: []string{"example.com"}})}},
+		{name: "no domain suffix", area: US, options: []PoolOption{WithDomain(Domain{RegionDomainPrefixes: []string{"api"}})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPool(tt.area, log.DiscardLogger, tt.options...); err == nil {
+				t.Error("NewPool() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestFixedBaseURL(t *testing.T) {
+	resolver := &fakeResolver{domain: ChineseMainlandMajorDomain}
+	// The domain area is ignored
+	pool, err := NewPool(Area(-1), log.DiscardLogger, WithResolver(resolver), WithBaseURL("https://gateway.example.com/"))
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+
+	if err = pool.SelectBestDomain(context.Background()); err != nil {
+		t.Fatalf("SelectBestDomain() error = %v", err)
+	}
+	if resolver.callCount() != 0 {
+		t.Errorf("resolver calls = %d, want 0", resolver.callCount())
+	}
+
+	pool.ReportResult("", "", 0, false)
+	pool.NextRegion()
+	baseURL, regionPrefix, domainSuffix := pool.Current()
+	if baseURL != "https://gateway.example.com" || regionPrefix != "" || domainSuffix != "" {
+		t.Errorf("Current() = %s, %s, %s, want the base URL without the trailing slash", baseURL, regionPrefix, domainSuffix)
+	}
+
+	var allowed []string
+	if _, _, _, ok := pool.Select(func(baseURL string) bool {
+		allowed = append(allowed, baseURL)
+		return false
+	}); ok || !reflect.DeepEqual(allowed, []string{"https://gateway.example.com"}) {
+		t.Errorf("Select() = %t with %v allowed, want false with only the base URL", ok, allowed)
+	}
+
+	if snapshot := pool.Snapshot(); snapshot.BaseURL != "https://gateway.example.com" || len(snapshot.Endpoints) != 0 {
+		t.Errorf("Snapshot() = %+v, want only the base URL", snapshot)
+	}
+}
@@ -0,0 +1,292 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// @brief Policy of the endpoint health tracking of the Pool
+//
+// @since v0.13.0
+type HealthPolicy struct {
+	// Number of consecutive failures after which an endpoint is marked unhealthy
+	//
+	// The default value 3 is used when it is less than 1.
+	FailureThreshold int
+	// Duration an unhealthy endpoint is avoided, it is also the duration a failure is considered recent
+	Cooldown time.Duration
+	// Weight of the latest outcome in the moving averages of the success rate and the latency, in (0,1]
+	//
+	// The default value 0.2 is used when it is not greater than 0, and 1 is used when it is greater than 1.
+	Smoothing float64
+}
+
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = 30 * time.Second
+	defaultSmoothing        = 0.2
+)
+
+// @brief Returns the default health policy
+//
+// @note 3 consecutive failures mark an endpoint unhealthy for 30 seconds.
+//
+// @since v0.13.0
+func DefaultHealthPolicy() *HealthPolicy {
+	return &HealthPolicy{
+		FailureThreshold: defaultFailureThreshold,
+		Cooldown:         defaultCooldown,
+		Smoothing:        defaultSmoothing,
+	}
+}
+
+// normalized returns a copy of the policy whose out of range values are replaced, see HealthPolicy.
+func (p HealthPolicy) normalized() *HealthPolicy {
+	if p.FailureThreshold < 1 {
+		p.FailureThreshold = defaultFailureThreshold
+	}
+	if p.Smoothing <= 0 {
+		p.Smoothing = defaultSmoothing
+	} else if p.Smoothing > 1 {
+		p.Smoothing = 1
+	}
+	return &p
+}
+
+// @brief Endpoint, a pair of region prefix and domain suffix
+//
+// @since v0.13.0
+type Endpoint struct {
+	RegionPrefix string
+	DomainSuffix string
+}
+
+func (e Endpoint) baseURL() string {
+	return fmt.Sprintf("https://%s.%s", e.RegionPrefix, e.DomainSuffix)
+}
+
+// @brief Health state of an endpoint, see Pool.Snapshot
+//
+// @since v0.13.0
+type EndpointSnapshot struct {
+	Endpoint
+	// Whether the endpoint is not in cooldown
+	Healthy bool
+	// End of the cooldown, zero if the endpoint has never been unhealthy
+	UnhealthyUntil time.Time
+	// Moving average of the success rate, 1 if the endpoint has not been used
+	SuccessRate float64
+	// Moving average of the latency, 0 if the endpoint has not been used
+	Latency time.Duration
+	// Total number of successful requests
+	Successes int64
+	// Total number of failed requests
+	Failures int64
+	// Number of failures since the last success
+	ConsecutiveFailures int
+	// Time of the last failure, zero if the endpoint has never failed
+	LastFailure time.Time
+}
+
+// @brief State of the Pool, see Pool.Snapshot
+//
+// @since v0.13.0
+type PoolSnapshot struct {
+	// Fixed base URL, empty if the pool selects endpoints. See WithBaseURL for details.
+	BaseURL string
+	// Endpoint requests are currently sent to
+	Current Endpoint
+	// Health state of every endpoint, in order of preference
+	Endpoints []EndpointSnapshot
+}
+
+type endpointHealth struct {
+	successRate         float64
+	latency             time.Duration
+	successes           int64
+	failures            int64
+	consecutiveFailures int
+	lastFailure         time.Time
+	unhealthyUntil      time.Time
+}
+
+func newEndpointHealth() *endpointHealth {
+	return &endpointHealth{successRate: 1}
+}
+
+func (h *endpointHealth) record(policy *HealthPolicy, latency time.Duration, success bool, now time.Time) {
+	outcome := 0.0
+	if success {
+		outcome = 1
+	}
+	h.successRate += policy.Smoothing * (outcome - h.successRate)
+
+	if success {
+		if h.successes == 0 {
+			h.latency = latency
+		} else {
+			h.latency += time.Duration(policy.Smoothing * float64(latency-h.latency))
+		}
+		h.successes++
+		h.consecutiveFailures = 0
+		return
+	}
+
+	h.failures++
+	h.consecutiveFailures++
+	h.lastFailure = now
+	if h.consecutiveFailures >= policy.FailureThreshold {
+		h.unhealthyUntil = now.Add(policy.Cooldown)
+	}
+}
+
+func (h *endpointHealth) healthy(now time.Time) bool {
+	return !now.Before(h.unhealthyUntil)
+}
+
+// failedRecently reports whether the endpoint failed since its last success within the cooldown.
+func (h *endpointHealth) failedRecently(policy *HealthPolicy, now time.Time) bool {
+	return h.consecutiveFailures > 0 && now.Sub(h.lastFailure) < policy.Cooldown
+}
+
+// score is the latency weighted by the success rate, the lower the better.
+func (h *endpointHealth) score() float64 {
+	successRate := h.successRate
+	if successRate < 0.05 {
+		successRate = 0.05
+	}
+	return float64(h.latency) / (successRate * successRate)
+}
+
+// rank returns the group of the endpoint, the lower the better:
+//
+//  0. healthy and used without recent failures, ordered by score
+//  1. healthy and never used, ordered by preference
+//  2. healthy with recent failures, ordered by the number of failures
+//  3. unhealthy, ordered by the end of the cooldown
+func (h *endpointHealth) rank(policy *HealthPolicy, now time.Time) int {
+	switch {
+	case !h.healthy(now):
+		return 3
+	case h.failedRecently(policy, now):
+		return 2
+	case h.successes == 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// preferredEndpoints returns the endpoints in order of preference:
+// region prefixes in their current order, and the selected domain suffix before the others.
+func (d *Pool) preferredEndpoints() []Endpoint {
+	suffixes := make([]string, 0, len(d.domainSuffixes))
+	suffixes = append(suffixes, d.currentDomain)
+	for _, suffix := range d.domainSuffixes {
+		if suffix != d.currentDomain {
+			suffixes = append(suffixes, suffix)
+		}
+	}
+
+	endpoints := make([]Endpoint, 0, len(d.currentRegionPrefixes)*len(suffixes))
+	for _, prefix := range d.currentRegionPrefixes {
+		for _, suffix := range suffixes {
+			endpoints = append(endpoints, Endpoint{RegionPrefix: prefix, DomainSuffix: suffix})
+		}
+	}
+	return endpoints
+}
+
+func (d *Pool) endpointHealth(endpoint Endpoint) *endpointHealth {
+	h, ok := d.health[endpoint]
+	if !ok {
+		h = newEndpointHealth()
+		d.health[endpoint] = h
+	}
+	return h
+}
+
+// bestEndpoint returns the best endpoint, see endpointHealth.rank. It must be called with the lock held.
+func (d *Pool) bestEndpoint(now time.Time) Endpoint {
+	return d.rankedEndpoints(now)[0]
+}
+
+// rankedEndpoints returns the endpoints from the best to the worst. It must be called with the lock held.
+func (d *Pool) rankedEndpoints(now time.Time) []Endpoint {
+	endpoints := d.preferredEndpoints()
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		hi, hj := d.endpointHealth(endpoints[i]), d.endpointHealth(endpoints[j])
+		ri, rj := hi.rank(d.healthPolicy, now), hj.rank(d.healthPolicy, now)
+		if ri != rj {
+			return ri < rj
+		}
+		switch ri {
+		case 0:
+			return hi.score() < hj.score()
+		case 2:
+			return hi.consecutiveFailures < hj.consecutiveFailures
+		case 3:
+			return hi.unhealthyUntil.Before(hj.unhealthyUntil)
+		default:
+			return false
+		}
+	})
+	return endpoints
+}
+
+// @brief Records the outcome of a request sent to an endpoint
+//
+// @param latency Duration of the request
+//
+// @param success Whether a response is received, a transport error or a 5xx status code is a failure
+//
+// @since v0.13.0
+func (d *Pool) ReportResult(regionPrefix string, domainSuffix string, latency time.Duration, success bool) {
+	if d.baseURL != "" {
+		return
+	}
+
+	d.locker.Lock()
+	defer d.locker.Unlock()
+
+	now := time.Now()
+	h := d.endpointHealth(Endpoint{RegionPrefix: regionPrefix, DomainSuffix: domainSuffix})
+	wasHealthy := h.healthy(now)
+	h.record(d.healthPolicy, latency, success, now)
+	if wasHealthy && !h.healthy(now) {
+		d.logger.Warnf(context.Background(), d.module, "endpoint %s.%s is unhealthy after %d consecutive failures, cooldown:%s",
+			regionPrefix, domainSuffix, h.consecutiveFailures, d.healthPolicy.Cooldown)
+	}
+}
+
+// @brief Returns the state of the pool, e.g. for dashboards
+//
+// @since v0.13.0
+func (d *Pool) Snapshot() PoolSnapshot {
+	if d.baseURL != "" {
+		return PoolSnapshot{BaseURL: d.baseURL}
+	}
+
+	d.locker.Lock()
+	defer d.locker.Unlock()
+
+	now := time.Now()
+	snapshot := PoolSnapshot{Current: d.bestEndpoint(now)}
+	for _, endpoint := range d.preferredEndpoints() {
+		h := d.endpointHealth(endpoint)
+		snapshot.Endpoints = append(snapshot.Endpoints, EndpointSnapshot{
+			Endpoint:            endpoint,
+			Healthy:             h.healthy(now),
+			UnhealthyUntil:      h.unhealthyUntil,
+			SuccessRate:         h.successRate,
+			Latency:             h.latency,
+			Successes:           h.successes,
+			Failures:            h.failures,
+			ConsecutiveFailures: h.consecutiveFailures,
+			LastFailure:         h.lastFailure,
+		})
+	}
+	return snapshot
+}
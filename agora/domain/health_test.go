@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+)
+
+func TestHealthPolicyNormalized(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy HealthPolicy
+		want   HealthPolicy
+	}{
+		{
+			name:   "zero values",
+			policy: HealthPolicy{},
+			want:   HealthPolicy{FailureThreshold: 3, Smoothing: 0.2},
+		},
+		{
+			name:   "out of range",
+			policy: HealthPolicy{FailureThreshold: -1, Cooldown: time.Second, Smoothing: 1.5},
+			want:   HealthPolicy{FailureThreshold: 3, Cooldown: time.Second, Smoothing: 1},
+		},
+		{
+			name:   "valid",
+			policy: HealthPolicy{FailureThreshold: 1, Cooldown: time.Minute, Smoothing: 0.5},
+			want:   HealthPolicy{FailureThreshold: 1, Cooldown: time.Minute, Smoothing: 0.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.normalized(); *got != tt.want {
+				t.Errorf("normalized() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestPool returns a pool of 2 region prefixes and 2 domain suffixes.
+func newTestPool(t *testing.T, policy *HealthPolicy) *Pool {
+	t.Helper()
+
+	pool, err := NewPool(US, log.DiscardLogger, WithHealthPolicy(policy), WithDomain(Domain{
+		RegionDomainPrefixes: []string{"api-1", "api-2"},
+		MajorDomainSuffixes:  []string{"agora.io", "sd-rtn.com"},
+	}))
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	return pool
+}
+
+func snapshotOf(pool *Pool, endpoint Endpoint) EndpointSnapshot {
+	for _, snapshot := range pool.Snapshot().Endpoints {
+		if snapshot.Endpoint == endpoint {
+			return snapshot
+		}
+	}
+	return EndpointSnapshot{}
+}
+
+func TestRanking(t *testing.T) {
+	var (
+		first  = Endpoint{RegionPrefix: "api-1", DomainSuffix: "agora.io"}
+		second = Endpoint{RegionPrefix: "api-1", DomainSuffix: "sd-rtn.com"}
+		third  = Endpoint{RegionPrefix: "api-2", DomainSuffix: "agora.io"}
+		fourth = Endpoint{RegionPrefix: "api-2", DomainSuffix: "sd-rtn.com"}
+	)
+	tests := []struct {
+		name   string
+		report func(pool *Pool)
+		want   []Endpoint
+	}{
+		{
+			name:   "order of preference when unused",
+			report: func(pool *Pool) {},
+			want:   []Endpoint{first, second, third, fourth},
+		},
+		{
+			name: "used endpoints by latency before unused ones",
+			report: func(pool *Pool) {
+				pool.ReportResult("api-2", "agora.io", 100*time.Millisecond, true)
+				pool.ReportResult("api-2", "sd-rtn.com", 10*time.Millisecond, true)
+			},
+			want: []Endpoint{fourth, third, first, second},
+		},
+		{
+			name: "recent failures after unused endpoints, by number of failures",
+			report: func(pool *Pool) {
+				pool.ReportResult("api-1", "agora.io", 0, false)
+				pool.ReportResult("api-1", "agora.io", 0, false)
+				pool.ReportResult("api-1", "sd-rtn.com", 0, false)
+			},
+			want: []Endpoint{third, fourth, second, first},
+		},
+		{
+			name: "unhealthy last",
+			report: func(pool *Pool) {
+				for i := 0; i < 3; i++ {
+					pool.ReportResult("api-1", "agora.io", 0, false)
+				}
+				pool.ReportResult("api-1", "sd-rtn.com", 0, false)
+			},
+			want: []Endpoint{third, fourth, second, first},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newTestPool(t, &HealthPolicy{FailureThreshold: 3, Cooldown: time.Minute, Smoothing: 0.5})
+			tt.report(pool)
+
+			pool.locker.Lock()
+			got := pool.rankedEndpoints(time.Now())
+			pool.locker.Unlock()
+			if len(got) != len(tt.want) {
+				t.Fatalf("ranked endpoints = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("ranked endpoints = %v, want %v", got, tt.want)
+				}
+			}
+			if current := pool.Snapshot().Current; current != tt.want[0] {
+				t.Errorf("Snapshot().Current = %v, want %v", current, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestUnhealthyRecovery(t *testing.T) {
+	pool := newTestPool(t, &HealthPolicy{FailureThreshold: 2, Cooldown: 30 * time.Millisecond, Smoothing: 0.5})
+	first := Endpoint{RegionPrefix: "api-1", DomainSuffix: "agora.io"}
+
+	before := time.Now()
+	pool.ReportResult("api-1", "agora.io", 0, false)
+	if !snapshotOf(pool, first).Healthy {
+		t.Fatal("endpoint unhealthy after 1 failure, want healthy below the threshold")
+	}
+	pool.ReportResult("api-1", "agora.io", 0, false)
+
+	snapshot := snapshotOf(pool, first)
+	if snapshot.Healthy || snapshot.ConsecutiveFailures != 2 || snapshot.Failures != 2 {
+		t.Fatalf("snapshot = %+v, want unhealthy after 2 consecutive failures", snapshot)
+	}
+	if snapshot.UnhealthyUntil.Before(before.Add(30*time.Millisecond)) || snapshot.LastFailure.Before(before) {
+		t.Errorf("snapshot = %+v, want the cooldown to end 30ms after the last failure", snapshot)
+	}
+	if baseURL, _, _ := pool.Current(); baseURL == "https://api-1.agora.io" {
+		t.Error("Current() is the unhealthy endpoint")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if !snapshotOf(pool, first).Healthy {
+		t.Fatal("endpoint unhealthy after the cooldown, want healthy")
+	}
+	// Neither recently failed nor used with success, it is back in the order of preference
+	if baseURL, _, _ := pool.Current(); baseURL != "https://api-1.agora.io" {
+		t.Errorf("Current() = %s after the cooldown, want https://api-1.agora.io", baseURL)
+	}
+
+	pool.ReportResult("api-1", "agora.io", 20*time.Millisecond, true)
+	snapshot = snapshotOf(pool, first)
+	if snapshot.ConsecutiveFailures != 0 || snapshot.Successes != 1 || snapshot.Latency != 20*time.Millisecond {
+		t.Errorf("snapshot = %+v, want the failures reset by the success", snapshot)
+	}
+	// 1 → 0.5 → 0.25 → 0.625
+	if snapshot.SuccessRate != 0.625 {
+		t.Errorf("SuccessRate = %v, want 0.625", snapshot.SuccessRate)
+	}
+}
+
+func TestDefaultsOfPartialHealthPolicy(t *testing.T) {
+	// Only the cooldown is set
+	pool := newTestPool(t, &HealthPolicy{Cooldown: time.Minute})
+	first := Endpoint{RegionPrefix: "api-1", DomainSuffix: "agora.io"}
+
+	pool.ReportResult("api-1", "agora.io", 0, false)
+	snapshot := snapshotOf(pool, first)
+	if !snapshot.Healthy {
+		t.Error("endpoint unhealthy after 1 failure, want the default threshold of 3")
+	}
+	if snapshot.SuccessRate != 0.8 {
+		t.Errorf("SuccessRate = %v after 1 failure, want 0.8 with the default smoothing", snapshot.SuccessRate)
+	}
+
+	pool.ReportResult("api-1", "agora.io", 0, false)
+	pool.ReportResult("api-1", "agora.io", 0, false)
+	if snapshotOf(pool, first).Healthy {
+		t.Error("endpoint healthy after 3 failures, want unhealthy")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	pool := newTestPool(t, DefaultHealthPolicy())
+	pool.ReportResult("api-2", "sd-rtn.com", 10*time.Millisecond, true)
+
+	snapshot := pool.Snapshot()
+	if snapshot.BaseURL != "" || snapshot.Current != (Endpoint{RegionPrefix: "api-2", DomainSuffix: "sd-rtn.com"}) {
+		t.Errorf("Snapshot() = %+v, want the used endpoint as the current one", snapshot)
+	}
+	// In order of preference, not in the ranked order
+	want := []Endpoint{
+		{RegionPrefix: "api-1", DomainSuffix: "agora.io"},
+		{RegionPrefix: "api-1", DomainSuffix: "sd-rtn.com"},
+		{RegionPrefix: "api-2", DomainSuffix: "agora.io"},
+		{RegionPrefix: "api-2", DomainSuffix: "sd-rtn.com"},
+	}
+	if len(snapshot.Endpoints) != len(want) {
+		t.Fatalf("Snapshot().Endpoints = %+v, want %v", snapshot.Endpoints, want)
+	}
+	for i, endpoint := range snapshot.Endpoints {
+		if endpoint.Endpoint != want[i] {
+			t.Errorf("Snapshot().Endpoints[%d] = %v, want %v", i, endpoint.Endpoint, want[i])
+		}
+		if i < 3 && (endpoint.SuccessRate != 1 || endpoint.Successes != 0 || !endpoint.Healthy) {
+			t.Errorf("Snapshot().Endpoints[%d] = %+v, want an unused healthy endpoint", i, endpoint)
+		}
+	}
+	if used := snapshot.Endpoints[3]; used.Successes != 1 || used.Latency != 10*time.Millisecond {
+		t.Errorf("Snapshot().Endpoints[3] = %+v, want 1 success with a 10ms latency", used)
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// maxResolveTimeout is the maximum time a background resolution may take
+const maxResolveTimeout = 10 * time.Second
+
+// @brief Resolve the domain in a background goroutine every interval, instead of when a request is sent
+//
+// @note Call Pool.Close to stop the goroutine.
+//
+// @since v0.13.0
+func WithBackgroundRefresh(interval time.Duration) PoolOption {
+	return func(o *poolOptions) {
+		o.refreshInterval = interval
+	}
+}
+
+// @brief Set the handler of domain resolution errors
+//
+// @note The last known-good domain is kept when the resolution fails, requests are not affected.
+//
+// @since v0.13.0
+func WithResolveErrorHandler(handler func(err error)) PoolOption {
+	return func(o *poolOptions) {
+		o.onResolveError = handler
+	}
+}
+
+func (d *Pool) startRefresher() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.stopRefresher = cancel
+	d.refresherDone = make(chan struct{})
+
+	timeout := d.refreshInterval
+	if timeout > maxResolveTimeout {
+		timeout = maxResolveTimeout
+	}
+
+	go func() {
+		defer close(d.refresherDone)
+
+		ticker := time.NewTicker(d.refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			resolveCtx, cancelResolve := context.WithTimeout(ctx, timeout)
+			d.resolve(resolveCtx)
+			cancelResolve()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// @brief Stop the background refresher, see WithBackgroundRefresh
+//
+// @note It waits for the refresher to exit. It is safe to call it more than once.
+//
+// @since v0.13.0
+func (d *Pool) Close() error {
+	d.closeOnce.Do(func() {
+		if d.stopRefresher != nil {
+			d.stopRefresher()
+			<-d.refresherDone
+		}
+	})
+	return nil
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+)
+
+// waitFor polls condition until it is true or a second elapsed.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBackgroundRefresh(t *testing.T) {
+	resolver := &fakeResolver{domain: ChineseMainlandMajorDomain}
+	var (
+		locker        sync.Mutex
+		resolveErrors []error
+	)
+	pool, err := NewPool(US, log.DiscardLogger, WithResolver(resolver), WithBackgroundRefresh(10*time.Millisecond),
+		WithResolveErrorHandler(func(err error) {
+			locker.Lock()
+			resolveErrors = append(resolveErrors, err)
+			locker.Unlock()
+		}))
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	defer pool.Close()
+
+	// Resolved when the pool is created, then every interval
+	waitFor(t, func() bool { return resolver.callCount() >= 3 })
+	if got := pool.GetCurrentUrl(); got != "https://api-us-west-1.sd-rtn.com" {
+		t.Errorf("GetCurrentUrl() = %s, want the domain resolved in the background", got)
+	}
+
+	// The last known-good domain is kept when the resolution fails
+	errResolve := errors.New("resolve failed")
+	resolver.set("", errResolve)
+	waitFor(t, func() bool {
+		locker.Lock()
+		defer locker.Unlock()
+		return len(resolveErrors) > 0
+	})
+	if got := pool.GetCurrentUrl(); got != "https://api-us-west-1.sd-rtn.com" {
+		t.Errorf("GetCurrentUrl() = %s after a failed refresh, want the last known-good domain", got)
+	}
+	locker.Lock()
+	if !errors.Is(resolveErrors[0], errResolve) {
+		t.Errorf("resolve error = %v, want %v", resolveErrors[0], errResolve)
+	}
+	locker.Unlock()
+
+	// Recovers with the next successful refresh
+	resolver.set(OverseaMajorDomain, nil)
+	waitFor(t, func() bool { return pool.GetCurrentUrl() == "https://api-us-west-1.agora.io" })
+}
+
+func TestBackgroundRefreshSkipsRequests(t *testing.T) {
+	resolver := &fakeResolver{domain: OverseaMajorDomain}
+	pool, err := NewPool(US, log.DiscardLogger, WithResolver(resolver), WithBackgroundRefresh(time.Hour))
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	defer pool.Close()
+	waitFor(t, func() bool { return resolver.callCount() == 1 })
+
+	// Even when the domain has not been resolved for 30 seconds
+	pool.locker.Lock()
+	pool.lastUpdate = time.Time{}
+	pool.locker.Unlock()
+	if err = pool.SelectBestDomain(context.Background()); err != nil {
+		t.Fatalf("SelectBestDomain() error = %v", err)
+	}
+	if resolver.callCount() != 1 {
+		t.Errorf("resolver calls = %d, want the domain only resolved in the background", resolver.callCount())
+	}
+}
+
+func TestCloseStopsRefresher(t *testing.T) {
+	resolver := &fakeResolver{domain: OverseaMajorDomain}
+	pool, err := NewPool(US, log.DiscardLogger, WithResolver(resolver), WithBackgroundRefresh(time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	waitFor(t, func() bool { return resolver.callCount() >= 2 })
+
+	if err = pool.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	select {
+	case <-pool.refresherDone:
+	default:
+		t.Fatal("the refresher is running after Close returned")
+	}
+	calls := resolver.callCount()
+	time.Sleep(10 * time.Millisecond)
+	if resolver.callCount() != calls {
+		t.Errorf("resolver calls = %d after Close, want %d", resolver.callCount(), calls)
+	}
+
+	// Safe to call more than once
+	if err = pool.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
+
+func TestCloseWithoutRefresher(t *testing.T) {
+	pool, err := NewPool(US, log.DiscardLogger, WithResolver(&fakeResolver{domain: OverseaMajorDomain}))
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	if err = pool.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if err = pool.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+}
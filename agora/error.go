@@ -2,6 +2,7 @@ package agora
 
 import (
 	"fmt"
+	"time"
 )
 
 type InternalErr struct {
@@ -52,3 +53,35 @@ func NewGatewayErr(code int, msg string) *GatewayErr {
 func (g *GatewayErr) Error() string {
 	return fmt.Sprintf("statusCode:%d,body:%s", g.Code, g.Msg)
 }
+
+// @brief ErrCircuitOpen is returned without sending the request when the circuit breakers of all the endpoints are open
+//
+// @note Use errors.As to check for it. See Config.CircuitBreaker for details.
+//
+// @since v0.13.0
+type ErrCircuitOpen struct {
+	// Host of the best endpoint
+	Host string
+	// End of the open state of the circuit of Host, requests are let through again after it
+	OpenUntil time.Time
+}
+
+func (e *ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit breaker of %s is open until %s", e.Host, e.OpenUntil.Format(time.RFC3339))
+}
+
+// @brief ErrResponseTooLarge is returned when a response body exceeds Config.MaxResponseBodySize
+//
+// @note Use errors.As to check for it. The request is not retried in this case.
+//
+// @since v0.13.0
+type ErrResponseTooLarge struct {
+	// Name of the API operation, e.g. "convoai:history"
+	Module string
+	// Maximum size of a response body in bytes
+	Limit int64
+}
+
+func (e *ErrResponseTooLarge) Error() string {
+	return fmt.Sprintf("response body of %s exceeds %d bytes", e.Module, e.Limit)
+}
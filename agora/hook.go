@@ -0,0 +1,57 @@
+package agora
+
+import (
+	"context"
+	"net/http"
+)
+
+// @brief Metadata of the HTTP attempt a Hook runs for
+//
+// @since v0.13.0
+type HookInfo struct {
+	// Service operation of the call, e.g. Module "cloudRecording:start"
+	CallInfo
+	// Attempt number within the call, starting at 1
+	Attempt int
+	// Whether the call is idempotent, i.e. it does not mutate anything, or mutates it the same way when repeated
+	Idempotent bool
+}
+
+// @brief Hook runs around every HTTP attempt of every call, e.g. to add headers, audit calls or capture bodies
+//
+// @note Hooks run in the order they are registered in Config.Hooks.
+//
+// @since v0.13.0
+type Hook interface {
+	// BeforeRequest is called right before the request is sent, once the credential and the default headers are set.
+	//
+	// The request body can be read with req.GetBody. Returning an error fails the call without sending the request.
+	BeforeRequest(ctx context.Context, info HookInfo, req *http.Request) error
+	// AfterResponse is called once the response body is read, or once the attempt failed.
+	//
+	// resp is nil when err is not nil. A response with an unsuccessful HTTP status code is passed with a nil err.
+	AfterResponse(ctx context.Context, info HookInfo, resp *BaseResponse, err error)
+}
+
+// @brief HookFuncs is an adapter to build a Hook from functions, nil functions are skipped
+//
+// @since v0.13.0
+type HookFuncs struct {
+	Before func(ctx context.Context, info HookInfo, req *http.Request) error
+	After  func(ctx context.Context, info HookInfo, resp *BaseResponse, err error)
+}
+
+// BeforeRequest calls h.Before if it is set
+func (h HookFuncs) BeforeRequest(ctx context.Context, info HookInfo, req *http.Request) error {
+	if h.Before == nil {
+		return nil
+	}
+	return h.Before(ctx, info, req)
+}
+
+// AfterResponse calls h.After if it is set
+func (h HookFuncs) AfterResponse(ctx context.Context, info HookInfo, resp *BaseResponse, err error) {
+	if h.After != nil {
+		h.After(ctx, info, resp, err)
+	}
+}
@@ -0,0 +1,86 @@
+package agora
+
+import (
+	"context"
+)
+
+// @brief CallInfo describes a logical API call, which consists of one or more HTTP attempts
+//
+// @since v0.13.0
+type CallInfo struct {
+	// Name of the API operation, e.g. "cloudRecording:start"
+	Module string
+	// HTTP method
+	Method string
+	// Request path, including the query string
+	Path string
+}
+
+// @brief AttemptInfo describes the result of an HTTP attempt of an API call
+//
+// @since v0.13.0
+type AttemptInfo struct {
+	// Number of the attempt in the call, starting from 1
+	Attempt int
+	// Region prefix of the domain the attempt was sent to, e.g. "api-us-west-1"
+	RegionPrefix string
+	// Domain suffix the attempt was sent to, e.g. "agora.io"
+	DomainSuffix string
+	// HTTP status code, 0 if no response was received
+	HttpStatusCode int
+	// Request ID of the response, empty if no response was received
+	RequestID string
+}
+
+// @brief Instrumentation observes the API calls made by the REST Client, e.g. to emit traces and metrics
+//
+// @note Set it in Config.Instrumentation. See the otelinstrumentation module for an OpenTelemetry implementation.
+//
+// @since v0.13.0
+type Instrumentation interface {
+	// StartCall is called when an API call starts. The returned context is used by all attempts of the call.
+	StartCall(ctx context.Context, info CallInfo) (context.Context, CallObserver)
+}
+
+// @brief CallObserver observes a single API call
+//
+// @since v0.13.0
+type CallObserver interface {
+	// StartAttempt is called before each HTTP attempt of the call. The returned context is used by the HTTP request.
+	StartAttempt(ctx context.Context, attempt int) (context.Context, AttemptObserver)
+	// End is called when the call finishes. The response is nil if no response was received.
+	End(response *BaseResponse, err error)
+}
+
+// @brief AttemptObserver observes a single HTTP attempt of an API call
+//
+// @since v0.13.0
+type AttemptObserver interface {
+	// End is called when the attempt finishes.
+	End(info AttemptInfo, err error)
+}
+
+type nopInstrumentation struct{}
+
+type nopObserver struct{}
+
+type nopAttemptObserver struct{}
+
+// @brief NopInstrumentation returns an Instrumentation that observes nothing
+//
+// @since v0.13.0
+func NopInstrumentation() Instrumentation {
+	return nopInstrumentation{}
+}
+
+func (nopInstrumentation) StartCall(ctx context.Context, _ CallInfo) (context.Context, CallObserver) {
+	return ctx, nopObserver{}
+}
+
+func (nopObserver) StartAttempt(ctx context.Context, _ int) (context.Context, AttemptObserver) {
+	return ctx, nopAttemptObserver{}
+}
+
+func (nopObserver) End(_ *BaseResponse, _ error) {}
+
+func (nopAttemptObserver) End(_ AttemptInfo, _ error) {}
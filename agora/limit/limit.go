@@ -0,0 +1,215 @@
+package limit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// @brief Limit of the requests sent by a service client or to one of its endpoints
+//
+// @note A zero Limit does not limit anything.
+//
+// @since v0.13.0
+type Limit struct {
+	// Number of requests allowed per second, the requests are limited by a token bucket.(Optional)
+	//
+	// The rate is not limited when it is 0.
+	QPS float64
+	// Size of the token bucket, i.e. the number of requests that can be sent at once.(Optional)
+	//
+	// The default value is QPS rounded up, and at least 1.
+	Burst int
+	// Maximum number of requests in flight.(Optional)
+	//
+	// The concurrency is not limited when it is 0.
+	MaxInFlight int
+}
+
+// @brief Statistics of a Limiter
+//
+// @since v0.13.0
+type Stats struct {
+	// Total number of requests admitted by the limiter
+	Admitted int64
+	// Total number of requests that had to wait before being admitted
+	Waited int64
+	// Total number of requests that gave up waiting because their context was done
+	Canceled int64
+	// Total time requests spent waiting
+	WaitTime time.Duration
+	// Longest time a request spent waiting
+	MaxWaitTime time.Duration
+	// Number of requests in flight
+	InFlight int
+}
+
+// @brief Limiter limits the rate and the concurrency of requests, see Limit
+//
+// @since v0.13.0
+type Limiter struct {
+	limit Limit
+	// nil if the concurrency is not limited
+	slots chan struct{}
+
+	locker *sync.Mutex
+	tokens float64
+	last   time.Time
+	stats  Stats
+}
+
+// @brief Creates a Limiter
+//
+// @param limit Limit of the requests. See Limit for details.
+//
+// @return Returns the Limiter instance.
+//
+// @since v0.13.0
+func NewLimiter(limit Limit) *Limiter {
+	if limit.QPS > 0 && limit.Burst < 1 {
+		limit.Burst = int(math.Max(math.Ceil(limit.QPS), 1))
+	}
+
+	l := &Limiter{
+		limit:  limit,
+		locker: &sync.Mutex{},
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+	if limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+
+	return l
+}
+
+// @brief Waits until a request can be sent
+//
+// @param ctx Context of the request, waiting stops when it is done.
+//
+// @return Returns the function to call once the request has completed, and the error of ctx if it is done before the request is admitted.
+//
+// @note An error is returned at once when the deadline of ctx is reached before a token is available.
+//
+// @since v0.13.0
+func (l *Limiter) Wait(ctx context.Context) (release func(), err error) {
+	start := time.Now()
+	defer func() {
+		l.record(time.Since(start), err)
+	}()
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			select {
+			case l.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, fmt.Errorf("wait for a request slot: %w", ctx.Err())
+			}
+		}
+	}
+	release = l.releaseSlot
+
+	if err = l.waitToken(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	l.locker.Lock()
+	l.stats.InFlight++
+	l.locker.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.locker.Lock()
+			l.stats.InFlight--
+			l.locker.Unlock()
+			l.releaseSlot()
+		})
+	}, nil
+}
+
+func (l *Limiter) releaseSlot() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// waitToken takes a token from the bucket, waiting for it to be refilled if it is empty.
+func (l *Limiter) waitToken(ctx context.Context) error {
+	if l.limit.QPS <= 0 {
+		return nil
+	}
+
+	l.locker.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.tokens+now.Sub(l.last).Seconds()*l.limit.QPS, float64(l.limit.Burst))
+	l.last = now
+	// The token is reserved now, the bucket goes negative while requests wait for it
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.limit.QPS * float64(time.Second))
+	l.locker.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		l.cancelToken()
+		return fmt.Errorf("wait for a rate limit token: %w", context.DeadlineExceeded)
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancelToken()
+		return fmt.Errorf("wait for a rate limit token: %w", ctx.Err())
+	}
+}
+
+// cancelToken gives a reserved token back to the bucket.
+func (l *Limiter) cancelToken() {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	l.tokens = math.Min(l.tokens+1, float64(l.limit.Burst))
+}
+
+func (l *Limiter) record(wait time.Duration, err error) {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	if err != nil {
+		l.stats.Canceled++
+	} else {
+		l.stats.Admitted++
+	}
+	// Waits shorter than a millisecond are the bookkeeping of the limiter itself
+	if wait >= time.Millisecond {
+		l.stats.Waited++
+		l.stats.WaitTime += wait
+		if wait > l.stats.MaxWaitTime {
+			l.stats.MaxWaitTime = wait
+		}
+	}
+}
+
+// @brief Returns the statistics of the limiter
+//
+// @return Returns a copy of the statistics. See Stats for details.
+//
+// @since v0.13.0
+func (l *Limiter) Stats() Stats {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	return l.stats
+}
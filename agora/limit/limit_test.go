@@ -0,0 +1,246 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewLimiterBurst(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit Limit
+		want  int
+	}{
+		{name: "rate not limited", limit: Limit{}, want: 0},
+		{name: "qps below 1", limit: Limit{QPS: 0.5}, want: 1},
+		{name: "integer qps", limit: Limit{QPS: 2}, want: 2},
+		{name: "fractional qps is rounded up", limit: Limit{QPS: 2.3}, want: 3},
+		{name: "explicit burst", limit: Limit{QPS: 2, Burst: 5}, want: 5},
+		{name: "negative burst", limit: Limit{QPS: 2, Burst: -1}, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.limit)
+			if l.limit.Burst != tt.want {
+				t.Errorf("Burst = %d, want %d", l.limit.Burst, tt.want)
+			}
+			if l.tokens != float64(tt.want) {
+				t.Errorf("tokens = %v, want a full bucket of %d", l.tokens, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiterTokens(t *testing.T) {
+	l := NewLimiter(Limit{QPS: 20, Burst: 3})
+
+	// The burst is admitted at once
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := l.Wait(context.Background())
+		if err != nil {
+			t.Fatalf("Wait() %d error = %v", i, err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+		t.Errorf("burst took %v, want no wait", elapsed)
+	}
+
+	// The next request waits for a token, 1/QPS = 50ms
+	start = time.Now()
+	release, err := l.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Wait() took %v, want about 50ms", elapsed)
+	}
+
+	stats := l.Stats()
+	if stats.Admitted != 4 {
+		t.Errorf("Admitted = %d, want 4", stats.Admitted)
+	}
+	if stats.Waited != 1 {
+		t.Errorf("Waited = %d, want 1", stats.Waited)
+	}
+	if stats.MaxWaitTime < 40*time.Millisecond || stats.WaitTime < stats.MaxWaitTime {
+		t.Errorf("MaxWaitTime = %v, WaitTime = %v, want about 50ms", stats.MaxWaitTime, stats.WaitTime)
+	}
+}
+
+func TestLimiterTokenGivenBack(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+		maxWait time.Duration
+	}{
+		{
+			// The deadline is before the token is available, the wait fails at once
+			name: "deadline before the token",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+			maxWait: 5 * time.Millisecond,
+		},
+		{
+			name: "canceled while waiting",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+			maxWait: 100 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(Limit{QPS: 1, Burst: 1})
+			release, err := l.Wait(context.Background())
+			if err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+			release()
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			start := time.Now()
+			if _, err = l.Wait(ctx); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Wait() error = %v, want %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > tt.maxWait {
+				t.Errorf("Wait() took %v, want at most %v", elapsed, tt.maxWait)
+			}
+
+			// The reserved token is given back, the bucket is not in debt for the failed request
+			l.locker.Lock()
+			tokens := l.tokens
+			l.locker.Unlock()
+			if tokens < -0.01 || tokens > 1 {
+				t.Errorf("tokens = %v, want in [0,1]", tokens)
+			}
+
+			stats := l.Stats()
+			if stats.Admitted != 1 || stats.Canceled != 1 {
+				t.Errorf("Admitted = %d, Canceled = %d, want 1 and 1", stats.Admitted, stats.Canceled)
+			}
+		})
+	}
+}
+
+func TestLimiterTokenNotAboveBurst(t *testing.T) {
+	l := NewLimiter(Limit{QPS: 1000, Burst: 2})
+	time.Sleep(20 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		release, err := l.Wait(context.Background())
+		if err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		release()
+	}
+	l.locker.Lock()
+	tokens := l.tokens
+	l.locker.Unlock()
+	if math.Abs(tokens) > 0.1 {
+		t.Errorf("tokens = %v after the burst, want about 0", tokens)
+	}
+}
+
+func TestLimiterSlots(t *testing.T) {
+	l := NewLimiter(Limit{MaxInFlight: 2})
+
+	release1, err := l.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	release2, err := l.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if got := l.Stats().InFlight; got != 2 {
+		t.Errorf("InFlight = %d, want 2", got)
+	}
+
+	// No slot is left
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err = l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// A waiting request is admitted when a slot is released
+	admitted := make(chan func())
+	go func() {
+		release, err := l.Wait(context.Background())
+		if err != nil {
+			t.Errorf("Wait() error = %v", err)
+			close(admitted)
+			return
+		}
+		admitted <- release
+	}()
+	select {
+	case <-admitted:
+		t.Fatal("request admitted while no slot is free")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release1()
+	// Releasing twice frees a single slot
+	release1()
+	var release3 func()
+	select {
+	case release3 = <-admitted:
+	case <-time.After(time.Second):
+		t.Fatal("request not admitted after a slot is released")
+	}
+	if got := l.Stats().InFlight; got != 2 {
+		t.Errorf("InFlight = %d, want 2", got)
+	}
+	if got := len(l.slots); got != 2 {
+		t.Errorf("slots in use = %d, want 2", got)
+	}
+
+	release2()
+	release3()
+	if got := l.Stats().InFlight; got != 0 {
+		t.Errorf("InFlight = %d, want 0", got)
+	}
+	if got := len(l.slots); got != 0 {
+		t.Errorf("slots in use = %d, want 0", got)
+	}
+
+	stats := l.Stats()
+	if stats.Admitted != 3 || stats.Canceled != 1 {
+		t.Errorf("Admitted = %d, Canceled = %d, want 3 and 1", stats.Admitted, stats.Canceled)
+	}
+}
+
+func TestLimiterSlotReleasedWhenTokenWaitFails(t *testing.T) {
+	l := NewLimiter(Limit{QPS: 1, Burst: 1, MaxInFlight: 1})
+	release, err := l.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := len(l.slots); got != 0 {
+		t.Errorf("slots in use = %d, want 0", got)
+	}
+	if got := l.Stats().InFlight; got != 0 {
+		t.Errorf("InFlight = %d, want 0", got)
+	}
+}
@@ -0,0 +1,132 @@
+package limit
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// @brief Policy limits the requests of a service client
+//
+// @note Every service client has its own limiters, the requests of different service clients are not limited together.
+//
+// @since v0.13.0
+type Policy struct {
+	// Limit shared by all the requests of the service client.(Optional)
+	Service Limit
+	// Limits of single endpoints, keyed by the endpoint name, e.g. "acquire" or "query".(Optional)
+	//
+	// A request to one of these endpoints is limited by both its endpoint limit and the service limit.
+	Endpoints map[string]Limit
+	// Function called when a request has waited for the limiters, e.g. to record a metric.(Optional)
+	//
+	// endpoint is the name of the endpoint of the request.
+	OnWait func(endpoint string, wait time.Duration)
+}
+
+// @brief Statistics of a Group
+//
+// @since v0.13.0
+type GroupStats struct {
+	// Statistics of the service limiter
+	Service Stats
+	// Statistics of the endpoint limiters, keyed by the endpoint name
+	Endpoints map[string]Stats
+}
+
+// @brief Group holds the limiters of a Policy
+//
+// @since v0.13.0
+type Group struct {
+	service   *Limiter
+	endpoints map[string]*Limiter
+	onWait    func(endpoint string, wait time.Duration)
+}
+
+// @brief Creates the limiters of a policy
+//
+// @param policy Policy of the limiters, nothing is limited when it is nil. See Policy for details.
+//
+// @return Returns the Group instance.
+//
+// @since v0.13.0
+func NewGroup(policy *Policy) *Group {
+	if policy == nil {
+		policy = &Policy{}
+	}
+
+	g := &Group{
+		service:   NewLimiter(policy.Service),
+		endpoints: make(map[string]*Limiter, len(policy.Endpoints)),
+		onWait:    policy.OnWait,
+	}
+	for endpoint, limit := range policy.Endpoints {
+		g.endpoints[endpoint] = NewLimiter(limit)
+	}
+
+	return g
+}
+
+// @brief Returns the endpoint name of a module, e.g. "acquire" for "cloudRecording:acquire"
+//
+// @since v0.13.0
+func EndpointName(module string) string {
+	if i := strings.LastIndex(module, ":"); i >= 0 {
+		return module[i+1:]
+	}
+	return module
+}
+
+// @brief Waits until a request to the endpoint can be sent
+//
+// @param ctx Context of the request, waiting stops when it is done.
+//
+// @param endpoint Name of the endpoint, see EndpointName.
+//
+// @return Returns the function to call once the request has completed, and the error of ctx if it is done before the request is admitted.
+//
+// @since v0.13.0
+func (g *Group) Wait(ctx context.Context, endpoint string) (func(), error) {
+	start := time.Now()
+
+	var releaseEndpoint func() = func() {}
+	if l, ok := g.endpoints[endpoint]; ok {
+		release, err := l.Wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+		releaseEndpoint = release
+	}
+
+	releaseService, err := g.service.Wait(ctx)
+	if err != nil {
+		releaseEndpoint()
+		return nil, err
+	}
+
+	if wait := time.Since(start); g.onWait != nil && wait >= time.Millisecond {
+		g.onWait(endpoint, wait)
+	}
+
+	return func() {
+		releaseService()
+		releaseEndpoint()
+	}, nil
+}
+
+// @brief Returns the statistics of the limiters
+//
+// @return Returns a copy of the statistics. See GroupStats for details.
+//
+// @since v0.13.0
+func (g *Group) Stats() GroupStats {
+	stats := GroupStats{
+		Service:   g.service.Stats(),
+		Endpoints: make(map[string]Stats, len(g.endpoints)),
+	}
+	for endpoint, l := range g.endpoints {
+		stats.Endpoints[endpoint] = l.Stats()
+	}
+
+	return stats
+}
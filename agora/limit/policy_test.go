@@ -0,0 +1,131 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEndpointName(t *testing.T) {
+	tests := []struct {
+		module string
+		want   string
+	}{
+		{module: "cloudRecording:acquire", want: "acquire"},
+		{module: "convoai:join", want: "join"},
+		{module: "a:b:query", want: "query"},
+		{module: "query", want: "query"},
+		{module: "cloudRecording:", want: ""},
+		{module: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := EndpointName(tt.module); got != tt.want {
+			t.Errorf("EndpointName(%q) = %q, want %q", tt.module, got, tt.want)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var waits []string
+	g := NewGroup(&Policy{
+		Service: Limit{MaxInFlight: 2},
+		Endpoints: map[string]Limit{
+			"acquire": {MaxInFlight: 1},
+		},
+		OnWait: func(endpoint string, wait time.Duration) {
+			waits = append(waits, endpoint)
+		},
+	})
+
+	releaseAcquire, err := g.Wait(context.Background(), "acquire")
+	if err != nil {
+		t.Fatalf("Wait(acquire) error = %v", err)
+	}
+
+	// The endpoint limit is reached, the service slot is not taken
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = g.Wait(ctx, "acquire"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait(acquire) error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := g.Stats().Service.InFlight; got != 1 {
+		t.Errorf("service InFlight = %d, want 1", got)
+	}
+
+	// Endpoints without a limit are only limited by the service limit
+	releaseQuery, err := g.Wait(context.Background(), "query")
+	if err != nil {
+		t.Fatalf("Wait(query) error = %v", err)
+	}
+
+	// The service limit is reached, the endpoint slot is given back
+	releaseAcquire()
+	releaseQuery2, err := g.Wait(context.Background(), "query")
+	if err != nil {
+		t.Fatalf("Wait(query) error = %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = g.Wait(ctx, "acquire"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait(acquire) error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	stats := g.Stats()
+	if got := stats.Endpoints["acquire"].InFlight; got != 0 {
+		t.Errorf("acquire InFlight = %d, want 0", got)
+	}
+	if got := len(g.endpoints["acquire"].slots); got != 0 {
+		t.Errorf("acquire slots in use = %d, want 0", got)
+	}
+
+	releaseQuery()
+	releaseQuery2()
+	if got := g.Stats().Service.InFlight; got != 0 {
+		t.Errorf("service InFlight = %d, want 0", got)
+	}
+	if len(waits) != 0 {
+		t.Errorf("OnWait called for %v, want no call for requests admitted at once", waits)
+	}
+}
+
+func TestGroupOnWait(t *testing.T) {
+	var endpoints []string
+	var waits []time.Duration
+	g := NewGroup(&Policy{
+		Endpoints: map[string]Limit{
+			"query": {QPS: 20, Burst: 1},
+		},
+		OnWait: func(endpoint string, wait time.Duration) {
+			endpoints = append(endpoints, endpoint)
+			waits = append(waits, wait)
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		release, err := g.Wait(context.Background(), "query")
+		if err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		release()
+	}
+	if len(endpoints) != 1 || endpoints[0] != "query" {
+		t.Fatalf("OnWait called for %v, want a single call for query", endpoints)
+	}
+	if waits[0] < 40*time.Millisecond {
+		t.Errorf("OnWait wait = %v, want about 50ms", waits[0])
+	}
+}
+
+func TestGroupNilPolicy(t *testing.T) {
+	g := NewGroup(nil)
+	for i := 0; i < 100; i++ {
+		release, err := g.Wait(context.Background(), "query")
+		if err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		release()
+	}
+	if got := g.Stats().Service.Admitted; got != 100 {
+		t.Errorf("Admitted = %d, want 100", got)
+	}
+}
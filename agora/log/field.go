@@ -0,0 +1,79 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// @brief Key/value pair attached to a structured log message
+//
+// @since v0.13.0
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// @brief Creates a field with the given key and value
+//
+// @since v0.13.0
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// @brief Creates a field with a string value
+//
+// @since v0.13.0
+func String(key string, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// @brief Creates a field with an int value
+//
+// @since v0.13.0
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// @brief Creates an "error" field
+//
+// @since v0.13.0
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+type fieldsKey struct{}
+
+// @brief Returns a copy of ctx carrying the fields, they are added to every message logged with the context
+//
+// @note Use it to correlate the logs of the REST Client with your request, e.g. with a trace ID.
+//
+// @since v0.13.0
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	existing := FieldsFromContext(ctx)
+	merged := make([]Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// @brief Returns the fields carried by ctx, see WithFields
+//
+// @since v0.13.0
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]Field)
+	return fields
+}
+
+// formatFields formats fields as " key=value key=value".
+func formatFields(fields []Field) string {
+	var b strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&b, " %s=%v", field.Key, field.Value)
+	}
+	return b.String()
+}
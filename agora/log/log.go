@@ -23,6 +23,22 @@ const (
 	ErrLevel
 )
 
+// String returns the name of the level, e.g. "DEBUG".
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "DEBUG"
+	case InfoLevel:
+		return "INFO"
+	case WarningLevel:
+		return "WARN"
+	case ErrLevel:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
 // @brief Logger interface,implement this interface to output logs to your logging component
 //
 // @since v0.7.0
@@ -63,6 +79,9 @@ func (d *discardLogger) Warn(ctx context.Context, module string, v ...interface{
 
 func (d *discardLogger) Warnf(ctx context.Context, module string, format string, v ...interface{}) {}
 
+func (d *discardLogger) Log(ctx context.Context, level Level, module string, msg string, fields ...Field) {
+}
+
 func (d *discardLogger) Level() Level {
 	return DebugLevel
 }
@@ -101,51 +120,88 @@ func NewDefaultLogger(level Level) *SampleLogger {
 	}
 }
 
+var _ StructuredLogger = (*SampleLogger)(nil)
+
+// output writes a message prefixed by the module and followed by the fields carried by ctx.
+//
+// calldepth is passed to log.Logger.Output, it is 3 when the method logging the message is called by the caller to report.
+func (d *SampleLogger) output(ctx context.Context, calldepth int, logger *log.Logger, module string, msg string, fields ...Field) {
+	if contextFields := FieldsFromContext(ctx); len(contextFields) > 0 {
+		fields = append(contextFields[:len(contextFields):len(contextFields)], fields...)
+	}
+	_ = logger.Output(calldepth, fmt.Sprintf("[%s] %s%s", module, msg, formatFields(fields)))
+}
+
+// @brief Outputs a message with fields, see StructuredLogger
+//
+// @since v0.13.0
+func (d *SampleLogger) Log(ctx context.Context, level Level, module string, msg string, fields ...Field) {
+	d.log(ctx, level, module, msg, fields...)
+}
+
+// log outputs a message with fields, it is called by Log and by the package level Log function,
+// so that both report their caller.
+func (d *SampleLogger) log(ctx context.Context, level Level, module string, msg string, fields ...Field) {
+	if d.level > level {
+		return
+	}
+	switch level {
+	case DebugLevel:
+		d.output(ctx, 4, d.DEBUG, module, msg, fields...)
+	case InfoLevel:
+		d.output(ctx, 4, d.INFO, module, msg, fields...)
+	case WarningLevel:
+		d.output(ctx, 4, d.WARN, module, msg, fields...)
+	default:
+		d.output(ctx, 4, d.ERROR, module, msg, fields...)
+	}
+}
+
 func (d *SampleLogger) Debug(ctx context.Context, module string, v ...interface{}) {
 	if d.level <= DebugLevel {
-		_ = d.DEBUG.Output(2, fmt.Sprintln(v...))
+		d.output(ctx, 3, d.DEBUG, module, sprint(v...))
 	}
 }
 
 func (d *SampleLogger) Debugf(ctx context.Context, module string, format string, v ...interface{}) {
 	if d.level <= DebugLevel {
-		_ = d.DEBUG.Output(2, fmt.Sprintf(format, v...))
+		d.output(ctx, 3, d.DEBUG, module, fmt.Sprintf(format, v...))
 	}
 }
 
 func (d *SampleLogger) Error(ctx context.Context, module string, v ...interface{}) {
 	if d.level <= ErrLevel {
-		_ = d.ERROR.Output(2, fmt.Sprintln(v...))
+		d.output(ctx, 3, d.ERROR, module, sprint(v...))
 	}
 }
 
 func (d *SampleLogger) Errorf(ctx context.Context, module string, format string, v ...interface{}) {
 	if d.level <= ErrLevel {
-		_ = d.ERROR.Output(2, fmt.Sprintf(format, v...))
+		d.output(ctx, 3, d.ERROR, module, fmt.Sprintf(format, v...))
 	}
 }
 
 func (d *SampleLogger) Info(ctx context.Context, module string, v ...interface{}) {
-	if d.level >= InfoLevel {
-		_ = d.INFO.Output(2, fmt.Sprintln(v...))
+	if d.level <= InfoLevel {
+		d.output(ctx, 3, d.INFO, module, sprint(v...))
 	}
 }
 
 func (d *SampleLogger) Infof(ctx context.Context, module string, format string, v ...interface{}) {
-	if d.level >= InfoLevel {
-		_ = d.WARN.Output(2, fmt.Sprintln(v...))
+	if d.level <= InfoLevel {
+		d.output(ctx, 3, d.INFO, module, fmt.Sprintf(format, v...))
 	}
 }
 
 func (d *SampleLogger) Warn(ctx context.Context, module string, v ...interface{}) {
 	if d.level <= WarningLevel {
-		_ = d.WARN.Output(2, fmt.Sprintln(v...))
+		d.output(ctx, 3, d.WARN, module, sprint(v...))
 	}
 }
 
 func (d *SampleLogger) Warnf(ctx context.Context, module string, format string, v ...interface{}) {
 	if d.level <= WarningLevel {
-		_ = d.WARN.Output(2, fmt.Sprintf(format, v...))
+		d.output(ctx, 3, d.WARN, module, fmt.Sprintf(format, v...))
 	}
 }
 
@@ -0,0 +1,166 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+// newTestSampleLogger returns a SampleLogger writing each level to its own buffer, with the file and line of the caller.
+func newTestSampleLogger(level Level) (*SampleLogger, map[Level]*bytes.Buffer) {
+	buffers := map[Level]*bytes.Buffer{
+		DebugLevel:   {},
+		InfoLevel:    {},
+		WarningLevel: {},
+		ErrLevel:     {},
+	}
+	return &SampleLogger{
+		DEBUG: log.New(buffers[DebugLevel], "DEBUG ", log.Lshortfile),
+		INFO:  log.New(buffers[InfoLevel], "INFO ", log.Lshortfile),
+		WARN:  log.New(buffers[WarningLevel], "WARN ", log.Lshortfile),
+		ERROR: log.New(buffers[ErrLevel], "ERROR ", log.Lshortfile),
+		level: level,
+	}, buffers
+}
+
+func TestSampleLoggerLevels(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		log  func(logger *SampleLogger)
+		want Level
+	}{
+		{name: "Infof", log: func(l *SampleLogger) { l.Infof(ctx, "test", "message %d", 1) }, want: InfoLevel},
+		{name: "Warn", log: func(l *SampleLogger) { l.Warn(ctx, "test", "message", 1) }, want: WarningLevel},
+		{name: "Errorf", log: func(l *SampleLogger) { l.Errorf(ctx, "test", "message %d", 1) }, want: ErrLevel},
+		{name: "Log info", log: func(l *SampleLogger) { l.Log(ctx, InfoLevel, "test", "message 1") }, want: InfoLevel},
+		{name: "Log warning", log: func(l *SampleLogger) { l.Log(ctx, WarningLevel, "test", "message 1") }, want: WarningLevel},
+		{name: "Log error", log: func(l *SampleLogger) { l.Log(ctx, ErrLevel, "test", "message 1") }, want: ErrLevel},
+		{name: "Log unknown level as error", log: func(l *SampleLogger) { l.Log(ctx, Level(9), "test", "message 1") }, want: ErrLevel},
+		{name: "package Log", log: func(l *SampleLogger) { Log(ctx, l, WarningLevel, "test", "message 1") }, want: WarningLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buffers := newTestSampleLogger(InfoLevel)
+			tt.log(logger)
+
+			for level, buffer := range buffers {
+				got := buffer.String()
+				if level != tt.want {
+					if got != "" {
+						t.Errorf("%s output = %q, want nothing", level, got)
+					}
+					continue
+				}
+				if !strings.Contains(got, "[test] message 1") {
+					t.Errorf("%s output = %q, want [test] message 1", level, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSampleLoggerBelowLevel(t *testing.T) {
+	ctx := context.Background()
+	logger, buffers := newTestSampleLogger(WarningLevel)
+
+	logger.Debugf(ctx, "test", "debug")
+	logger.Info(ctx, "test", "info")
+	logger.Log(ctx, InfoLevel, "test", "info")
+	Log(ctx, logger, DebugLevel, "test", "debug")
+
+	for level, buffer := range buffers {
+		if buffer.Len() != 0 {
+			t.Errorf("%s output = %q, want nothing below the warning level", level, buffer.String())
+		}
+	}
+}
+
+func TestSampleLoggerCaller(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		log  func(logger *SampleLogger)
+	}{
+		{name: "Debugf", log: func(l *SampleLogger) { l.Debugf(ctx, "test", "message") }},
+		{name: "Log", log: func(l *SampleLogger) { l.Log(ctx, DebugLevel, "test", "message") }},
+		{name: "package Log", log: func(l *SampleLogger) { Log(ctx, l, DebugLevel, "test", "message") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buffers := newTestSampleLogger(DebugLevel)
+			tt.log(logger)
+
+			if got := buffers[DebugLevel].String(); !strings.HasPrefix(got, "DEBUG log_test.go:") {
+				t.Errorf("output = %q, want the caller in log_test.go", got)
+			}
+		})
+	}
+}
+
+func TestSampleLoggerContextFields(t *testing.T) {
+	ctx := WithFields(context.Background(), String("trace_id", "trace"))
+	ctx = WithFields(ctx, Int("attempt", 1))
+	logger, buffers := newTestSampleLogger(DebugLevel)
+
+	logger.Infof(ctx, "test", "message")
+	logger.Log(ctx, WarningLevel, "test", "message", String("request_id", "request"))
+
+	if got := buffers[InfoLevel].String(); !strings.Contains(got, "[test] message trace_id=trace attempt=1\n") {
+		t.Errorf("info output = %q, want the context fields", got)
+	}
+	if got := buffers[WarningLevel].String(); !strings.Contains(got, "[test] message trace_id=trace attempt=1 request_id=request\n") {
+		t.Errorf("warning output = %q, want the context fields followed by the message fields", got)
+	}
+}
+
+// recordingLogger records the messages of a Logger that is not a StructuredLogger.
+type recordingLogger struct {
+	// Only the methods of Logger are promoted
+	Logger
+	messages []string
+}
+
+func (r *recordingLogger) Warn(ctx context.Context, module string, v ...interface{}) {
+	r.messages = append(r.messages, "WARN ["+module+"] "+sprint(v...))
+}
+
+// recordingStructuredLogger records the messages of a StructuredLogger.
+type recordingStructuredLogger struct {
+	levels []Level
+	fields [][]Field
+}
+
+func (r *recordingStructuredLogger) Log(ctx context.Context, level Level, module string, msg string, fields ...Field) {
+	r.levels = append(r.levels, level)
+	r.fields = append(r.fields, fields)
+}
+
+func TestLogFormatsFields(t *testing.T) {
+	logger := &recordingLogger{Logger: DiscardLogger}
+	Log(context.Background(), logger, WarningLevel, "test", "message", String("key", "value"), Int("n", 1))
+
+	want := []string{"WARN [test] message key=value n=1"}
+	if len(logger.messages) != 1 || logger.messages[0] != want[0] {
+		t.Errorf("messages = %q, want %q", logger.messages, want)
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	structured := &recordingStructuredLogger{}
+	logger := NewLogger(structured, InfoLevel)
+	ctx := WithFields(context.Background(), String("trace_id", "trace"))
+
+	logger.Debugf(ctx, "test", "filtered")
+	logger.Warnf(ctx, "test", "message %d", 1)
+	Log(ctx, logger, ErrLevel, "test", "message", Int("n", 1))
+
+	if len(structured.levels) != 2 || structured.levels[0] != WarningLevel || structured.levels[1] != ErrLevel {
+		t.Fatalf("levels = %v, want [WARN ERROR]", structured.levels)
+	}
+	if got := structured.fields[1]; len(got) != 2 || got[0] != String("trace_id", "trace") || got[1] != Int("n", 1) {
+		t.Errorf("fields = %v, want the context fields followed by the message fields", got)
+	}
+}
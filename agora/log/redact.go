@@ -0,0 +1,91 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// RedactedValue replaces the values of sensitive fields.
+const RedactedValue = "******"
+
+// @brief Names of the JSON fields whose values are masked by RedactJSON, compared case-insensitively
+//
+// @note It includes storage keys, TTS and LLM vendor keys and tokens. Append to it to mask more fields.
+//
+// @since v0.13.0
+var SensitiveFields = []string{
+	"secretKey",
+	"secret_key",
+	"accessKey",
+	"access_key",
+	"token",
+	"key",
+	"api_key",
+	"apiKey",
+	"secret",
+	"password",
+	"app_certificate",
+	"appCertificate",
+	"authorization",
+}
+
+func isSensitiveField(name string) bool {
+	for _, field := range SensitiveFields {
+		if strings.EqualFold(field, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// @brief Masks the values of the sensitive fields in a JSON document, see SensitiveFields
+//
+// @return Returns the redacted JSON document. The document is returned as it is if it is not valid JSON.
+//
+// @since v0.13.0
+func RedactJSON(body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return body
+	}
+	if !redact(v) {
+		return body
+	}
+
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redact masks the sensitive fields of v in place, it reports whether any field is masked.
+func redact(v interface{}) bool {
+	redacted := false
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, child := range value {
+			if child != nil && isSensitiveField(k) {
+				value[k] = RedactedValue
+				redacted = true
+				continue
+			}
+			if redact(child) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			if redact(child) {
+				redacted = true
+			}
+		}
+	}
+
+	return redacted
+}
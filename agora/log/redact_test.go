@@ -0,0 +1,71 @@
+package log
+
+import "testing"
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "storage keys",
+			body: `{"storageConfig":{"accessKey":"ak","secretKey":"sk","bucket":"bucket","vendor":2}}`,
+			want: `{"storageConfig":{"accessKey":"******","bucket":"bucket","secretKey":"******","vendor":2}}`,
+		},
+		{
+			name: "vendor keys and tokens in arrays",
+			body: `{"properties":{"token":"007abc","llm":{"api_key":"llm-key"},"tts":{"params":{"key":"tts-key","region":"eastus"}}},"list":[{"password":"p"}]}`,
+			want: `{"list":[{"password":"******"}],"properties":{"llm":{"api_key":"******"},"token":"******","tts":{"params":{"key":"******","region":"eastus"}}}}`,
+		},
+		{
+			name: "case-insensitive names",
+			body: `{"SecretKey":"sk","Authorization":"Basic abc"}`,
+			want: `{"Authorization":"******","SecretKey":"******"}`,
+		},
+		{
+			name: "key only matches the whole name",
+			body: `{"keyword":"k","monkey":"m","keys":["a"],"key_frame":1,"sid":"sid","cname":"channel","uid":"1"}`,
+			want: `{"keyword":"k","monkey":"m","keys":["a"],"key_frame":1,"sid":"sid","cname":"channel","uid":"1"}`,
+		},
+		{
+			name: "null values kept",
+			body: `{"token":null,"cname":"channel"}`,
+			want: `{"token":null,"cname":"channel"}`,
+		},
+		{
+			name: "large numbers kept",
+			body: `{"key":"k","uid":12345678901234567890}`,
+			want: `{"key":"******","uid":12345678901234567890}`,
+		},
+		{
+			name: "not JSON",
+			body: `token=abc`,
+			want: `token=abc`,
+		},
+		{
+			name: "empty",
+			body: ``,
+			want: ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(RedactJSON([]byte(tt.body))); got != tt.want {
+				t.Errorf("RedactJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSensitiveFieldsAppended(t *testing.T) {
+	saved := SensitiveFields
+	t.Cleanup(func() {
+		SensitiveFields = saved
+	})
+	SensitiveFields = append(SensitiveFields[:len(SensitiveFields):len(SensitiveFields)], "customerSecret")
+
+	if got := string(RedactJSON([]byte(`{"customerSecret":"s"}`))); got != `{"customerSecret":"******"}` {
+		t.Errorf("RedactJSON() = %s, want the appended field masked", got)
+	}
+}
@@ -0,0 +1,53 @@
+//go:build go1.21
+
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// @brief Creates a StructuredLogger that outputs to a log/slog logger
+//
+// @note The module is added as the "module" attribute. Use NewLogger to use it as a Logger:
+//
+//	logger := log.NewLogger(log.NewSlogLogger(slog.Default()), log.InfoLevel)
+//
+// @since v0.13.0
+func NewSlogLogger(logger *slog.Logger) StructuredLogger {
+	return &slogLogger{logger: logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (s *slogLogger) Log(ctx context.Context, level Level, module string, msg string, fields ...Field) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	slogLevel := slogLevel(level)
+	if !s.logger.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields)+1)
+	attrs = append(attrs, slog.String("module", module))
+	for _, field := range fields {
+		attrs = append(attrs, slog.Any(field.Key, field.Value))
+	}
+	s.logger.LogAttrs(ctx, slogLevel, msg, attrs...)
+}
+
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarningLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
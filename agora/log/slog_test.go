@@ -0,0 +1,57 @@
+//go:build go1.21
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	handler := slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := NewLogger(NewSlogLogger(slog.New(handler)), DebugLevel)
+	ctx := WithFields(context.Background(), String("trace_id", "trace"))
+
+	// Filtered by the slog handler
+	logger.Debugf(ctx, "test", "debug")
+	Log(ctx, logger, WarningLevel, "test", "message", Int("attempt", 2))
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("output = %q, want one JSON record: %v", buffer.String(), err)
+	}
+	want := map[string]interface{}{
+		"level":    "WARN",
+		"msg":      "message",
+		"module":   "test",
+		"trace_id": "trace",
+		"attempt":  float64(2),
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  slog.Level
+	}{
+		{level: DebugLevel, want: slog.LevelDebug},
+		{level: InfoLevel, want: slog.LevelInfo},
+		{level: WarningLevel, want: slog.LevelWarn},
+		{level: ErrLevel, want: slog.LevelError},
+		{level: Level(9), want: slog.LevelError},
+	}
+	for _, tt := range tests {
+		if got := slogLevel(tt.level); got != tt.want {
+			t.Errorf("slogLevel(%s) = %s, want %s", tt.level, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// @brief Structured logger interface, implement this interface to output logs with key/value fields to your logging component
+//
+// @note Use NewLogger to use it as a Logger. See NewSlogLogger for the log/slog adapter.
+//
+// @since v0.13.0
+type StructuredLogger interface {
+	// Log outputs a message with fields. module is the component of the REST Client that logs the message.
+	Log(ctx context.Context, level Level, module string, msg string, fields ...Field)
+}
+
+// @brief Creates a Logger that outputs to a StructuredLogger
+//
+// @note The fields carried by ctx, see WithFields, are added to every message.
+//
+// @param structured Structured logger. See StructuredLogger for details.
+//
+// @param level Log level. See Level for details.
+//
+// @since v0.13.0
+func NewLogger(structured StructuredLogger, level Level) Logger {
+	return &structuredLogger{
+		structured: structured,
+		level:      level,
+	}
+}
+
+type structuredLogger struct {
+	structured StructuredLogger
+	level      Level
+}
+
+var (
+	_ Logger           = (*structuredLogger)(nil)
+	_ StructuredLogger = (*structuredLogger)(nil)
+)
+
+func (s *structuredLogger) Log(ctx context.Context, level Level, module string, msg string, fields ...Field) {
+	if s.level > level {
+		return
+	}
+	contextFields := FieldsFromContext(ctx)
+	if len(contextFields) > 0 {
+		fields = append(contextFields[:len(contextFields):len(contextFields)], fields...)
+	}
+	s.structured.Log(ctx, level, module, msg, fields...)
+}
+
+func (s *structuredLogger) Debug(ctx context.Context, module string, v ...interface{}) {
+	s.Log(ctx, DebugLevel, module, sprint(v...))
+}
+
+func (s *structuredLogger) Debugf(ctx context.Context, module string, format string, v ...interface{}) {
+	s.Log(ctx, DebugLevel, module, fmt.Sprintf(format, v...))
+}
+
+func (s *structuredLogger) Error(ctx context.Context, module string, v ...interface{}) {
+	s.Log(ctx, ErrLevel, module, sprint(v...))
+}
+
+func (s *structuredLogger) Errorf(ctx context.Context, module string, format string, v ...interface{}) {
+	s.Log(ctx, ErrLevel, module, fmt.Sprintf(format, v...))
+}
+
+func (s *structuredLogger) Info(ctx context.Context, module string, v ...interface{}) {
+	s.Log(ctx, InfoLevel, module, sprint(v...))
+}
+
+func (s *structuredLogger) Infof(ctx context.Context, module string, format string, v ...interface{}) {
+	s.Log(ctx, InfoLevel, module, fmt.Sprintf(format, v...))
+}
+
+func (s *structuredLogger) Warn(ctx context.Context, module string, v ...interface{}) {
+	s.Log(ctx, WarningLevel, module, sprint(v...))
+}
+
+func (s *structuredLogger) Warnf(ctx context.Context, module string, format string, v ...interface{}) {
+	s.Log(ctx, WarningLevel, module, fmt.Sprintf(format, v...))
+}
+
+func (s *structuredLogger) Level() Level {
+	return s.level
+}
+
+func (s *structuredLogger) SetLevel(level Level) {
+	s.level = level
+}
+
+// @brief Outputs a message with fields to logger
+//
+// @note The fields are passed as they are if logger implements StructuredLogger, otherwise they are formatted into the message.
+//
+// @since v0.13.0
+func Log(ctx context.Context, logger Logger, level Level, module string, msg string, fields ...Field) {
+	if sample, ok := logger.(*SampleLogger); ok {
+		// Reports the caller of Log rather than Log itself
+		sample.log(ctx, level, module, msg, fields...)
+		return
+	}
+	if structured, ok := logger.(StructuredLogger); ok {
+		structured.Log(ctx, level, module, msg, fields...)
+		return
+	}
+
+	msg += formatFields(fields)
+	switch level {
+	case DebugLevel:
+		logger.Debug(ctx, module, msg)
+	case InfoLevel:
+		logger.Info(ctx, module, msg)
+	case WarningLevel:
+		logger.Warn(ctx, module, msg)
+	default:
+		logger.Error(ctx, module, msg)
+	}
+}
+
+// sprint formats v like fmt.Sprintln without the trailing newline.
+func sprint(v ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
@@ -0,0 +1,12 @@
+module github.com/AgoraIO-Community/agora-rest-client-go/agora/log/zaplog
+
+go 1.19
+
+require (
+	github.com/AgoraIO-Community/agora-rest-client-go v0.13.0
+	go.uber.org/zap v1.28.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
+
+replace github.com/AgoraIO-Community/agora-rest-client-go => ../../..
@@ -0,0 +1,53 @@
+// Package zaplog adapts a zap logger to the structured logger of the REST Client.
+//
+// It is a separate module so that the REST Client does not depend on zap.
+package zaplog
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+)
+
+// New creates a log.StructuredLogger that outputs to a zap logger, the module is added as the "module" field.
+//
+// Use log.NewLogger to use it as a log.Logger:
+//
+//	logger := log.NewLogger(zaplog.New(zapLogger), log.InfoLevel)
+func New(logger *zap.Logger) log.StructuredLogger {
+	return &zapLogger{logger: logger.WithOptions(zap.AddCallerSkip(3))}
+}
+
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+func (z *zapLogger) Log(_ context.Context, level log.Level, module string, msg string, fields ...log.Field) {
+	entry := z.logger.Check(zapLevel(level), msg)
+	if entry == nil {
+		return
+	}
+
+	zapFields := make([]zap.Field, 0, len(fields)+1)
+	zapFields = append(zapFields, zap.String("module", module))
+	for _, field := range fields {
+		zapFields = append(zapFields, zap.Any(field.Key, field.Value))
+	}
+	entry.Write(zapFields...)
+}
+
+func zapLevel(level log.Level) zapcore.Level {
+	switch level {
+	case log.DebugLevel:
+		return zapcore.DebugLevel
+	case log.InfoLevel:
+		return zapcore.InfoLevel
+	case log.WarningLevel:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
@@ -0,0 +1,74 @@
+package zaplog
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+)
+
+func TestNew(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := log.NewLogger(New(zap.New(core, zap.AddCaller())), log.DebugLevel)
+	ctx := log.WithFields(context.Background(), log.String("trace_id", "trace"))
+
+	// Filtered by the zap core
+	logger.Debugf(ctx, "test", "debug")
+	logger.Warnf(ctx, "test", "message %d", 1)
+	log.Log(ctx, logger, log.ErrLevel, "test", "message", log.Int("attempt", 2))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("entries = %v, want 2", entries)
+	}
+
+	want := []struct {
+		level  zapcore.Level
+		msg    string
+		fields map[string]interface{}
+	}{
+		{level: zapcore.WarnLevel, msg: "message 1", fields: map[string]interface{}{"module": "test", "trace_id": "trace"}},
+		{level: zapcore.ErrorLevel, msg: "message", fields: map[string]interface{}{"module": "test", "trace_id": "trace", "attempt": int64(2)}},
+	}
+	for i, entry := range entries {
+		if entry.Level != want[i].level || entry.Message != want[i].msg {
+			t.Errorf("entry %d = %s %q, want %s %q", i, entry.Level, entry.Message, want[i].level, want[i].msg)
+		}
+		fields := entry.ContextMap()
+		if len(fields) != len(want[i].fields) {
+			t.Errorf("entry %d fields = %v, want %v", i, fields, want[i].fields)
+		}
+		for key, value := range want[i].fields {
+			if fields[key] != value {
+				t.Errorf("entry %d field %s = %v, want %v", i, key, fields[key], value)
+			}
+		}
+		// The caller of the Logger, not the adapter
+		if file := filepath.Base(entry.Caller.File); file != "zaplog_test.go" {
+			t.Errorf("entry %d caller = %s, want zaplog_test.go", i, entry.Caller)
+		}
+	}
+}
+
+func TestZapLevel(t *testing.T) {
+	tests := []struct {
+		level log.Level
+		want  zapcore.Level
+	}{
+		{level: log.DebugLevel, want: zapcore.DebugLevel},
+		{level: log.InfoLevel, want: zapcore.InfoLevel},
+		{level: log.WarningLevel, want: zapcore.WarnLevel},
+		{level: log.ErrLevel, want: zapcore.ErrorLevel},
+		{level: log.Level(9), want: zapcore.ErrorLevel},
+	}
+	for _, tt := range tests {
+		if got := zapLevel(tt.level); got != tt.want {
+			t.Errorf("zapLevel(%s) = %s, want %s", tt.level, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package agora
+
+import (
+	"net/http"
+)
+
+// @brief Middleware wraps the http.RoundTripper used by the REST Client
+//
+// @note Every HTTP request sent by the REST Client, including retries, passes through the middleware chain.
+// Use it to add headers, record metrics or inspect requests and responses.
+//
+// @since v0.13.0
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// @brief RoundTripperFunc is an adapter to allow the use of ordinary functions as http.RoundTripper
+//
+// @since v0.13.0
+type RoundTripperFunc func(r *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(r)
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// @brief Chains the middlewares around the transport
+//
+// @note The first middleware is the outermost one, i.e. it sees the request first and the response last.
+//
+// @param transport The transport to wrap. http.DefaultTransport is used when it is nil.
+//
+// @param middlewares The middlewares to apply.
+//
+// @return Returns the wrapped transport.
+//
+// @since v0.13.0
+func ChainMiddlewares(transport http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] != nil {
+			transport = middlewares[i](transport)
+		}
+	}
+	return transport
+}
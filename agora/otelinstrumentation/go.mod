@@ -0,0 +1,23 @@
+module github.com/AgoraIO-Community/agora-rest-client-go/agora/otelinstrumentation
+
+go 1.19
+
+require (
+	github.com/AgoraIO-Community/agora-rest-client-go v0.13.0
+	go.opentelemetry.io/otel v1.16.0
+	go.opentelemetry.io/otel/metric v1.16.0
+	go.opentelemetry.io/otel/sdk v1.16.0
+	go.opentelemetry.io/otel/sdk/metric v0.39.0
+	go.opentelemetry.io/otel/trace v1.16.0
+)
+
+require (
+	github.com/go-logr/logr v1.2.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/tidwall/gjson v1.18.0 // indirect
+	github.com/tidwall/match v1.1.1 // indirect
+	github.com/tidwall/pretty v1.2.1 // indirect
+	golang.org/x/sys v0.8.0 // indirect
+)
+
+replace github.com/AgoraIO-Community/agora-rest-client-go => ../..
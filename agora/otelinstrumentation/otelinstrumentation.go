@@ -0,0 +1,218 @@
+// Package otelinstrumentation implements agora.Instrumentation with OpenTelemetry.
+//
+// It emits a span per API call with a child span per HTTP attempt, and records call and attempt counters and latency histograms.
+//
+// It is a separate module so that the REST Client does not depend on OpenTelemetry.
+package otelinstrumentation
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/version"
+)
+
+const instrumentationName = "github.com/AgoraIO-Community/agora-rest-client-go"
+
+// Attribute keys set on spans and metrics.
+const (
+	ModuleKey         = attribute.Key("agora.module")
+	AttemptKey        = attribute.Key("agora.attempt")
+	AttemptsKey       = attribute.Key("agora.attempts")
+	RegionPrefixKey   = attribute.Key("agora.region_prefix")
+	DomainSuffixKey   = attribute.Key("agora.domain_suffix")
+	RequestIDKey      = attribute.Key("agora.request_id")
+	HttpMethodKey     = attribute.Key("http.request.method")
+	HttpStatusCodeKey = attribute.Key("http.response.status_code")
+	URLPathKey        = attribute.Key("url.path")
+	OutcomeKey        = attribute.Key("agora.outcome")
+)
+
+type options struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the Instrumentation.
+type Option func(*options)
+
+// WithTracerProvider sets the tracer provider. The default value is otel.GetTracerProvider().
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tracerProvider
+	}
+}
+
+// WithMeterProvider sets the meter provider. The default value is otel.GetMeterProvider().
+func WithMeterProvider(meterProvider metric.MeterProvider) Option {
+	return func(o *options) {
+		o.meterProvider = meterProvider
+	}
+}
+
+// Instrumentation emits OpenTelemetry traces and metrics for the API calls of the REST Client.
+type Instrumentation struct {
+	tracer trace.Tracer
+
+	calls           metric.Int64Counter
+	attempts        metric.Int64Counter
+	callDuration    metric.Float64Histogram
+	attemptDuration metric.Float64Histogram
+}
+
+var _ agora.Instrumentation = (*Instrumentation)(nil)
+
+// New creates an Instrumentation, set it in the Instrumentation field of the client config.
+func New(opts ...Option) (*Instrumentation, error) {
+	o := options{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	meter := o.meterProvider.Meter(instrumentationName, metric.WithInstrumentationVersion(version.GetSDKVersion()))
+	i := &Instrumentation{
+		tracer: o.tracerProvider.Tracer(instrumentationName, trace.WithInstrumentationVersion(version.GetSDKVersion())),
+	}
+
+	var err error
+	if i.calls, err = meter.Int64Counter("agora.rest.calls",
+		metric.WithDescription("Number of API calls"),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+	if i.attempts, err = meter.Int64Counter("agora.rest.attempts",
+		metric.WithDescription("Number of HTTP attempts of API calls"),
+		metric.WithUnit("{attempt}")); err != nil {
+		return nil, err
+	}
+	if i.callDuration, err = meter.Float64Histogram("agora.rest.call.duration",
+		metric.WithDescription("Duration of API calls, including all attempts and the delays between them"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if i.attemptDuration, err = meter.Float64Histogram("agora.rest.attempt.duration",
+		metric.WithDescription("Duration of HTTP attempts of API calls"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// StartCall starts the span of an API call.
+func (i *Instrumentation) StartCall(ctx context.Context, info agora.CallInfo) (context.Context, agora.CallObserver) {
+	ctx, span := i.tracer.Start(ctx, info.Module,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			ModuleKey.String(info.Module),
+			HttpMethodKey.String(info.Method),
+			URLPathKey.String(info.Path),
+		),
+	)
+
+	return ctx, &callObserver{
+		instrumentation: i,
+		ctx:             ctx,
+		span:            span,
+		info:            info,
+		start:           time.Now(),
+	}
+}
+
+type callObserver struct {
+	instrumentation *Instrumentation
+	ctx             context.Context
+	span            trace.Span
+	info            agora.CallInfo
+	start           time.Time
+	attempts        int
+}
+
+func (c *callObserver) StartAttempt(ctx context.Context, attempt int) (context.Context, agora.AttemptObserver) {
+	c.attempts = attempt
+	ctx, span := c.instrumentation.tracer.Start(ctx, c.info.Module+" attempt",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			ModuleKey.String(c.info.Module),
+			AttemptKey.Int(attempt),
+		),
+	)
+
+	return ctx, &attemptObserver{
+		call:  c,
+		ctx:   ctx,
+		span:  span,
+		start: time.Now(),
+	}
+}
+
+func (c *callObserver) End(response *agora.BaseResponse, err error) {
+	attrs := []attribute.KeyValue{
+		ModuleKey.String(c.info.Module),
+		OutcomeKey.String(outcome(err)),
+	}
+	if response != nil {
+		attrs = append(attrs, HttpStatusCodeKey.Int(response.HttpStatusCode))
+		c.span.SetAttributes(RequestIDKey.String(response.GetRequestID()))
+	}
+	c.span.SetAttributes(attrs...)
+	c.span.SetAttributes(AttemptsKey.Int(c.attempts))
+	endSpan(c.span, err)
+
+	set := metric.WithAttributes(attrs...)
+	c.instrumentation.calls.Add(c.ctx, 1, set)
+	c.instrumentation.callDuration.Record(c.ctx, time.Since(c.start).Seconds(), set)
+}
+
+type attemptObserver struct {
+	call  *callObserver
+	ctx   context.Context
+	span  trace.Span
+	start time.Time
+}
+
+func (a *attemptObserver) End(info agora.AttemptInfo, err error) {
+	attrs := []attribute.KeyValue{
+		ModuleKey.String(a.call.info.Module),
+		RegionPrefixKey.String(info.RegionPrefix),
+		DomainSuffixKey.String(info.DomainSuffix),
+		OutcomeKey.String(outcome(err)),
+	}
+	if info.HttpStatusCode != 0 {
+		attrs = append(attrs, HttpStatusCodeKey.Int(info.HttpStatusCode))
+	}
+	a.span.SetAttributes(attrs...)
+	if info.RequestID != "" {
+		a.span.SetAttributes(RequestIDKey.String(info.RequestID))
+	}
+	endSpan(a.span, err)
+
+	set := metric.WithAttributes(attrs...)
+	a.call.instrumentation.attempts.Add(a.ctx, 1, set)
+	a.call.instrumentation.attemptDuration.Record(a.ctx, time.Since(a.start).Seconds(), set)
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,298 @@
+package otelinstrumentation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+)
+
+func newTestInstrumentation(t *testing.T) (*Instrumentation, *tracetest.SpanRecorder, sdkmetric.Reader) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	instrumentation, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return instrumentation, recorder, reader
+}
+
+// runCall observes a call made of a failed attempt followed by a successful one.
+func runCall(instrumentation *Instrumentation) {
+	ctx, call := instrumentation.StartCall(context.Background(), agora.CallInfo{
+		Module: "cloudRecording:start",
+		Method: http.MethodPost,
+		Path:   "/v1/apps/appid/cloud_recording/start",
+	})
+
+	_, attempt := call.StartAttempt(ctx, 1)
+	attempt.End(agora.AttemptInfo{
+		Attempt:        1,
+		RegionPrefix:   "api-us-west-1",
+		DomainSuffix:   "agora.io",
+		HttpStatusCode: http.StatusServiceUnavailable,
+		RequestID:      "request-1",
+	}, errors.New("service unavailable"))
+
+	_, attempt = call.StartAttempt(ctx, 2)
+	attempt.End(agora.AttemptInfo{
+		Attempt:        2,
+		RegionPrefix:   "api-us-east-1",
+		DomainSuffix:   "sd-rtn.com",
+		HttpStatusCode: http.StatusOK,
+		RequestID:      "request-2",
+	}, nil)
+
+	header := http.Header{}
+	header.Set("X-Request-Id", "request-2")
+	call.End(&agora.BaseResponse{
+		RawResponse:    &http.Response{StatusCode: http.StatusOK, Header: header},
+		HttpStatusCode: http.StatusOK,
+	}, nil)
+}
+
+func attributeMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, attr := range attrs {
+		m[attr.Key] = attr.Value
+	}
+	return m
+}
+
+func TestSpans(t *testing.T) {
+	instrumentation, recorder, _ := newTestInstrumentation(t)
+	runCall(instrumentation)
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+
+	var callSpan sdktrace.ReadOnlySpan
+	var attemptSpans []sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.Name() == "cloudRecording:start" {
+			callSpan = span
+		} else {
+			attemptSpans = append(attemptSpans, span)
+		}
+	}
+	if callSpan == nil {
+		t.Fatal("no span for the call")
+	}
+	if len(attemptSpans) != 2 {
+		t.Fatalf("got %d attempt spans, want 2", len(attemptSpans))
+	}
+
+	callAttrs := attributeMap(callSpan.Attributes())
+	wantCallAttrs := map[attribute.Key]attribute.Value{
+		ModuleKey:         attribute.StringValue("cloudRecording:start"),
+		HttpMethodKey:     attribute.StringValue(http.MethodPost),
+		URLPathKey:        attribute.StringValue("/v1/apps/appid/cloud_recording/start"),
+		HttpStatusCodeKey: attribute.IntValue(http.StatusOK),
+		RequestIDKey:      attribute.StringValue("request-2"),
+		AttemptsKey:       attribute.IntValue(2),
+		OutcomeKey:        attribute.StringValue("ok"),
+	}
+	for key, want := range wantCallAttrs {
+		if got, ok := callAttrs[key]; !ok || got != want {
+			t.Errorf("call span attribute %s = %v, want %v", key, got.Emit(), want.Emit())
+		}
+	}
+	if callSpan.Status().Code == codes.Error {
+		t.Errorf("call span status = %v, want not error", callSpan.Status().Code)
+	}
+
+	wantAttempts := map[int64]struct {
+		regionPrefix   string
+		domainSuffix   string
+		httpStatusCode int
+		requestID      string
+		outcome        string
+		status         codes.Code
+	}{
+		1: {"api-us-west-1", "agora.io", http.StatusServiceUnavailable, "request-1", "error", codes.Error},
+		2: {"api-us-east-1", "sd-rtn.com", http.StatusOK, "request-2", "ok", codes.Unset},
+	}
+	for _, span := range attemptSpans {
+		if span.Parent().SpanID() != callSpan.SpanContext().SpanID() {
+			t.Errorf("attempt span %s is not a child of the call span", span.Name())
+		}
+		if span.SpanContext().TraceID() != callSpan.SpanContext().TraceID() {
+			t.Errorf("attempt span %s is not in the trace of the call span", span.Name())
+		}
+
+		attrs := attributeMap(span.Attributes())
+		attempt := attrs[AttemptKey].AsInt64()
+		want, ok := wantAttempts[attempt]
+		if !ok {
+			t.Errorf("unexpected attempt %d", attempt)
+			continue
+		}
+		delete(wantAttempts, attempt)
+
+		wantAttrs := map[attribute.Key]attribute.Value{
+			ModuleKey:         attribute.StringValue("cloudRecording:start"),
+			RegionPrefixKey:   attribute.StringValue(want.regionPrefix),
+			DomainSuffixKey:   attribute.StringValue(want.domainSuffix),
+			HttpStatusCodeKey: attribute.IntValue(want.httpStatusCode),
+			RequestIDKey:      attribute.StringValue(want.requestID),
+			OutcomeKey:        attribute.StringValue(want.outcome),
+		}
+		for key, wantValue := range wantAttrs {
+			if got, ok := attrs[key]; !ok || got != wantValue {
+				t.Errorf("attempt %d span attribute %s = %v, want %v", attempt, key, got.Emit(), wantValue.Emit())
+			}
+		}
+		if span.Status().Code != want.status {
+			t.Errorf("attempt %d span status = %v, want %v", attempt, span.Status().Code, want.status)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	instrumentation, _, reader := newTestInstrumentation(t)
+	runCall(instrumentation)
+	runCall(instrumentation)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	metrics := make(map[string]metricdata.Metrics)
+	for _, scopeMetrics := range rm.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+
+	tests := []struct {
+		name  string
+		attrs []attribute.KeyValue
+		want  int64
+	}{
+		{
+			name: "agora.rest.calls",
+			attrs: []attribute.KeyValue{
+				ModuleKey.String("cloudRecording:start"),
+				OutcomeKey.String("ok"),
+				HttpStatusCodeKey.Int(http.StatusOK),
+			},
+			want: 2,
+		},
+		{
+			name: "agora.rest.attempts",
+			attrs: []attribute.KeyValue{
+				ModuleKey.String("cloudRecording:start"),
+				RegionPrefixKey.String("api-us-west-1"),
+				DomainSuffixKey.String("agora.io"),
+				OutcomeKey.String("error"),
+				HttpStatusCodeKey.Int(http.StatusServiceUnavailable),
+			},
+			want: 2,
+		},
+		{
+			name: "agora.rest.attempts",
+			attrs: []attribute.KeyValue{
+				ModuleKey.String("cloudRecording:start"),
+				RegionPrefixKey.String("api-us-east-1"),
+				DomainSuffixKey.String("sd-rtn.com"),
+				OutcomeKey.String("ok"),
+				HttpStatusCodeKey.Int(http.StatusOK),
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		m, ok := metrics[tt.name]
+		if !ok {
+			t.Errorf("metric %s not recorded", tt.name)
+			continue
+		}
+		sum, ok := m.Data.(metricdata.Sum[int64])
+		if !ok {
+			t.Errorf("metric %s is a %T, want a metricdata.Sum[int64]", tt.name, m.Data)
+			continue
+		}
+		want := attribute.NewSet(tt.attrs...)
+		var found bool
+		for _, point := range sum.DataPoints {
+			if point.Attributes.Equals(&want) {
+				found = true
+				if point.Value != tt.want {
+					t.Errorf("metric %s%v = %d, want %d", tt.name, tt.attrs, point.Value, tt.want)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("metric %s has no data point with attributes %v", tt.name, tt.attrs)
+		}
+	}
+
+	histograms := []struct {
+		name      string
+		wantCount uint64
+	}{
+		{name: "agora.rest.call.duration", wantCount: 2},
+		{name: "agora.rest.attempt.duration", wantCount: 4},
+	}
+	for _, tt := range histograms {
+		m, ok := metrics[tt.name]
+		if !ok {
+			t.Errorf("metric %s not recorded", tt.name)
+			continue
+		}
+		if m.Unit != "s" {
+			t.Errorf("metric %s unit = %q, want %q", tt.name, m.Unit, "s")
+		}
+		histogram, ok := m.Data.(metricdata.Histogram[float64])
+		if !ok {
+			t.Errorf("metric %s is a %T, want a metricdata.Histogram[float64]", tt.name, m.Data)
+			continue
+		}
+		var count uint64
+		for _, point := range histogram.DataPoints {
+			count += point.Count
+		}
+		if count != tt.wantCount {
+			t.Errorf("metric %s count = %d, want %d", tt.name, count, tt.wantCount)
+		}
+	}
+}
+
+func TestCallError(t *testing.T) {
+	instrumentation, recorder, _ := newTestInstrumentation(t)
+
+	ctx, call := instrumentation.StartCall(context.Background(), agora.CallInfo{Module: "convoai:join", Method: http.MethodPost, Path: "/join"})
+	_, attempt := call.StartAttempt(ctx, 1)
+	attempt.End(agora.AttemptInfo{Attempt: 1, RegionPrefix: "api-eu-west-1", DomainSuffix: "agora.io"}, errors.New("connection refused"))
+	call.End(nil, errors.New("connection refused"))
+
+	for _, span := range recorder.Ended() {
+		if span.Status().Code != codes.Error {
+			t.Errorf("span %s status = %v, want %v", span.Name(), span.Status().Code, codes.Error)
+		}
+		attrs := attributeMap(span.Attributes())
+		if _, ok := attrs[HttpStatusCodeKey]; ok {
+			t.Errorf("span %s has a status code without response", span.Name())
+		}
+		if got := attrs[OutcomeKey].AsString(); got != "error" {
+			t.Errorf("span %s outcome = %q, want %q", span.Name(), got, "error")
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package agora
+
+import (
+	"context"
+)
+
+// @brief ReconcileFunc checks whether a non-idempotent request whose outcome is unknown has taken effect on the server
+//
+// @return Returns true if the request has taken effect and must not be retried, otherwise false.
+//
+// @return Returns an error object if the check fails. The request is not retried in this case.
+//
+// @since v0.13.0
+type ReconcileFunc func(ctx context.Context) (applied bool, err error)
+
+// @brief Request describes a call to an Agora REST API endpoint
+//
+// @since v0.13.0
+type Request struct {
+	// Name of the API operation, e.g. "cloudRecording:start"
+	Module string
+	// HTTP method
+	Method string
+	// Request path, including the query string
+	Path string
+	// Request body, it is marshaled to JSON. No body is sent when it is nil.
+	Body interface{}
+	// Whether sending the request more than once has the same effect as sending it once.
+	//
+	// A non-idempotent request, e.g. one that creates a billable resource, is only retried when
+	// it provably never reached the server (DNS or dial failure), or when Reconcile reports
+	// that the previous attempt did not take effect.
+	Idempotent bool
+	// Called before retrying a non-idempotent request whose outcome is unknown.(Optional)
+	//
+	// The request is not retried in this case if it is nil.
+	Reconcile ReconcileFunc
+	// Returns the sentinel error, e.g. ErrResourceExpired, identified by the service error code of an APIError.(Optional)
+	//
+	// The result is stored in APIError.Kind.
+	ErrorKind func(apiErr *APIError) error
+	// Decides whether an unsuccessful response is retried based on its service error code.(Optional)
+	//
+	// The retry policy decides based on the HTTP status code when it is nil or decided is false.
+	// A non-idempotent request is still only retried as described for Idempotent.
+	ShouldRetry func(apiErr *APIError) (retry bool, decided bool)
+	// JSON fields, one of which identifies the error response of the service, e.g. "code".(Optional)
+	//
+	// Call returns an unsuccessful response without any of these fields as a gateway error.
+	// When it is empty, any unsuccessful response whose body is a JSON object is an error response of the service.
+	ErrorFields []string
+	// Options of the call, e.g. a timeout or extra headers.(Optional)
+	//
+	// See CallOption for details.
+	Options []CallOption
+}
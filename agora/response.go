@@ -19,6 +19,9 @@ type BaseResponse struct {
 	RawBody []byte
 	// HTTP status code
 	HttpStatusCode int
+
+	apiErr         *APIError
+	discardRawBody bool
 }
 
 // UnmarshalToTarget unmarshal body into target var
@@ -41,3 +44,36 @@ func (r *BaseResponse) GetRequestID() string {
 		return ""
 	}
 }
+
+// @brief Get the error of an unsuccessful response
+//
+// @return Returns an *APIError if the response is unsuccessful, otherwise nil.
+//
+// @since v0.13.0
+func (r *BaseResponse) Err() error {
+	if r == nil || r.apiErr == nil {
+		return nil
+	}
+	return r.apiErr
+}
+
+// @brief Set the error of an unsuccessful response, see Err
+//
+// @since v0.13.0
+func (r *BaseResponse) SetErr(apiErr *APIError) {
+	r.apiErr = apiErr
+}
+
+// @brief Set whether Call drops RawBody once the response is decoded, see Config.DiscardRawBody
+//
+// @since v0.13.0
+func (r *BaseResponse) SetDiscardRawBody(discard bool) {
+	r.discardRawBody = discard
+}
+
+// releaseRawBody drops RawBody if SetDiscardRawBody(true) was called.
+func (r *BaseResponse) releaseRawBody() {
+	if r.discardRawBody {
+		r.RawBody = nil
+	}
+}
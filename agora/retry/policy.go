@@ -0,0 +1,191 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// @brief Policy controls how failed requests are retried
+//
+// @note The same policy applies to every service client, and to both network errors and retryable HTTP status codes.
+//
+// @since v0.13.0
+type Policy struct {
+	// Maximum number of attempts, including the first one.
+	//
+	// Values less than 1 are treated as 1, i.e. no retry.
+	MaxAttempts int
+	// Delay before the first retry.
+	InitialBackoff time.Duration
+	// Upper bound of the delay between two attempts.(Optional)
+	//
+	// No upper bound is applied when it is 0.
+	MaxBackoff time.Duration
+	// Factor by which the delay grows after each retry.
+	//
+	// Values less than 1 are treated as 1, i.e. a constant delay.
+	Multiplier float64
+	// Randomization factor applied to each delay, the value range is [0,1].
+	//
+	// For example, 0.2 spreads each delay randomly over ±20% of its value.
+	Jitter float64
+	// HTTP status codes that are retried.(Optional)
+	//
+	// When it is empty, 429 and 5xx status codes are retried.
+	RetryableStatusCodes []int
+	// Whether to wait for the duration given by the Retry-After response header instead of the computed backoff.
+	HonorRetryAfter bool
+	// Upper bound of the delay given by the Retry-After response header.(Optional)
+	//
+	// MaxBackoff is used when it is 0. The delay is not bounded when both are 0,
+	// but a call is not retried when the delay exceeds the time left before its deadline.
+	MaxRetryAfter time.Duration
+}
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultMultiplier     = 2
+	defaultJitter         = 0.2
+	defaultMaxRetryAfter  = 30 * time.Second
+)
+
+// @brief Returns the default retry policy
+//
+// @note The default policy makes at most 3 attempts with an exponential backoff starting at 500ms,
+// retries 429 and 5xx status codes and honors the Retry-After header up to 30 seconds.
+//
+// @return Returns a new Policy instance that can be modified freely.
+//
+// @since v0.13.0
+func DefaultPolicy() *Policy {
+	return &Policy{
+		MaxAttempts:     defaultMaxAttempts,
+		InitialBackoff:  defaultInitialBackoff,
+		MaxBackoff:      defaultMaxBackoff,
+		Multiplier:      defaultMultiplier,
+		Jitter:          defaultJitter,
+		HonorRetryAfter: true,
+		MaxRetryAfter:   defaultMaxRetryAfter,
+	}
+}
+
+// @brief Returns the given retry policy, or the default one with the given maximum number of attempts
+//
+// @note It backs the deprecated RetryCount variables of the service packages, which only apply when no policy is configured.
+//
+// @param policy The configured retry policy, it may be nil.
+//
+// @param maxAttempts Maximum number of attempts of the default policy.
+//
+// @return Returns policy if it is not nil, otherwise a new default Policy.
+//
+// @since v0.13.0
+func PolicyOrDefault(policy *Policy, maxAttempts int) *Policy {
+	if policy != nil {
+		return policy
+	}
+	policy = DefaultPolicy()
+	policy.MaxAttempts = maxAttempts
+	return policy
+}
+
+// Attempts returns the maximum number of attempts, at least 1.
+func (p *Policy) Attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// IsRetryableStatusCode reports whether a response with the status code should be retried.
+func (p *Policy) IsRetryableStatusCode(statusCode int) bool {
+	if p == nil {
+		return false
+	}
+	if len(p.RetryableStatusCodes) == 0 {
+		return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+	}
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the delay before the (retryCount+1)-th retry, retryCount starts from 0.
+func (p *Policy) Backoff(retryCount int) time.Duration {
+	if p == nil || p.InitialBackoff <= 0 {
+		return 0
+	}
+
+	multiplier := math.Max(p.Multiplier, 1)
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retryCount))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	jitter := math.Min(math.Max(p.Jitter, 0), 1)
+	if jitter > 0 {
+		backoff += backoff * jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(backoff)
+}
+
+// Delay returns the delay before the (retryCount+1)-th retry, taking the Retry-After header of
+// the failed response into account when HonorRetryAfter is set. header may be nil.
+//
+// The delay given by the Retry-After header is bounded by MaxRetryAfter, or by MaxBackoff when it is 0.
+func (p *Policy) Delay(retryCount int, header http.Header) time.Duration {
+	if p != nil && p.HonorRetryAfter && header != nil {
+		if d, ok := ParseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+			limit := p.MaxRetryAfter
+			if limit <= 0 {
+				limit = p.MaxBackoff
+			}
+			if limit > 0 && d > limit {
+				d = limit
+			}
+			return d
+		}
+	}
+	return p.Backoff(retryCount)
+}
+
+// @brief Parses the value of a Retry-After header
+//
+// @param value Either a number of seconds or an HTTP date.
+//
+// @param now The time the HTTP date is relative to.
+//
+// @return Returns the duration to wait and true if value is valid, otherwise false.
+//
+// @since v0.13.0
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		if int64(seconds) > math.MaxInt64/int64(time.Second) {
+			return time.Duration(math.MaxInt64), true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
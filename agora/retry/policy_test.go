@@ -0,0 +1,203 @@
+package retry
+
+import (
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAttempts(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+		want   int
+	}{
+		{name: "nil policy", policy: nil, want: 1},
+		{name: "negative", policy: &Policy{MaxAttempts: -1}, want: 1},
+		{name: "zero", policy: &Policy{MaxAttempts: 0}, want: 1},
+		{name: "one", policy: &Policy{MaxAttempts: 1}, want: 1},
+		{name: "five", policy: &Policy{MaxAttempts: 5}, want: 5},
+		{name: "default", policy: DefaultPolicy(), want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Attempts(); got != tt.want {
+				t.Errorf("Attempts() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyOrDefault(t *testing.T) {
+	policy := &Policy{MaxAttempts: 7}
+	if got := PolicyOrDefault(policy, 5); got != policy {
+		t.Errorf("PolicyOrDefault(policy, 5) = %+v, want the given policy", got)
+	}
+
+	got := PolicyOrDefault(nil, 5)
+	want := DefaultPolicy()
+	want.MaxAttempts = 5
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PolicyOrDefault(nil, 5) = %+v, want %+v", got, want)
+	}
+}
+
+func TestIsRetryableStatusCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *Policy
+		statusCode int
+		want       bool
+	}{
+		{name: "nil policy", policy: nil, statusCode: http.StatusServiceUnavailable, want: false},
+		{name: "default 200", policy: &Policy{}, statusCode: http.StatusOK, want: false},
+		{name: "default 404", policy: &Policy{}, statusCode: http.StatusNotFound, want: false},
+		{name: "default 428", policy: &Policy{}, statusCode: 428, want: false},
+		{name: "default 429", policy: &Policy{}, statusCode: http.StatusTooManyRequests, want: true},
+		{name: "default 499", policy: &Policy{}, statusCode: 499, want: false},
+		{name: "default 500", policy: &Policy{}, statusCode: http.StatusInternalServerError, want: true},
+		{name: "default 599", policy: &Policy{}, statusCode: 599, want: true},
+		{name: "custom listed", policy: &Policy{RetryableStatusCodes: []int{http.StatusConflict}}, statusCode: http.StatusConflict, want: true},
+		{name: "custom not listed 503", policy: &Policy{RetryableStatusCodes: []int{http.StatusConflict}}, statusCode: http.StatusServiceUnavailable, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.IsRetryableStatusCode(tt.statusCode); got != tt.want {
+				t.Errorf("IsRetryableStatusCode(%d) = %t, want %t", tt.statusCode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *Policy
+		retryCount int
+		want       time.Duration
+	}{
+		{name: "nil policy", policy: nil, retryCount: 0, want: 0},
+		{name: "no initial backoff", policy: &Policy{Multiplier: 2}, retryCount: 3, want: 0},
+		{name: "first retry", policy: &Policy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2}, retryCount: 0, want: 100 * time.Millisecond},
+		{name: "third retry", policy: &Policy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2}, retryCount: 2, want: 400 * time.Millisecond},
+		{name: "capped", policy: &Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}, retryCount: 2, want: 300 * time.Millisecond},
+		{name: "at cap", policy: &Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 400 * time.Millisecond, Multiplier: 2}, retryCount: 2, want: 400 * time.Millisecond},
+		{name: "no cap", policy: &Policy{InitialBackoff: time.Second, Multiplier: 10}, retryCount: 3, want: 1000 * time.Second},
+		{name: "multiplier below 1 is constant", policy: &Policy{InitialBackoff: 100 * time.Millisecond, Multiplier: 0.5}, retryCount: 4, want: 100 * time.Millisecond},
+		{name: "zero multiplier is constant", policy: &Policy{InitialBackoff: 100 * time.Millisecond}, retryCount: 4, want: 100 * time.Millisecond},
+		{name: "negative jitter is ignored", policy: &Policy{InitialBackoff: 100 * time.Millisecond, Multiplier: 1, Jitter: -1}, retryCount: 1, want: 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Backoff(tt.retryCount); got != tt.want {
+				t.Errorf("Backoff(%d) = %v, want %v", tt.retryCount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter float64
+		min    time.Duration
+		max    time.Duration
+	}{
+		{name: "20%", jitter: 0.2, min: 800 * time.Millisecond, max: 1200 * time.Millisecond},
+		{name: "100%", jitter: 1, min: 0, max: 2 * time.Second},
+		{name: "over 100% is capped", jitter: 5, min: 0, max: 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &Policy{InitialBackoff: time.Second, Multiplier: 1, Jitter: tt.jitter}
+			varied := false
+			first := policy.Backoff(0)
+			for i := 0; i < 1000; i++ {
+				got := policy.Backoff(0)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("Backoff(0) = %v, want in [%v,%v]", got, tt.min, tt.max)
+				}
+				if got != first {
+					varied = true
+				}
+			}
+			if !varied {
+				t.Errorf("Backoff(0) = %v every time, want a randomized delay", first)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "empty", value: "", wantOK: false},
+		{name: "zero seconds", value: "0", want: 0, wantOK: true},
+		{name: "seconds", value: "120", want: 2 * time.Minute, wantOK: true},
+		{name: "negative seconds", value: "-1", wantOK: false},
+		{name: "fractional seconds", value: "1.5", wantOK: false},
+		{name: "seconds overflowing a duration", value: "99999999999999", want: time.Duration(math.MaxInt64), wantOK: true},
+		{name: "http date in the future", value: "Wed, 01 May 2024 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{name: "http date now", value: "Wed, 01 May 2024 12:00:00 GMT", want: 0, wantOK: true},
+		{name: "http date in the past", value: "Wed, 01 May 2024 11:59:00 GMT", want: 0, wantOK: true},
+		{name: "rfc 850 date", value: "Wednesday, 01-May-24 12:01:00 GMT", want: time.Minute, wantOK: true},
+		{name: "ansi c date", value: "Wed May  1 12:00:10 2024", want: 10 * time.Second, wantOK: true},
+		{name: "garbage", value: "soon", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tt.value, now)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRetryAfter(%q) ok = %t, want %t", tt.value, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDelay(t *testing.T) {
+	retryAfter := http.Header{}
+	retryAfter.Set("Retry-After", "7")
+	invalidRetryAfter := http.Header{}
+	invalidRetryAfter.Set("Retry-After", "soon")
+	dayRetryAfter := http.Header{}
+	dayRetryAfter.Set("Retry-After", "86400")
+	futureDateRetryAfter := http.Header{}
+	futureDateRetryAfter.Set("Retry-After", time.Now().Add(365*24*time.Hour).UTC().Format(http.TimeFormat))
+
+	tests := []struct {
+		name   string
+		policy *Policy
+		header http.Header
+		want   time.Duration
+	}{
+		{name: "nil policy", policy: nil, header: retryAfter, want: 0},
+		{name: "honor retry after", policy: &Policy{InitialBackoff: time.Second, HonorRetryAfter: true}, header: retryAfter, want: 7 * time.Second},
+		{name: "ignore retry after", policy: &Policy{InitialBackoff: time.Second}, header: retryAfter, want: time.Second},
+		{name: "invalid retry after falls back to backoff", policy: &Policy{InitialBackoff: time.Second, HonorRetryAfter: true}, header: invalidRetryAfter, want: time.Second},
+		{name: "no header", policy: &Policy{InitialBackoff: time.Second, HonorRetryAfter: true}, header: nil, want: time.Second},
+		{name: "below max retry after", policy: &Policy{HonorRetryAfter: true, MaxRetryAfter: 7 * time.Second}, header: retryAfter, want: 7 * time.Second},
+		{name: "above max retry after", policy: &Policy{HonorRetryAfter: true, MaxRetryAfter: 5 * time.Second}, header: retryAfter, want: 5 * time.Second},
+		{name: "max retry after over max backoff", policy: &Policy{HonorRetryAfter: true, MaxBackoff: time.Second, MaxRetryAfter: time.Minute}, header: dayRetryAfter, want: time.Minute},
+		{name: "bounded by max backoff", policy: &Policy{HonorRetryAfter: true, MaxBackoff: 3 * time.Second}, header: retryAfter, want: 3 * time.Second},
+		{name: "unbounded", policy: &Policy{HonorRetryAfter: true}, header: dayRetryAfter, want: 24 * time.Hour},
+		{name: "default policy bounds a day", policy: DefaultPolicy(), header: dayRetryAfter, want: 30 * time.Second},
+		{name: "default policy bounds a future date", policy: DefaultPolicy(), header: futureDateRetryAfter, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Delay(0, tt.header); got != tt.want {
+				t.Errorf("Delay(0) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,10 @@
 package retry
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
-
-	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 )
 
 type Func func(retryCount int) error
@@ -15,7 +15,20 @@ type OnFailedAttemptFunc func(error)
 
 type DelayFunc func(int) time.Duration
 
+// needRetryErr is implemented by errors that decide whether the failed attempt is retried, see agora.RetryErr.
+type needRetryErr interface {
+	error
+	NeedRetry() bool
+}
+
 func Do(retryFunc Func, stopFunc ShouldStopFunc, delayFunc DelayFunc, attemptFunc OnFailedAttemptFunc) error {
+	return DoWithContext(context.Background(), retryFunc, stopFunc, delayFunc, attemptFunc)
+}
+
+// DoWithContext is like Do, but the delay between attempts is interrupted when ctx is done.
+//
+// The returned error wraps ctx.Err() and the last error in this case.
+func DoWithContext(ctx context.Context, retryFunc Func, stopFunc ShouldStopFunc, delayFunc DelayFunc, attemptFunc OnFailedAttemptFunc) error {
 	var (
 		retryCount int
 		err        error
@@ -27,7 +40,7 @@ func Do(retryFunc Func, stopFunc ShouldStopFunc, delayFunc DelayFunc, attemptFun
 		if err == nil {
 			return nil
 		}
-		var retryErr *agora.RetryErr
+		var retryErr needRetryErr
 		if errors.As(err, &retryErr) {
 			if !retryErr.NeedRetry() {
 				return retryErr
@@ -39,9 +52,26 @@ func Do(retryFunc Func, stopFunc ShouldStopFunc, delayFunc DelayFunc, attemptFun
 		}
 		stopRetry = stopFunc()
 		if !stopRetry && delayFunc != nil {
-			time.Sleep(delayFunc(retryCount))
+			if sleepErr := sleep(ctx, delayFunc(retryCount)); sleepErr != nil {
+				return fmt.Errorf("%w, last error:%s", sleepErr, err)
+			}
 		}
 		retryCount++
 	}
 	return err
 }
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
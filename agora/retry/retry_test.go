@@ -0,0 +1,98 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testRetryErr struct {
+	needRetry bool
+}
+
+func (e *testRetryErr) Error() string {
+	return "test error"
+}
+
+func (e *testRetryErr) NeedRetry() bool {
+	return e.needRetry
+}
+
+func TestDo(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		errs        []error
+		wantCalls   int
+		wantErr     error
+	}{
+		{name: "success", maxAttempts: 3, errs: []error{nil}, wantCalls: 1},
+		{name: "success after a retry", maxAttempts: 3, errs: []error{errFailed, nil}, wantCalls: 2},
+		{name: "attempts exhausted", maxAttempts: 3, errs: []error{errFailed, errFailed, errFailed}, wantCalls: 3, wantErr: errFailed},
+		{name: "no retry", maxAttempts: 3, errs: []error{&testRetryErr{needRetry: false}}, wantCalls: 1, wantErr: &testRetryErr{}},
+		{name: "retry requested", maxAttempts: 3, errs: []error{&testRetryErr{needRetry: true}, nil}, wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls, failed int
+			err := Do(func(retryCount int) error {
+				if retryCount != calls {
+					t.Errorf("retryCount = %d, want %d", retryCount, calls)
+				}
+				calls++
+				return tt.errs[retryCount]
+			}, func() bool {
+				return calls >= tt.maxAttempts
+			}, func(int) time.Duration {
+				return 0
+			}, func(error) {
+				failed++
+			})
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("error = %v, want nil", err)
+				}
+			case *testRetryErr:
+				var retryErr *testRetryErr
+				if !errors.As(err, &retryErr) {
+					t.Errorf("error = %v, want a %T", err, want)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Errorf("error = %v, want %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDoWithContextCanceledDuringDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errFailed := errors.New("failed")
+
+	var calls int
+	err := DoWithContext(ctx, func(int) error {
+		calls++
+		cancel()
+		return errFailed
+	}, func() bool {
+		return false
+	}, func(int) time.Duration {
+		return time.Hour
+	}, nil)
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want it to wrap %v", err, context.Canceled)
+	}
+}
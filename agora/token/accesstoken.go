@@ -0,0 +1,327 @@
+package token
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Version of the AccessToken2 format, every token starts with it
+const Version = "007"
+
+// @brief Type of the service a token grants access to
+//
+// @since v0.13.0
+type ServiceType uint16
+
+const (
+	// RTC service
+	ServiceTypeRtc ServiceType = 1
+	// RTM service
+	ServiceTypeRtm ServiceType = 2
+)
+
+// @brief Privilege of a service
+//
+// @since v0.13.0
+type Privilege uint16
+
+const (
+	// Join an RTC channel
+	PrivilegeJoinChannel Privilege = 1
+	// Publish audio streams in an RTC channel
+	PrivilegePublishAudioStream Privilege = 2
+	// Publish video streams in an RTC channel
+	PrivilegePublishVideoStream Privilege = 3
+	// Publish data streams in an RTC channel
+	PrivilegePublishDataStream Privilege = 4
+	// Log in to RTM
+	PrivilegeLogin Privilege = 1
+)
+
+// @brief Maximum validity period of a token
+//
+// @since v0.13.0
+const MaxExpire = 24 * time.Hour
+
+// @brief Service a token grants access to, see NewRtcService and NewRtmService
+//
+// @since v0.13.0
+type Service struct {
+	serviceType ServiceType
+	privileges  map[Privilege]uint32
+	// RTC channel name and user account, or RTM user ID
+	fields []string
+}
+
+// @brief Creates an RTC service for the user account in the channel
+//
+// @note An empty account or "0" matches any user ID.
+//
+// @since v0.13.0
+func NewRtcService(channelName string, account string) *Service {
+	if account == "0" {
+		account = ""
+	}
+	return &Service{
+		serviceType: ServiceTypeRtc,
+		privileges:  make(map[Privilege]uint32),
+		fields:      []string{channelName, account},
+	}
+}
+
+// @brief Creates an RTM service for the user ID
+//
+// @since v0.13.0
+func NewRtmService(userID string) *Service {
+	return &Service{
+		serviceType: ServiceTypeRtm,
+		privileges:  make(map[Privilege]uint32),
+		fields:      []string{userID},
+	}
+}
+
+// @brief Grants a privilege that expires after expire
+//
+// @since v0.13.0
+func (s *Service) AddPrivilege(privilege Privilege, expire time.Duration) {
+	s.privileges[privilege] = uint32(expire / time.Second)
+}
+
+func (s *Service) pack(w *bytes.Buffer) {
+	packUint16(w, uint16(s.serviceType))
+
+	privileges := make([]int, 0, len(s.privileges))
+	for privilege := range s.privileges {
+		privileges = append(privileges, int(privilege))
+	}
+	sort.Ints(privileges)
+	packUint16(w, uint16(len(privileges)))
+	for _, privilege := range privileges {
+		packUint16(w, uint16(privilege))
+		packUint32(w, s.privileges[Privilege(privilege)])
+	}
+
+	for _, field := range s.fields {
+		packString(w, field)
+	}
+}
+
+// @brief AccessToken2 builder
+//
+// @since v0.13.0
+type AccessToken struct {
+	appID          string
+	appCertificate string
+	issueTs        uint32
+	expire         uint32
+	salt           uint32
+	services       map[ServiceType]*Service
+}
+
+// @brief Creates an AccessToken2 builder
+//
+// @param appID App ID of the project
+//
+// @param appCertificate App certificate of the project
+//
+// @param expire Validity period of the token, at most MaxExpire
+//
+// @since v0.13.0
+func NewAccessToken(appID string, appCertificate string, expire time.Duration) *AccessToken {
+	return &AccessToken{
+		appID:          appID,
+		appCertificate: appCertificate,
+		issueTs:        uint32(time.Now().Unix()),
+		expire:         uint32(expire / time.Second),
+		salt:           randomSalt(),
+		services:       make(map[ServiceType]*Service),
+	}
+}
+
+// @brief Adds a service the token grants access to
+//
+// @since v0.13.0
+func (t *AccessToken) AddService(service *Service) {
+	t.services[service.serviceType] = service
+}
+
+// @brief Builds the token
+//
+// @return Returns the token, or an error if the App ID, the App certificate or the validity period is invalid
+//
+// @since v0.13.0
+func (t *AccessToken) Build() (string, error) {
+	if !isUUID(t.appID) {
+		return "", errors.New("invalid app id")
+	}
+	if !isUUID(t.appCertificate) {
+		return "", errors.New("invalid app certificate")
+	}
+	if t.expire == 0 || time.Duration(t.expire)*time.Second > MaxExpire {
+		return "", fmt.Errorf("token expire should be in (0,%s]", MaxExpire)
+	}
+
+	data := new(bytes.Buffer)
+	packString(data, t.appID)
+	packUint32(data, t.issueTs)
+	packUint32(data, t.expire)
+	packUint32(data, t.salt)
+
+	serviceTypes := make([]int, 0, len(t.services))
+	for serviceType := range t.services {
+		serviceTypes = append(serviceTypes, int(serviceType))
+	}
+	sort.Ints(serviceTypes)
+	packUint16(data, uint16(len(serviceTypes)))
+	for _, serviceType := range serviceTypes {
+		t.services[ServiceType(serviceType)].pack(data)
+	}
+
+	h := hmac.New(sha256.New, t.signing())
+	h.Write(data.Bytes())
+
+	content := new(bytes.Buffer)
+	packString(content, string(h.Sum(nil)))
+	content.Write(data.Bytes())
+
+	compressed := new(bytes.Buffer)
+	zw := zlib.NewWriter(compressed)
+	if _, err := zw.Write(content.Bytes()); err != nil {
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	return Version + base64.StdEncoding.EncodeToString(compressed.Bytes()), nil
+}
+
+// signing derives the signing key from the App certificate, the issue timestamp and the salt.
+func (t *AccessToken) signing() []byte {
+	issueTs := new(bytes.Buffer)
+	packUint32(issueTs, t.issueTs)
+	h := hmac.New(sha256.New, issueTs.Bytes())
+	h.Write([]byte(t.appCertificate))
+
+	salt := new(bytes.Buffer)
+	packUint32(salt, t.salt)
+	hSalt := hmac.New(sha256.New, salt.Bytes())
+	hSalt.Write(h.Sum(nil))
+
+	return hSalt.Sum(nil)
+}
+
+// @brief Information in the header of a token, see Parse
+//
+// @since v0.13.0
+type Info struct {
+	// App ID the token is generated with
+	AppID string
+	// Time the token was issued
+	IssuedAt time.Time
+	// Time the token expires
+	ExpiresAt time.Time
+}
+
+// @brief Parses the header of a token without verifying its signature
+//
+// @note Use it to check whether a token is about to expire.
+//
+// @since v0.13.0
+func Parse(token string) (*Info, error) {
+	if len(token) <= len(Version) || token[:len(Version)] != Version {
+		return nil, errors.New("unsupported token version")
+	}
+	compressed, err := base64.StdEncoding.DecodeString(token[len(Version):])
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = zr.Close()
+	}()
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(content)
+	// signature
+	if _, err = unpackString(r); err != nil {
+		return nil, err
+	}
+	appID, err := unpackString(r)
+	if err != nil {
+		return nil, err
+	}
+	var issueTs, expire uint32
+	if err = binary.Read(r, binary.LittleEndian, &issueTs); err != nil {
+		return nil, err
+	}
+	if err = binary.Read(r, binary.LittleEndian, &expire); err != nil {
+		return nil, err
+	}
+
+	issuedAt := time.Unix(int64(issueTs), 0)
+	return &Info{
+		AppID:     appID,
+		IssuedAt:  issuedAt,
+		ExpiresAt: issuedAt.Add(time.Duration(expire) * time.Second),
+	}, nil
+}
+
+func packUint16(w *bytes.Buffer, v uint16) {
+	_ = binary.Write(w, binary.LittleEndian, v)
+}
+
+func packUint32(w *bytes.Buffer, v uint32) {
+	_ = binary.Write(w, binary.LittleEndian, v)
+}
+
+func packString(w *bytes.Buffer, s string) {
+	packUint16(w, uint16(len(s)))
+	w.WriteString(s)
+}
+
+func unpackString(r io.Reader) (string, error) {
+	var length uint16
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return "", err
+	}
+	s := make([]byte, length)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+func randomSalt() uint32 {
+	n, err := rand.Int(rand.Reader, big.NewInt(99999999))
+	if err != nil {
+		return uint32(time.Now().UnixNano()%99999999) + 1
+	}
+	return uint32(n.Int64()) + 1
+}
+
+func isUUID(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
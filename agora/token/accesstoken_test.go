@@ -0,0 +1,206 @@
+package token
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testAppID          = "970CA35de60c44645bbae8a215061b33"
+	testAppCertificate = "5CFd2fd1755d40ecb72977518be15d3b"
+	testChannelName    = "7d72365eb983485397e3e3f9d460bdda"
+	testAccount        = "2882341273"
+	testIssueTs        = 1111111
+	testSalt           = 1
+	testExpire         = 600 * time.Second
+)
+
+// newTestAccessToken returns a builder with a fixed issue timestamp and salt, so that its tokens are reproducible.
+func newTestAccessToken(expire time.Duration) *AccessToken {
+	token := NewAccessToken(testAppID, testAppCertificate, expire)
+	token.issueTs = testIssueTs
+	token.salt = testSalt
+	return token
+}
+
+// decodeContent returns the signature and the signed message of a token.
+func decodeContent(t *testing.T, token string) ([]byte, []byte) {
+	t.Helper()
+
+	if !strings.HasPrefix(token, Version) {
+		t.Fatalf("token %q does not start with %q", token, Version)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(token[len(Version):])
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("decode zlib: %v", err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decode zlib: %v", err)
+	}
+
+	signatureLength := int(binary.LittleEndian.Uint16(content))
+	return content[2 : 2+signatureLength], content[2+signatureLength:]
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatalf("decode hex: %v", err)
+	}
+	return b
+}
+
+// TestBuildMessage compares the signed message and the signature of a token with the AccessToken2 layout,
+// written out byte by byte, and with the signature computed as specified by the format.
+func TestBuildMessage(t *testing.T) {
+	token := newTestAccessToken(testExpire)
+	service := NewRtcService(testChannelName, testAccount)
+	service.AddPrivilege(PrivilegeJoinChannel, testExpire)
+	token.AddService(service)
+
+	built, err := token.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	signature, message := decodeContent(t, built)
+
+	wantMessage := mustHex(t, `
+		2000 `+hex.EncodeToString([]byte(testAppID))+`
+		47f41000
+		58020000
+		01000000
+		0100
+		0100 0100 0100 58020000
+		2000 `+hex.EncodeToString([]byte(testChannelName))+`
+		0a00 `+hex.EncodeToString([]byte(testAccount)))
+	if !bytes.Equal(message, wantMessage) {
+		t.Fatalf("message = %x, want %x", message, wantMessage)
+	}
+
+	// signing key = HMAC(salt, HMAC(issueTs, App certificate)), signature = HMAC(signing key, message)
+	issueTsKey := hmac.New(sha256.New, mustHex(t, "47f41000"))
+	issueTsKey.Write([]byte(testAppCertificate))
+	signingKey := hmac.New(sha256.New, mustHex(t, "01000000"))
+	signingKey.Write(issueTsKey.Sum(nil))
+	wantSignature := hmac.New(sha256.New, signingKey.Sum(nil))
+	wantSignature.Write(wantMessage)
+	if !bytes.Equal(signature, wantSignature.Sum(nil)) {
+		t.Fatalf("signature = %x, want %x", signature, wantSignature.Sum(nil))
+	}
+}
+
+// TestBuildGolden pins the tokens built with a fixed issue timestamp, salt and validity period.
+func TestBuildGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		account string
+		expire  time.Duration
+		want    string
+	}{
+		{
+			name:    "rtc account 600s",
+			account: testAccount,
+			expire:  600 * time.Second,
+			want:    "007eJxSYBBbsMMnKq7p9Hf/HcIX5kce9b518kCiQgSr5Zrp4X1Tu6UUGCzNDZwdjU1TUs0Mkk1MzExMk5ISUy0SjQxNDcwMk4yN3b8IMEQwMTAwMoAwBIL4CgzmKeZGxmamqUmWFsYmFqbGluapxqnGaZYpJmYGSSkpiVwMRhYWRsYmhkbmxoABAMJqJOM=",
+		},
+		{
+			name:    "rtc any user 600s",
+			account: "",
+			expire:  600 * time.Second,
+			want:    "007eJxSYLhzZP08Lxa1Pg57+TcXb/3cZ3wi4V6kbpbOog0G2dOYk20UGCzNDZwdjU1TUs0Mkk1MzExMk5ISUy0SjQxNDcwMk4yN3b8IMEQwMTAwMoAwBIL4CgzmKeZGxmamqUmWFsYmFqbGluapxqnGaZYpJmYGSSkpiQwMgAEANpwiag==",
+		},
+		{
+			name:    "rtc account 3600s",
+			account: testAccount,
+			expire:  3600 * time.Second,
+			want:    "007eJxSYDA/9NDU4bxjaeepZ5c4vvzhi5NxSf2+7PoVi1PVCya3FwgoMFiaGzg7GpumpJoZJJuYmJmYJiUlplokGhmaGpgZJhkbu38RYBDgY2BgZABhCATxFRjMU8yNjM1MU5MsLYxNLEyNLc1TjVON0yxTTMwMklJSErkYjCwsjIxNDI3MjQEDAFewJe4=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := newTestAccessToken(tt.expire)
+			service := NewRtcService(testChannelName, tt.account)
+			service.AddPrivilege(PrivilegeJoinChannel, tt.expire)
+			token.AddService(service)
+
+			got, err := token.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildInvalid(t *testing.T) {
+	tests := []struct {
+		name           string
+		appID          string
+		appCertificate string
+		expire         time.Duration
+	}{
+		{name: "short app id", appID: testAppID[:31], appCertificate: testAppCertificate, expire: testExpire},
+		{name: "non hex app id", appID: "z" + testAppID[1:], appCertificate: testAppCertificate, expire: testExpire},
+		{name: "empty app certificate", appID: testAppID, appCertificate: "", expire: testExpire},
+		{name: "zero expire", appID: testAppID, appCertificate: testAppCertificate, expire: 0},
+		{name: "expire over max", appID: testAppID, appCertificate: testAppCertificate, expire: MaxExpire + time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAccessToken(tt.appID, tt.appCertificate, tt.expire).Build(); err == nil {
+				t.Error("Build() error = nil, want an error")
+			}
+		})
+	}
+
+	if _, err := NewAccessToken(testAppID, testAppCertificate, MaxExpire).Build(); err != nil {
+		t.Errorf("Build() with MaxExpire error = %v, want nil", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	token := newTestAccessToken(testExpire)
+	token.AddService(NewRtmService(testAccount))
+	built, err := token.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	info, err := Parse(built)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if info.AppID != testAppID {
+		t.Errorf("AppID = %s, want %s", info.AppID, testAppID)
+	}
+	if !info.IssuedAt.Equal(time.Unix(testIssueTs, 0)) {
+		t.Errorf("IssuedAt = %v, want %v", info.IssuedAt, time.Unix(testIssueTs, 0))
+	}
+	if !info.ExpiresAt.Equal(time.Unix(testIssueTs, 0).Add(testExpire)) {
+		t.Errorf("ExpiresAt = %v, want %v", info.ExpiresAt, time.Unix(testIssueTs, 0).Add(testExpire))
+	}
+
+	for _, invalid := range []string{"", "006" + built[3:], Version, Version + "!!!", Version + base64.StdEncoding.EncodeToString([]byte("not zlib"))} {
+		if _, err := Parse(invalid); err == nil {
+			t.Errorf("Parse(%q) error = nil, want an error", invalid)
+		}
+	}
+}
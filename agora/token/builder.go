@@ -0,0 +1,110 @@
+package token
+
+import (
+	"strconv"
+	"time"
+)
+
+// @brief Role of a user in an RTC channel
+//
+// @since v0.13.0
+type Role int
+
+const (
+	// A publisher can join the channel and publish audio, video and data streams
+	RolePublisher Role = 1
+	// A subscriber can only join the channel
+	RoleSubscriber Role = 2
+)
+
+// @brief Default validity period of the tokens minted by the REST Client
+//
+// @since v0.13.0
+const DefaultExpire = MaxExpire
+
+func newRtcService(channelName string, account string, role Role, privilegeExpire time.Duration) *Service {
+	service := NewRtcService(channelName, account)
+	service.AddPrivilege(PrivilegeJoinChannel, privilegeExpire)
+	if role == RolePublisher {
+		service.AddPrivilege(PrivilegePublishAudioStream, privilegeExpire)
+		service.AddPrivilege(PrivilegePublishVideoStream, privilegeExpire)
+		service.AddPrivilege(PrivilegePublishDataStream, privilegeExpire)
+	}
+	return service
+}
+
+// @brief Builds an RTC token for a user account
+//
+// @param appID App ID of the project
+//
+// @param appCertificate App certificate of the project
+//
+// @param channelName Channel name
+//
+// @param account User account, or a user ID formatted as a string. An empty account or "0" matches any user ID.
+//
+// @param role Role of the user, see Role for details
+//
+// @param tokenExpire Validity period of the token, at most MaxExpire
+//
+// @param privilegeExpire Validity period of the privileges, at most tokenExpire
+//
+// @return Returns the token, or an error if the parameters are invalid
+//
+// @since v0.13.0
+func BuildRtcToken(appID string, appCertificate string, channelName string, account string, role Role,
+	tokenExpire time.Duration, privilegeExpire time.Duration,
+) (string, error) {
+	token := NewAccessToken(appID, appCertificate, tokenExpire)
+	token.AddService(newRtcService(channelName, account, role, privilegeExpire))
+
+	return token.Build()
+}
+
+// @brief Builds an RTC token for a user ID, see BuildRtcToken
+//
+// @since v0.13.0
+func BuildRtcTokenWithUID(appID string, appCertificate string, channelName string, uid uint32, role Role,
+	tokenExpire time.Duration, privilegeExpire time.Duration,
+) (string, error) {
+	return BuildRtcToken(appID, appCertificate, channelName, strconv.FormatUint(uint64(uid), 10), role, tokenExpire, privilegeExpire)
+}
+
+// @brief Builds an RTM token
+//
+// @param appID App ID of the project
+//
+// @param appCertificate App certificate of the project
+//
+// @param userID RTM user ID
+//
+// @param expire Validity period of the token, at most MaxExpire
+//
+// @return Returns the token, or an error if the parameters are invalid
+//
+// @since v0.13.0
+func BuildRtmToken(appID string, appCertificate string, userID string, expire time.Duration) (string, error) {
+	token := NewAccessToken(appID, appCertificate, expire)
+	service := NewRtmService(userID)
+	service.AddPrivilege(PrivilegeLogin, expire)
+	token.AddService(service)
+
+	return token.Build()
+}
+
+// @brief Builds a token with both RTC and RTM privileges, e.g. for a Conversational AI agent with enable_rtm set
+//
+// @note The RTM user ID is the same as the RTC user account. See BuildRtcToken for the parameters.
+//
+// @since v0.13.0
+func BuildRtcRtmToken(appID string, appCertificate string, channelName string, account string, role Role,
+	tokenExpire time.Duration, privilegeExpire time.Duration,
+) (string, error) {
+	token := NewAccessToken(appID, appCertificate, tokenExpire)
+	token.AddService(newRtcService(channelName, account, role, privilegeExpire))
+	rtm := NewRtmService(account)
+	rtm.AddPrivilege(PrivilegeLogin, tokenExpire)
+	token.AddService(rtm)
+
+	return token.Build()
+}
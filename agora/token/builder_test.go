@@ -0,0 +1,166 @@
+package token
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// decodedService is a service decoded from the signed message of a token.
+type decodedService struct {
+	privileges map[Privilege]uint32
+	fields     []string
+}
+
+// decodeServices returns the services of a token by type.
+func decodeServices(t *testing.T, token string) map[ServiceType]decodedService {
+	t.Helper()
+
+	_, message := decodeContent(t, token)
+	r := bytes.NewReader(message)
+	read := func(v interface{}) {
+		if err := binary.Read(r, binary.LittleEndian, v); err != nil {
+			t.Fatalf("decode message: %v", err)
+		}
+	}
+	readString := func() string {
+		s, err := unpackString(r)
+		if err != nil {
+			t.Fatalf("decode message: %v", err)
+		}
+		return s
+	}
+
+	// app ID, issue timestamp, validity period and salt
+	readString()
+	var header [3]uint32
+	read(&header)
+
+	var serviceCount uint16
+	read(&serviceCount)
+	services := make(map[ServiceType]decodedService, serviceCount)
+	for i := 0; i < int(serviceCount); i++ {
+		var serviceType, privilegeCount uint16
+		read(&serviceType)
+		read(&privilegeCount)
+		service := decodedService{privileges: make(map[Privilege]uint32, privilegeCount)}
+		for j := 0; j < int(privilegeCount); j++ {
+			var privilege uint16
+			var expire uint32
+			read(&privilege)
+			read(&expire)
+			service.privileges[Privilege(privilege)] = expire
+		}
+		fieldCount := 1
+		if ServiceType(serviceType) == ServiceTypeRtc {
+			fieldCount = 2
+		}
+		for j := 0; j < fieldCount; j++ {
+			service.fields = append(service.fields, readString())
+		}
+		services[ServiceType(serviceType)] = service
+	}
+	if r.Len() != 0 {
+		t.Fatalf("%d bytes left after the services", r.Len())
+	}
+
+	return services
+}
+
+func TestBuildRoundTrip(t *testing.T) {
+	const (
+		tokenExpire     = time.Hour
+		privilegeExpire = 30 * time.Minute
+	)
+	publisher := map[Privilege]uint32{
+		PrivilegeJoinChannel:        1800,
+		PrivilegePublishAudioStream: 1800,
+		PrivilegePublishVideoStream: 1800,
+		PrivilegePublishDataStream:  1800,
+	}
+	subscriber := map[Privilege]uint32{
+		PrivilegeJoinChannel: 1800,
+	}
+
+	tests := []struct {
+		name  string
+		build func() (string, error)
+		want  map[ServiceType]decodedService
+	}{
+		{
+			name: "rtc publisher",
+			build: func() (string, error) {
+				return BuildRtcToken(testAppID, testAppCertificate, testChannelName, "agent", RolePublisher, tokenExpire, privilegeExpire)
+			},
+			want: map[ServiceType]decodedService{
+				ServiceTypeRtc: {privileges: publisher, fields: []string{testChannelName, "agent"}},
+			},
+		},
+		{
+			name: "rtc subscriber with uid",
+			build: func() (string, error) {
+				return BuildRtcTokenWithUID(testAppID, testAppCertificate, testChannelName, 2882341273, RoleSubscriber, tokenExpire, privilegeExpire)
+			},
+			want: map[ServiceType]decodedService{
+				ServiceTypeRtc: {privileges: subscriber, fields: []string{testChannelName, testAccount}},
+			},
+		},
+		{
+			name: "rtc uid 0 matches any user",
+			build: func() (string, error) {
+				return BuildRtcTokenWithUID(testAppID, testAppCertificate, testChannelName, 0, RoleSubscriber, tokenExpire, privilegeExpire)
+			},
+			want: map[ServiceType]decodedService{
+				ServiceTypeRtc: {privileges: subscriber, fields: []string{testChannelName, ""}},
+			},
+		},
+		{
+			name: "rtm",
+			build: func() (string, error) {
+				return BuildRtmToken(testAppID, testAppCertificate, "agent", tokenExpire)
+			},
+			want: map[ServiceType]decodedService{
+				ServiceTypeRtm: {privileges: map[Privilege]uint32{PrivilegeLogin: 3600}, fields: []string{"agent"}},
+			},
+		},
+		{
+			name: "rtc and rtm",
+			build: func() (string, error) {
+				return BuildRtcRtmToken(testAppID, testAppCertificate, testChannelName, "agent", RolePublisher, tokenExpire, privilegeExpire)
+			},
+			want: map[ServiceType]decodedService{
+				ServiceTypeRtc: {privileges: publisher, fields: []string{testChannelName, "agent"}},
+				ServiceTypeRtm: {privileges: map[Privilege]uint32{PrivilegeLogin: 3600}, fields: []string{"agent"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now().Truncate(time.Second)
+			token, err := tt.build()
+			if err != nil {
+				t.Fatalf("build error = %v", err)
+			}
+
+			info, err := Parse(token)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if info.AppID != testAppID {
+				t.Errorf("AppID = %s, want %s", info.AppID, testAppID)
+			}
+			if info.IssuedAt.Before(before) || info.IssuedAt.After(time.Now()) {
+				t.Errorf("IssuedAt = %v, want the build time", info.IssuedAt)
+			}
+			if got := info.ExpiresAt.Sub(info.IssuedAt); got != tokenExpire {
+				t.Errorf("ExpiresAt - IssuedAt = %v, want %v", got, tokenExpire)
+			}
+
+			if got := decodeServices(t, token); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("services = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
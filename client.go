@@ -0,0 +1,216 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	agoraClient "github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/domain"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudtranscoder"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/convoai"
+)
+
+// @brief Defines the configuration shared by the services of the client
+//
+// @since v0.13.0
+type Config struct {
+	agora.Config
+
+	// Region of the Conversational AI engine service.(Optional)
+	//
+	// The default value is convoai.ChineseMainlandServiceRegion when DomainArea is domain.CN,
+	// and convoai.GlobalServiceRegion otherwise. See convoai.ServiceRegion for details.
+	ConvoAIServiceRegion convoai.ServiceRegion
+}
+
+// @brief Client of all the Agora REST services, which share one transport, domain pool, credential and logger
+//
+// @note The rate limits, retry policy and circuit breakers of Config apply to each service separately.
+//
+// @since v0.13.0
+type Client struct {
+	transport  *http.Transport
+	domainPool *domain.Pool
+
+	cloudRecording  *cloudrecording.Client
+	convoAI         *convoai.Client
+	cloudTranscoder *cloudtranscoder.Client
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewClient
+//
+// @brief Creates a client of all the Agora REST services with the specified configuration
+//
+// @note The configuration is validated once, see agora.Config.Validate. Call Close when the client is no longer used.
+//
+// @param config Configuration of the client. See Config for details.
+//
+// @return Returns the client.
+//
+// @return Returns an error object. If the configuration is invalid, the error object is not nil and contains error information.
+//
+// @since v0.13.0
+func NewClient(config *Config) (*Client, error) {
+	if config == nil {
+		return nil, errors.New("agora: config is required")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	cfg := config.Config
+	if cfg.Logger == nil {
+		cfg.Logger = log.DefaultLogger
+	}
+
+	c := &Client{}
+	if cfg.HttpClient == nil && cfg.Transport == nil {
+		if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+			c.transport = transport.Clone()
+			cfg.Transport = c.transport
+		}
+	}
+
+	domainPool, err := agoraClient.NewDomainPool(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.domainPool = domainPool
+	shared := agoraClient.WithDomainPool(domainPool)
+
+	if c.cloudRecording, err = cloudrecording.NewClient(cloudRecordingConfig(&cfg), shared); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	if c.convoAI, err = convoai.NewClient(convoAIConfig(&cfg, config.ConvoAIServiceRegion), shared); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	transcoderCfg := cfg
+	if c.cloudTranscoder, err = cloudtranscoder.NewClient(&transcoderCfg, shared); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// @brief Returns the Cloud Recording client
+//
+// @since v0.13.0
+func (c *Client) CloudRecording() *cloudrecording.Client {
+	return c.cloudRecording
+}
+
+// @brief Returns the Conversational AI engine client
+//
+// @since v0.13.0
+func (c *Client) ConvoAI() *convoai.Client {
+	return c.convoAI
+}
+
+// @brief Returns the Cloud Transcoder client
+//
+// @since v0.13.0
+func (c *Client) CloudTranscoder() *cloudtranscoder.Client {
+	return c.cloudTranscoder
+}
+
+// @brief Closes the services, stops the background domain refresh and closes the idle connections of the shared transport
+//
+// @note Calling Close more than once returns the result of the first call. The client must not be used after it is closed.
+//
+// @since v0.13.0
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		if c.cloudRecording != nil {
+			_ = c.cloudRecording.Close()
+		}
+		if c.convoAI != nil {
+			_ = c.convoAI.Close()
+		}
+		if c.cloudTranscoder != nil {
+			_ = c.cloudTranscoder.Close()
+		}
+		if c.domainPool != nil {
+			c.closeErr = c.domainPool.Close()
+		}
+		if c.transport != nil {
+			c.transport.CloseIdleConnections()
+		}
+	})
+	return c.closeErr
+}
+
+func cloudRecordingConfig(config *agora.Config) *cloudrecording.Config {
+	return &cloudrecording.Config{
+		AppID:                 config.AppID,
+		AppCertificate:        config.AppCertificate,
+		HttpTimeout:           config.HttpTimeout,
+		HttpClient:            config.HttpClient,
+		Transport:             config.Transport,
+		Middlewares:           config.Middlewares,
+		MaxResponseBodySize:   config.MaxResponseBodySize,
+		EnableGzip:            config.EnableGzip,
+		DiscardRawBody:        config.DiscardRawBody,
+		Hooks:                 config.Hooks,
+		RetryPolicy:           config.RetryPolicy,
+		RateLimit:             config.RateLimit,
+		Instrumentation:       config.Instrumentation,
+		Credential:            config.Credential,
+		CredentialProvider:    config.CredentialProvider,
+		DomainArea:            config.DomainArea,
+		Domain:                config.Domain,
+		Resolver:              config.Resolver,
+		BaseURL:               config.BaseURL,
+		HealthPolicy:          config.HealthPolicy,
+		CircuitBreaker:        config.CircuitBreaker,
+		DomainRefreshInterval: config.DomainRefreshInterval,
+		OnDomainResolveError:  config.OnDomainResolveError,
+		Logger:                config.Logger,
+	}
+}
+
+func convoAIConfig(config *agora.Config, serviceRegion convoai.ServiceRegion) *convoai.Config {
+	if serviceRegion == convoai.UnknownServiceRegion {
+		serviceRegion = convoai.GlobalServiceRegion
+		if config.DomainArea == domain.CN {
+			serviceRegion = convoai.ChineseMainlandServiceRegion
+		}
+	}
+
+	return &convoai.Config{
+		AppID:                 config.AppID,
+		AppCertificate:        config.AppCertificate,
+		HttpTimeout:           config.HttpTimeout,
+		HttpClient:            config.HttpClient,
+		Transport:             config.Transport,
+		Middlewares:           config.Middlewares,
+		MaxResponseBodySize:   config.MaxResponseBodySize,
+		EnableGzip:            config.EnableGzip,
+		DiscardRawBody:        config.DiscardRawBody,
+		Hooks:                 config.Hooks,
+		RetryPolicy:           config.RetryPolicy,
+		RateLimit:             config.RateLimit,
+		Instrumentation:       config.Instrumentation,
+		Credential:            config.Credential,
+		CredentialProvider:    config.CredentialProvider,
+		DomainArea:            config.DomainArea,
+		Domain:                config.Domain,
+		Resolver:              config.Resolver,
+		BaseURL:               config.BaseURL,
+		HealthPolicy:          config.HealthPolicy,
+		CircuitBreaker:        config.CircuitBreaker,
+		DomainRefreshInterval: config.DomainRefreshInterval,
+		OnDomainResolveError:  config.OnDomainResolveError,
+		Logger:                config.Logger,
+		ServiceRegion:         serviceRegion,
+	}
+}
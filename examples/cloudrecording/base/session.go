@@ -0,0 +1,113 @@
+package base
+
+import (
+	"context"
+	"log"
+	"time"
+
+	cloudRecordingAPI "github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/session"
+)
+
+// queryInterval is the interval of the status polling and of the queries of the examples
+const queryInterval = 10 * time.Second
+
+// RunSession acquires a resource and starts a recording of the channel, calls run while it is recording, then stops it.
+//
+// The status of the recording is polled in the background and its transitions are logged.
+func (s *Service) RunSession(mode string, start *cloudRecordingAPI.StartClientRequest, asyncStop bool,
+	run func(ctx context.Context, recording *session.Session),
+) {
+	ctx := context.Background()
+
+	// acquire and start
+	recording, err := s.CloudRecordingClient.Sessions().StartSession(ctx, &session.Spec{
+		Mode:         mode,
+		Cname:        s.Cname,
+		Uid:          s.Uid,
+		Start:        start,
+		PollInterval: queryInterval,
+		OnTransition: func(transition session.Transition) {
+			log.Printf("status changed from %s to %s\n", transition.From, transition.To)
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer recording.Close()
+	log.Printf("start success, resourceId:%s, sid:%s\n", recording.ResourceID(), recording.Sid())
+
+	// stop
+	defer func() {
+		stopResp, err := recording.Stop(ctx, asyncStop)
+		if err != nil {
+			log.Printf("stop failed:%s, status:%s, err:%v\n", err, recording.Status(), recording.Err())
+			return
+		}
+		if stopResp.IsSuccess() {
+			log.Printf("stop success:%+v, serverResponse:%s\n", stopResp, stopResp.SuccessResponse.GetRawServerResponse())
+		} else {
+			log.Printf("stop failed:%+v\n", stopResp)
+		}
+	}()
+
+	run(ctx, recording)
+}
+
+// WaitForStatus waits until the recording reaches status, it returns false if the recording ends or timeout elapses before.
+func WaitForStatus(recording *session.Session, status session.Status, timeout time.Duration) bool {
+	if recording.Status() == status {
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case transition, ok := <-recording.Transitions():
+			if !ok {
+				log.Printf("recording exited, status:%s, err:%v\n", recording.Status(), recording.Err())
+				return false
+			}
+			if transition.To == status {
+				return true
+			}
+		case <-timer.C:
+			log.Printf("recording not %s after %s\n", status, timeout)
+			return false
+		}
+	}
+}
+
+// Query queries the status of the recording n times, it returns false if a query fails or the recording ends.
+func Query(ctx context.Context, recording *session.Session, n int) bool {
+	for i := 0; i < n; i++ {
+		queryResp, err := recording.Query(ctx)
+		if !Succeeded("query", queryResp, err) {
+			return false
+		}
+
+		select {
+		case <-recording.Done():
+			log.Printf("recording exited, status:%s, err:%v\n", recording.Status(), recording.Err())
+			return false
+		case <-time.After(queryInterval):
+		}
+	}
+	return true
+}
+
+// Succeeded logs the result of an operation and reports whether it succeeded, resp is not used when err is not nil.
+func Succeeded(operation string, resp interface{ IsSuccess() bool }, err error) bool {
+	if err != nil {
+		log.Println(err)
+		return false
+	}
+	if !resp.IsSuccess() {
+		log.Printf("%s failed:%+v\n", operation, resp)
+		return false
+	}
+	log.Printf("%s success:%+v\n", operation, resp)
+	return true
+}
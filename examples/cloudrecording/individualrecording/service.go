@@ -2,12 +2,10 @@ package individualrecording
 
 import (
 	"context"
-	"log"
-	"time"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/examples/cloudrecording/base"
 	cloudRecordingAPI "github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
-	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/req"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/session"
 )
 
 type Scenario struct {
@@ -21,24 +19,7 @@ func NewScenario(s *base.Service) *Scenario {
 }
 
 func (s *Scenario) RunRecording(token string, storageConfig *cloudRecordingAPI.StorageConfig) {
-	ctx := context.Background()
-
-	// acquire
-	acquireResp, err := s.CloudRecordingClient.IndividualRecording().Acquire(ctx, s.Cname, s.Uid, &req.AcquireIndividualRecordingClientRequest{})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if acquireResp.IsSuccess() {
-		log.Printf("acquire success:%+v\n", acquireResp)
-	} else {
-		log.Printf("acquire failed:%+v\n", acquireResp)
-		return
-	}
-
-	resourceId := acquireResp.SuccessRes.ResourceId
-	// start
-	startResp, err := s.CloudRecordingClient.IndividualRecording().Start(ctx, resourceId, s.Cname, s.Uid, &req.StartIndividualRecordingClientRequest{
+	s.RunSession(cloudRecordingAPI.IndividualMode, &cloudRecordingAPI.StartClientRequest{
 		Token: token,
 		RecordingConfig: &cloudRecordingAPI.RecordingConfig{
 			ChannelType: 1,
@@ -58,111 +39,11 @@ func (s *Scenario) RunRecording(token string, storageConfig *cloudRecordingAPI.S
 			},
 		},
 		StorageConfig: storageConfig,
-	})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if startResp.IsSuccess() {
-		log.Printf("start success:%+v\n", startResp)
-	} else {
-		log.Printf("start failed:%+v\n", startResp)
-		return
-	}
-
-	sid := startResp.SuccessResponse.Sid
-	// stop
-	defer func() {
-		stopResp, err := s.CloudRecordingClient.IndividualRecording().Stop(ctx, resourceId, sid, s.Cname, s.Uid, false)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if stopResp.IsSuccess() {
-			log.Printf("stop success:%+v\n", stopResp)
-		} else {
-			log.Printf("stop failed:%+v\n", stopResp)
-			return
-		}
-	}()
-
-	// query
-	for i := 0; i < 3; i++ {
-		queryResp, err := s.CloudRecordingClient.IndividualRecording().Query(ctx, resourceId, sid)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if queryResp.IsSuccess() {
-			log.Printf("query success:%+v\n", queryResp)
-		} else {
-			log.Printf("query failed:%+v\n", queryResp)
-			return
-		}
-		time.Sleep(time.Second * 10)
-	}
-	// update
-	updateResp, err := s.CloudRecordingClient.IndividualRecording().Update(ctx, resourceId, sid, s.Cname, s.Uid, &req.UpdateIndividualRecordingClientRequest{
-		StreamSubscribe: &cloudRecordingAPI.UpdateStreamSubscribe{
-			AudioUidList: &cloudRecordingAPI.UpdateAudioUIDList{
-				SubscribeAudioUIDs: []string{
-					"#allstream#",
-				},
-			},
-			VideoUidList: &cloudRecordingAPI.UpdateVideoUIDList{
-				SubscribeVideoUIDs: []string{
-					"#allstream#",
-				},
-			},
-		},
-	})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if updateResp.IsSuccess() {
-		log.Printf("update success:%+v\n", updateResp)
-	} else {
-		log.Printf("update failed:%+v\n", updateResp)
-		return
-	}
-
-	// query
-	for i := 0; i < 3; i++ {
-		queryResp, err := s.CloudRecordingClient.IndividualRecording().Query(ctx, resourceId, sid)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if queryResp.IsSuccess() {
-			log.Printf("query success:%+v\n", queryResp)
-		} else {
-			log.Printf("query failed:%+v\n", queryResp)
-			return
-		}
-		time.Sleep(time.Second * 10)
-	}
+	}, false, s.queryAndUpdate)
 }
 
 func (s *Scenario) RunSnapshot(token string, storageConfig *cloudRecordingAPI.StorageConfig) {
-	ctx := context.Background()
-
-	// acquire
-	acquireResp, err := s.CloudRecordingClient.IndividualRecording().Acquire(ctx, s.Cname, s.Uid, &req.AcquireIndividualRecordingClientRequest{})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if acquireResp.IsSuccess() {
-		log.Printf("acquire success:%+v\n", acquireResp)
-	} else {
-		log.Printf("acquire failed:%+v\n", acquireResp)
-		return
-	}
-
-	resourceId := acquireResp.SuccessRes.ResourceId
-	// start
-	startResp, err := s.CloudRecordingClient.IndividualRecording().Start(ctx, resourceId, s.Cname, s.Uid, &req.StartIndividualRecordingClientRequest{
+	s.RunSession(cloudRecordingAPI.IndividualMode, &cloudRecordingAPI.StartClientRequest{
 		Token: token,
 		RecordingConfig: &cloudRecordingAPI.RecordingConfig{
 			ChannelType: 1,
@@ -181,111 +62,11 @@ func (s *Scenario) RunSnapshot(token string, storageConfig *cloudRecordingAPI.St
 			FileType:        []string{"jpg"},
 		},
 		StorageConfig: storageConfig,
-	})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if startResp.IsSuccess() {
-		log.Printf("start success:%+v\n", startResp)
-	} else {
-		log.Printf("start failed:%+v\n", startResp)
-		return
-	}
-
-	sid := startResp.SuccessResponse.Sid
-	// stop
-	defer func() {
-		stopResp, err := s.CloudRecordingClient.IndividualRecording().Stop(ctx, resourceId, sid, s.Cname, s.Uid, false)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if stopResp.IsSuccess() {
-			log.Printf("stop success:%+v\n", stopResp)
-		} else {
-			log.Printf("stop failed:%+v\n", stopResp)
-			return
-		}
-	}()
-
-	// query
-	for i := 0; i < 3; i++ {
-		queryResp, err := s.CloudRecordingClient.IndividualRecording().QueryVideoScreenshot(ctx, resourceId, sid)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if queryResp.IsSuccess() {
-			log.Printf("query success:%+v\n", queryResp)
-		} else {
-			log.Printf("query failed:%+v\n", queryResp)
-			return
-		}
-		time.Sleep(time.Second * 10)
-	}
-
-	// update
-	updateResp, err := s.CloudRecordingClient.IndividualRecording().Update(ctx, resourceId, sid, s.Cname, s.Uid, &req.UpdateIndividualRecordingClientRequest{
-		StreamSubscribe: &cloudRecordingAPI.UpdateStreamSubscribe{
-			AudioUidList: &cloudRecordingAPI.UpdateAudioUIDList{
-				SubscribeAudioUIDs: []string{
-					"#allstream#",
-				},
-			},
-			VideoUidList: &cloudRecordingAPI.UpdateVideoUIDList{
-				SubscribeVideoUIDs: []string{
-					"#allstream#",
-				},
-			},
-		},
-	})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if updateResp.IsSuccess() {
-		log.Printf("update success:%+v\n", updateResp)
-	} else {
-		log.Printf("update failed:%+v\n", updateResp)
-		return
-	}
-
-	// query
-	for i := 0; i < 3; i++ {
-		queryResp, err := s.CloudRecordingClient.IndividualRecording().QueryVideoScreenshot(ctx, resourceId, sid)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if queryResp.IsSuccess() {
-			log.Printf("query success:%+v\n", queryResp)
-		} else {
-			log.Printf("query failed:%+v\n", queryResp)
-			return
-		}
-		time.Sleep(time.Second * 10)
-	}
+	}, false, s.queryAndUpdate)
 }
 
 func (s *Scenario) RunRecordingAndSnapshot(token string, storageConfig *cloudRecordingAPI.StorageConfig) {
-	ctx := context.Background()
-	// acquire
-	acquireResp, err := s.CloudRecordingClient.IndividualRecording().Acquire(ctx, s.Cname, s.Uid, &req.AcquireIndividualRecordingClientRequest{})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if acquireResp.IsSuccess() {
-		log.Printf("acquire success:%+v\n", acquireResp)
-	} else {
-		log.Printf("acquire failed:%+v\n", acquireResp)
-		return
-	}
-
-	resourceId := acquireResp.SuccessRes.ResourceId
-	// start
-	startResp, err := s.CloudRecordingClient.IndividualRecording().Start(ctx, resourceId, s.Cname, s.Uid, &req.StartIndividualRecordingClientRequest{
+	s.RunSession(cloudRecordingAPI.IndividualMode, &cloudRecordingAPI.StartClientRequest{
 		Token: token,
 		RecordingConfig: &cloudRecordingAPI.RecordingConfig{
 			ChannelType: 1,
@@ -309,52 +90,37 @@ func (s *Scenario) RunRecordingAndSnapshot(token string, storageConfig *cloudRec
 			FileType:        []string{"jpg"},
 		},
 		StorageConfig: storageConfig,
-	})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if startResp.IsSuccess() {
-		log.Printf("start success:%+v\n", startResp)
-	} else {
-		log.Printf("start failed:%+v\n", startResp)
-		return
-	}
+	}, false, s.queryAndUpdate)
+}
 
-	sid := startResp.SuccessResponse.Sid
-	// stop
-	defer func() {
-		stopResp, err := s.CloudRecordingClient.IndividualRecording().Stop(ctx, resourceId, sid, s.Cname, s.Uid, false)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if stopResp.IsSuccess() {
-			log.Printf("stop success:%+v\n", stopResp)
-		} else {
-			log.Printf("stop failed:%+v\n", stopResp)
-			return
-		}
-	}()
+func (s *Scenario) RunAudioOnly(token string, storageConfig *cloudRecordingAPI.StorageConfig) {
+	s.RunSession(cloudRecordingAPI.IndividualMode, &cloudRecordingAPI.StartClientRequest{
+		Token: token,
+		RecordingConfig: &cloudRecordingAPI.RecordingConfig{
+			ChannelType: 1,
+			StreamTypes: 0,
+			StreamMode:  "original",
+			MaxIdleTime: 30,
+			SubscribeAudioUIDs: []string{
+				"#allstream#",
+			},
+		},
+		StorageConfig: storageConfig,
+	}, false, func(ctx context.Context, recording *session.Session) {
+		// query
+		base.Query(ctx, recording, 3)
+	})
+}
 
+// queryAndUpdate queries the recording, updates its subscriptions and queries it again.
+func (s *Scenario) queryAndUpdate(ctx context.Context, recording *session.Session) {
 	// query
-	for i := 0; i < 3; i++ {
-		queryResp, err := s.CloudRecordingClient.IndividualRecording().Query(ctx, resourceId, sid)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if queryResp.IsSuccess() {
-			log.Printf("query success:%+v\n", queryResp)
-		} else {
-			log.Printf("query failed:%+v\n", queryResp)
-			return
-		}
-		time.Sleep(time.Second * 10)
+	if !base.Query(ctx, recording, 3) {
+		return
 	}
 
 	// update
-	updateResp, err := s.CloudRecordingClient.IndividualRecording().Update(ctx, resourceId, sid, s.Cname, s.Uid, &req.UpdateIndividualRecordingClientRequest{
+	updateResp, err := recording.Update(ctx, &cloudRecordingAPI.UpdateClientRequest{
 		StreamSubscribe: &cloudRecordingAPI.UpdateStreamSubscribe{
 			AudioUidList: &cloudRecordingAPI.UpdateAudioUIDList{
 				SubscribeAudioUIDs: []string{
@@ -368,30 +134,10 @@ func (s *Scenario) RunRecordingAndSnapshot(token string, storageConfig *cloudRec
 			},
 		},
 	})
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	if updateResp.IsSuccess() {
-		log.Printf("update success:%+v\n", updateResp)
-	} else {
-		log.Printf("update failed:%+v\n", updateResp)
+	if !base.Succeeded("update", updateResp, err) {
 		return
 	}
 
 	// query
-	for i := 0; i < 3; i++ {
-		queryResp, err := s.CloudRecordingClient.IndividualRecording().Query(ctx, resourceId, sid)
-		if err != nil {
-			log.Println(err)
-			return
-		}
-		if queryResp.IsSuccess() {
-			log.Printf("query success:%+v\n", queryResp)
-		} else {
-			log.Printf("query failed:%+v\n", queryResp)
-			return
-		}
-		time.Sleep(time.Second * 10)
-	}
+	base.Query(ctx, recording, 3)
 }
@@ -103,7 +103,7 @@ func main() {
 
 	mode := flag.String("mode", "mix", "recording mode, options is mix/individual/web")
 	mix_scene := flag.String("mix_scene", "hls", "scene for mix mode, options is hls/hls_and_mp4")
-	individual_scene := flag.String("individual_scene", "recording", "scene for individual mode, options is recording/snapshot/recording_and_snapshot/recording_and_postpone_transcoding/recording_and_audio_mix")
+	individual_scene := flag.String("individual_scene", "recording", "scene for individual mode, options is recording/snapshot/recording_and_snapshot/recording_and_postpone_transcoding/recording_and_audio_mix/audio_only")
 	web_scene := flag.String("web_scene", "web_recorder", "scene for web mode, options is web_recorder/web_recorder_and_rtmp_publish")
 	flag.Parse()
 
@@ -134,6 +134,8 @@ func main() {
 			service.RunSnapshot(token, storageConfig)
 		case "recording_and_snapshot":
 			service.RunRecordingAndSnapshot(token, storageConfig)
+		case "audio_only":
+			service.RunAudioOnly(token, storageConfig)
 		default:
 			panic("invalid individual_scene")
 		}
@@ -2,10 +2,7 @@ module github.com/AgoraIO-Community/agora-rest-client-go
 
 go 1.18
 
-require (
-	github.com/pkg/errors v0.9.1
-	github.com/tidwall/gjson v1.18.0
-)
+require github.com/tidwall/gjson v1.18.0
 
 require (
 	github.com/google/uuid v1.6.0
@@ -2,11 +2,8 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
-	"github.com/tidwall/gjson"
-
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
@@ -16,12 +13,11 @@ type Acquire struct {
 	baseHandler
 }
 
-func NewAcquire(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Acquire {
+func NewAcquire(module string, logger log.Logger, client client.Client, prefixPath string) *Acquire {
 	return &Acquire{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -67,37 +63,28 @@ type AcquireSuccessResp struct {
 	ResourceId string `json:"resourceId"`
 }
 
-func (a *Acquire) Do(ctx context.Context, payload *AcquireReqBody) (*AcquireResp, error) {
+func (a *Acquire) Do(ctx context.Context, payload *AcquireReqBody, opts ...agora.CallOption) (*AcquireResp, error) {
 	path := a.buildPath()
 
-	responseData, err := doRESTWithRetry(ctx, a.module, a.logger, a.retryCount, a.client, path, http.MethodPost, payload)
+	result, err := agora.Call[AcquireReqBody, AcquireSuccessResp, ErrResponse](ctx, a.client, &agora.Request{
+		Module:      a.module,
+		Method:      http.MethodPost,
+		Path:        path,
+		ErrorKind:   errorKind,
+		ShouldRetry: shouldRetry,
+		// A duplicated acquire only leaves an unused resource ID
+		Idempotent:  true,
+		ErrorFields: errorFields,
+		Options:     opts,
+	}, payload)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var resp AcquireResp
+	resp.BaseResponse = result.Response
+	resp.SuccessRes = result.SuccessRes
+	resp.ErrResponse = result.ErrResponse
 
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse AcquireSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		resp.SuccessRes = successResponse
-	} else {
-		codeResult := gjson.GetBytes(responseData.RawBody, "code")
-		if !codeResult.Exists() {
-			return nil, agora.NewGatewayErr(responseData.HttpStatusCode, string(responseData.RawBody))
-		}
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		resp.ErrResponse = errResponse
-	}
-
-	resp.BaseResponse = responseData
 	return &resp, nil
 }
@@ -17,12 +17,11 @@ type Query struct {
 	baseHandler
 }
 
-func NewQuery(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Query {
+func NewQuery(module string, logger log.Logger, client client.Client, prefixPath string) *Query {
 	return &Query{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -60,6 +59,12 @@ type QuerySuccessResp struct {
 	rtmpPublishServerResponse           *QueryRtmpPublishServerResponse
 }
 
+// queryBody is the body of a successful Query response, the type of its server response depends on the mode.
+type queryBody struct {
+	QuerySuccessResp
+	ServerResponse json.RawMessage `json:"serverResponse"`
+}
+
 type QueryResp struct {
 	Response
 	SuccessResponse QuerySuccessResp
@@ -464,16 +469,39 @@ func (q *QuerySuccessResp) GetServerResponseMode() QueryRespServerResponseMode {
 	return q.serverResponseMode
 }
 
-func (q *QuerySuccessResp) setServerResponse(rawBody []byte, mode string) error {
+// @brief Returns the status of the cloud service in the server response of any mode
+//
+// @return Returns the status, and false if the response carries no server response.
+//
+// @since v0.13.0
+func (q *QuerySuccessResp) GetStatus() (int, bool) {
+	switch q.serverResponseMode {
+	case QueryIndividualRecordingServerResponseMode:
+		return q.individualRecordingServerResponse.Status, true
+	case QueryIndividualVideoScreenshotServerResponseMode:
+		return q.individualVideoScreenshotResponse.Status, true
+	case QueryMixRecordingHlsServerResponseMode:
+		return q.mixRecordingHLSServerResponse.Status, true
+	case QueryMixRecordingHlsAndMp4ServerResponseMode:
+		return q.mixRecordingHLSAndMP4ServerResponse.Status, true
+	case QueryWebRecordingServerResponseMode:
+		return q.webRecordingServerResponse.Status, true
+	case QueryRtmpPublishServerResponseMode:
+		return q.rtmpPublishServerResponse.Status, true
+	default:
+		return 0, false
+	}
+}
+
+func (q *QuerySuccessResp) setServerResponse(serverResponse json.RawMessage, mode string) error {
 	serverResponseMode := QueryServerResponseUnknownMode
 	switch mode {
 	case IndividualMode:
-		fileListMode := gjson.GetBytes(rawBody, "serverResponse.fileListMode")
-		serverResponse := gjson.GetBytes(rawBody, "serverResponse")
+		fileListMode := gjson.GetBytes(serverResponse, "fileListMode")
 		if fileListMode.Exists() && fileListMode.String() == "json" {
 			serverResponseMode = QueryIndividualRecordingServerResponseMode
 			var resp QueryIndividualRecordingServerResponse
-			if err := json.Unmarshal([]byte(serverResponse.String()), &resp); err != nil {
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
 				return err
 			}
 			q.individualRecordingServerResponse = &resp
@@ -481,31 +509,30 @@ func (q *QuerySuccessResp) setServerResponse(rawBody []byte, mode string) error
 		} else {
 			serverResponseMode = QueryIndividualVideoScreenshotServerResponseMode
 			var resp QueryIndividualVideoScreenshotServerResponse
-			if err := json.Unmarshal([]byte(serverResponse.String()), &resp); err != nil {
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
 				return err
 			}
 			q.individualVideoScreenshotResponse = &resp
 		}
 
 	case MixMode:
-		fileListMode := gjson.GetBytes(rawBody, "serverResponse.fileListMode")
+		fileListMode := gjson.GetBytes(serverResponse, "fileListMode")
 		if !fileListMode.Exists() {
 			break
 		}
 
-		serverResponse := gjson.GetBytes(rawBody, "serverResponse")
 		switch fileListMode.String() {
 		case "string":
 			serverResponseMode = QueryMixRecordingHlsServerResponseMode
 			var resp QueryMixRecordingHLSServerResponse
-			if err := json.Unmarshal([]byte(serverResponse.String()), &resp); err != nil {
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
 				return err
 			}
 			q.mixRecordingHLSServerResponse = &resp
 		case "json":
 			serverResponseMode = QueryMixRecordingHlsAndMp4ServerResponseMode
 			var resp QueryMixRecordingHLSAndMP4ServerResponse
-			if err := json.Unmarshal([]byte(serverResponse.String()), &resp); err != nil {
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
 				return err
 			}
 			q.mixRecordingHLSAndMP4ServerResponse = &resp
@@ -514,20 +541,19 @@ func (q *QuerySuccessResp) setServerResponse(rawBody []byte, mode string) error
 		}
 
 	case WebMode:
-		serviceName := gjson.GetBytes(rawBody, "serverResponse.extensionServiceState[*].serviceName")
-		serverResponse := gjson.GetBytes(rawBody, "serverResponse")
+		serviceName := gjson.GetBytes(serverResponse, "extensionServiceState[*].serviceName")
 		switch serviceName.String() {
 		case "rtmp_publish_service":
 			serverResponseMode = QueryRtmpPublishServerResponseMode
 			var resp QueryRtmpPublishServerResponse
-			if err := json.Unmarshal([]byte(serverResponse.String()), &resp); err != nil {
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
 				return err
 			}
 			q.rtmpPublishServerResponse = &resp
 		case "web_recorder_service":
 			serverResponseMode = QueryWebRecordingServerResponseMode
 			var resp QueryWebRecordingServerResponse
-			if err := json.Unmarshal([]byte(serverResponse.String()), &resp); err != nil {
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
 				return err
 			}
 			q.webRecordingServerResponse = &resp
@@ -542,39 +568,32 @@ func (q *QuerySuccessResp) setServerResponse(rawBody []byte, mode string) error
 	return nil
 }
 
-func (q *Query) Do(ctx context.Context, resourceID string, sid string, mode string) (*QueryResp, error) {
+func (q *Query) Do(ctx context.Context, resourceID string, sid string, mode string, opts ...agora.CallOption) (*QueryResp, error) {
 	path := q.buildPath(resourceID, sid, mode)
 
-	responseData, err := doRESTWithRetry(ctx, q.module, q.logger, q.retryCount, q.client, path, http.MethodGet, nil)
+	result, err := agora.Call[agora.NoBody, queryBody, ErrResponse](ctx, q.client, &agora.Request{
+		Module:      q.module,
+		Method:      http.MethodGet,
+		Path:        path,
+		ErrorKind:   errorKind,
+		ShouldRetry: shouldRetry,
+		Idempotent:  true,
+		ErrorFields: errorFields,
+		Options:     opts,
+	}, nil)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
-	var resp QueryResp
 
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse QuerySuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		resp.SuccessResponse = successResponse
-		if err = resp.SuccessResponse.setServerResponse(responseData.RawBody, mode); err != nil {
-			return nil, err
-		}
-	} else {
-		codeResult := gjson.GetBytes(responseData.RawBody, "code")
-		if !codeResult.Exists() {
-			return nil, agora.NewGatewayErr(responseData.HttpStatusCode, string(responseData.RawBody))
-		}
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
+	var resp QueryResp
+	resp.BaseResponse = result.Response
+	resp.SuccessResponse = result.SuccessRes.QuerySuccessResp
+	resp.ErrResponse = result.ErrResponse
+	if result.IsSuccess() {
+		if err = resp.SuccessResponse.setServerResponse(result.SuccessRes.ServerResponse, mode); err != nil {
 			return nil, err
 		}
-		resp.ErrResponse = errResponse
 	}
-	resp.BaseResponse = responseData
 
 	return &resp, nil
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/errcode"
 )
 
 // @brief Error response returned by the cloud recording API.
@@ -16,6 +17,15 @@ type ErrResponse struct {
 	Reason string `json:"reason"`
 }
 
+// @brief Describe the error code, including whether it is retryable and the suggested action
+//
+// @return Returns the details of the error code, see errcode.Info for details
+//
+// @since v0.13.0
+func (e ErrResponse) Describe() errcode.Info {
+	return errcode.Describe(e.ErrorCode)
+}
+
 // @brief Response returned by the cloud recording API.
 //
 // @since v0.8.0
@@ -40,3 +50,29 @@ func (b Response) IsSuccess() bool {
 		return false
 	}
 }
+
+// errorKind returns the sentinel error identified by the cloud recording error code.
+func errorKind(apiErr *agora.APIError) error {
+	switch errcode.Code(apiErr.Code) {
+	case errcode.ResourceNotFound:
+		return agora.ErrNotFound
+	case errcode.ResourceExpired:
+		return agora.ErrResourceExpired
+	default:
+		return nil
+	}
+}
+
+// shouldRetry retries a response with a retryable cloud recording error code, e.g. errcode.NetworkJitter.
+//
+// The retry policy decides based on the HTTP status code for the other codes.
+func shouldRetry(apiErr *agora.APIError) (bool, bool) {
+	info, ok := errcode.Lookup(apiErr.Code)
+	if !ok || !info.Retryable {
+		return false, false
+	}
+	return true, true
+}
+
+// errorFields identifies the error response of the cloud recording service, a response without it is returned by the gateway.
+var errorFields = []string{"code"}
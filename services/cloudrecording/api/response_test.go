@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+)
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        int
+		wantRetry   bool
+		wantDecided bool
+	}{
+		{name: "network jitter", code: 65, wantRetry: true, wantDecided: true},
+		{name: "non-retryable code left to the status policy", code: 2},
+		{name: "resource expired left to the status policy", code: 433},
+		{name: "undocumented code", code: 12345},
+		{name: "no code", code: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, decided := shouldRetry(&agora.APIError{HttpStatusCode: http.StatusBadRequest, Code: tt.code})
+			if retry != tt.wantRetry || decided != tt.wantDecided {
+				t.Errorf("shouldRetry(%d) = %t, %t, want %t, %t", tt.code, retry, decided, tt.wantRetry, tt.wantDecided)
+			}
+		})
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+		want error
+	}{
+		{name: "resource not found", code: 404, want: agora.ErrNotFound},
+		{name: "resource expired", code: 433, want: agora.ErrResourceExpired},
+		{name: "other code", code: 49, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := &agora.APIError{HttpStatusCode: http.StatusBadRequest, Code: tt.code}
+			apiErr.Kind = errorKind(apiErr)
+			if apiErr.Kind != tt.want {
+				t.Errorf("errorKind(%d) = %v, want %v", tt.code, apiErr.Kind, tt.want)
+			}
+			if tt.want != nil && !errors.Is(apiErr, tt.want) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", apiErr, tt.want)
+			}
+		})
+	}
+}
@@ -2,26 +2,23 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
-	"github.com/tidwall/gjson"
-
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/token"
 )
 
 type Start struct {
 	baseHandler
 }
 
-func NewStart(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Start {
+func NewStart(module string, logger log.Logger, client client.Client, prefixPath string) *Start {
 	return &Start{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -55,6 +52,29 @@ type StartClientRequest struct {
 	ExtensionServiceConfig *ExtensionServiceConfig `json:"extensionServiceConfig,omitempty"`
 }
 
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @note Only the constraints shared by every recording mode are checked.
+//
+// @return Returns a ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *StartClientRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	validateRecordingConfig(&errs, "clientRequest.recordingConfig", r.RecordingConfig)
+	validateRecordingFileConfig(&errs, "clientRequest.recordingFileConfig", r.RecordingFileConfig)
+	validateSnapshotConfig(&errs, "clientRequest.snapshotConfig", r.SnapshotConfig)
+	validateStorageConfig(&errs, "clientRequest.storageConfig", r.StorageConfig)
+	validateExtensionServiceConfig(&errs, "clientRequest.extensionServiceConfig", r.ExtensionServiceConfig)
+
+	return errs.Err()
+}
+
 // @brief Configuration for recorded audio and video streams.
 //
 // @since v0.8.0
@@ -574,36 +594,55 @@ type StartSuccessResp struct {
 	Sid string `json:"sid"`
 }
 
-func (s *Start) Do(ctx context.Context, resourceID string, mode string, payload *StartReqBody) (*StartResp, error) {
+// withToken returns a copy of payload carrying a token minted with the App certificate when payload has no token.
+//
+// The payload is returned as it is when the App certificate is not configured.
+func (s *Start) withToken(payload *StartReqBody) (*StartReqBody, error) {
+	appCertificate := s.client.GetAppCertificate()
+	if appCertificate == "" || payload == nil || payload.ClientRequest == nil || payload.ClientRequest.Token != "" {
+		return payload, nil
+	}
+
+	rtcToken, err := token.BuildRtcToken(s.client.GetAppID(), appCertificate, payload.Cname, payload.Uid,
+		token.RoleSubscriber, token.DefaultExpire, token.DefaultExpire)
+	if err != nil {
+		return nil, err
+	}
+	clientRequest := *payload.ClientRequest
+	clientRequest.Token = rtcToken
+	withToken := *payload
+	withToken.ClientRequest = &clientRequest
+
+	return &withToken, nil
+}
+
+func (s *Start) Do(ctx context.Context, resourceID string, mode string, payload *StartReqBody, opts ...agora.CallOption) (*StartResp, error) {
 	path := s.buildPath(resourceID, mode)
 
-	responseData, err := doRESTWithRetry(ctx, s.module, s.logger, s.retryCount, s.client, path, http.MethodPost, payload)
+	payload, err := s.withToken(payload)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
+	}
+
+	result, err := agora.Call[StartReqBody, StartSuccessResp, ErrResponse](ctx, s.client, &agora.Request{
+		Module:      s.module,
+		Method:      http.MethodPost,
+		Path:        path,
+		ErrorKind:   errorKind,
+		ShouldRetry: shouldRetry,
+		// Start begins a billable recording session
+		Idempotent:  false,
+		ErrorFields: errorFields,
+		Options:     opts,
+	}, payload)
+	if err != nil {
+		return nil, err
 	}
 
 	var resp StartResp
+	resp.BaseResponse = result.Response
+	resp.SuccessResponse = result.SuccessRes
+	resp.ErrResponse = result.ErrResponse
 
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResp StartSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResp); err != nil {
-			return nil, err
-		}
-		resp.SuccessResponse = successResp
-	} else {
-		codeResult := gjson.GetBytes(responseData.RawBody, "code")
-		if !codeResult.Exists() {
-			return nil, agora.NewGatewayErr(responseData.HttpStatusCode, string(responseData.RawBody))
-		}
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		resp.ErrResponse = errResponse
-	}
-	resp.BaseResponse = responseData
 	return &resp, nil
 }
@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/tidwall/gjson"
@@ -16,12 +18,11 @@ type Stop struct {
 	baseHandler
 }
 
-func NewStop(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Stop {
+func NewStop(module string, logger log.Logger, client client.Client, prefixPath string) *Stop {
 	return &Stop{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -68,6 +69,8 @@ type StopResp struct {
 
 // @brief Successful response returned by the various of cloud recording scenarios Stop API.
 //
+// @note The server response is only returned by a synchronous stop, read it with the getter of the recording mode.
+//
 // @since v0.8.0
 type StopSuccessResp struct {
 	// Name of the channel to be recorded
@@ -78,38 +81,407 @@ type StopSuccessResp struct {
 	ResourceId string `json:"resourceId"`
 	// Unique identifier of the recording session
 	Sid string `json:"sid"`
+
+	serverResponseMode                  StopRespServerResponseMode
+	individualRecordingServerResponse   *StopIndividualRecordingServerResponse
+	individualVideoScreenshotResponse   *StopIndividualVideoScreenshotServerResponse
+	mixRecordingHLSServerResponse       *StopMixRecordingHLSServerResponse
+	mixRecordingHLSAndMP4ServerResponse *StopMixRecordingHLSAndMP4ServerResponse
+	webRecordingServerResponse          *StopWebRecordingServerResponse
+	rawServerResponse                   json.RawMessage
+}
+
+// stopBody is the body of a successful Stop response, the type of its server response depends on the mode.
+type stopBody struct {
+	StopSuccessResp
+	ServerResponse json.RawMessage `json:"serverResponse"`
 }
 
-func (s *Stop) Do(ctx context.Context, resourceId string, sid string, mode string, payload *StopReqBody) (*StopResp, error) {
+// @brief Server response returned by the individual recording Stop API.
+//
+// @since v0.13.0
+type StopIndividualRecordingServerResponse struct {
+	// The data format of the fileList field:
+	//
+	//  - "string": fileList is of String type. In composite recording mode,
+	//     if avFileType is set to ["hls"], fileListMode is "string".
+	//
+	//  - "json": fileList is a JSON Array. When avFileType is set to ["hls","mp4"]
+	//     in the individual or composite recording mode, fileListMode is set to "json".
+	FileListMode string `json:"fileListMode"`
+
+	// The file list.
+	FileList []struct {
+		// The file names of the M3U8 and MP4 files generated during recording.
+		FileName string `json:"fileName"`
+
+		// The recording file type.
+		//
+		//  - "audio": Audio-only files.
+		//
+		//  - "video": Video-only files.
+		//
+		//  - "audio_and_video": audio and video files
+		TrackType string `json:"trackType"`
+
+		// User UID, indicating which user's audio or video stream is being recorded.
+		//
+		// In composite recording mode, the uid is "0".
+		Uid string `json:"uid"`
+
+		// Whether the users were recorded separately.
+		//
+		//  - true: All users are recorded in a single file.
+		//
+		//  - false: Each user is recorded separately.
+		MixedAllUser bool `json:"mixedAllUser"`
+
+		// Whether or not can be played online.
+		//
+		//  - true: The file can be played online.
+		//
+		//  - false: The file cannot be played online.
+		IsPlayable bool `json:"isPlayable"`
+
+		// The recording start time of the file, the Unix timestamp, in seconds.
+		SliceStartTime int64 `json:"sliceStartTime"`
+	} `json:"fileList"`
+
+	// Upload status of the recorded files:
+	//
+	//  - "uploaded": All the recorded files are uploaded to the third-party cloud storage.
+	//
+	//  - "backuped": Some recorded files failed to be uploaded to the third-party cloud storage, and are uploaded to
+	//     the Agora backup cloud, which uploads them to the third-party cloud storage automatically.
+	//
+	//  - "unknown": Unknown status.
+	UploadingStatus string `json:"uploadingStatus"`
+}
+
+// @brief Server response returned by the individual recording Stop API when only video screenshots are captured.
+//
+// @since v0.13.0
+type StopIndividualVideoScreenshotServerResponse struct {
+	// Upload status of the recorded files:
+	//
+	//  - "uploaded": All the recorded files are uploaded to the third-party cloud storage.
+	//
+	//  - "backuped": Some recorded files failed to be uploaded to the third-party cloud storage, and are uploaded to
+	//     the Agora backup cloud, which uploads them to the third-party cloud storage automatically.
+	//
+	//  - "unknown": Unknown status.
+	UploadingStatus string `json:"uploadingStatus"`
+}
+
+// @brief Server response returned by the mix recording Stop API when the video file format is hls.
+//
+// @since v0.13.0
+type StopMixRecordingHLSServerResponse struct {
+	// The data format of the fileList field:
+	//
+	//  - "string": fileList is of String type. In composite recording mode,
+	//     if avFileType is set to ["hls"], fileListMode is "string".
+	//
+	//  - "json": fileList is a JSON Array. When avFileType is set to ["hls","mp4"]
+	//     in the individual or composite recording mode, fileListMode is set to "json".
+	FileListMode string `json:"fileListMode"`
+
+	// The file name of the M3U8 file generated during recording.
+	FileList string `json:"fileList"`
+
+	// Upload status of the recorded files:
+	//
+	//  - "uploaded": All the recorded files are uploaded to the third-party cloud storage.
+	//
+	//  - "backuped": Some recorded files failed to be uploaded to the third-party cloud storage, and are uploaded to
+	//     the Agora backup cloud, which uploads them to the third-party cloud storage automatically.
+	//
+	//  - "unknown": Unknown status.
+	UploadingStatus string `json:"uploadingStatus"`
+}
+
+// @brief Server response returned by the mix recording Stop API when the video file format is hls and mp4.
+//
+// @since v0.13.0
+type StopMixRecordingHLSAndMP4ServerResponse struct {
+	// The data format of the fileList field:
+	//
+	//  - "string": fileList is of String type. In composite recording mode,
+	//     if avFileType is set to ["hls"], fileListMode is "string".
+	//
+	//  - "json": fileList is a JSON Array. When avFileType is set to ["hls","mp4"]
+	//     in the individual or composite recording mode, fileListMode is set to "json".
+	FileListMode string `json:"fileListMode"`
+
+	// The file list.
+	FileList []struct {
+		// The file names of the M3U8 and MP4 files generated during recording.
+		FileName string `json:"fileName"`
+
+		// The recording file type.
+		//
+		//  - "audio": Audio-only files.
+		//
+		//  - "video": Video-only files.
+		//
+		//  - "audio_and_video": audio and video files
+		TrackType string `json:"trackType"`
+
+		// User UID, indicating which user's audio or video stream is being recorded.
+		//
+		// In composite recording mode, the uid is "0".
+		Uid string `json:"uid"`
+
+		// Whether the users were recorded separately.
+		//
+		//  - true: All users are recorded in a single file.
+		//
+		//  - false: Each user is recorded separately.
+		MixedAllUser bool `json:"mixedAllUser"`
+
+		// Whether or not can be played online.
+		//
+		//  - true: The file can be played online.
+		//
+		//  - false: The file cannot be played online.
+		IsPlayable bool `json:"isPlayable"`
+
+		// The recording start time of the file, the Unix timestamp, in seconds.
+		SliceStartTime int64 `json:"sliceStartTime"`
+	} `json:"fileList"`
+
+	// Upload status of the recorded files:
+	//
+	//  - "uploaded": All the recorded files are uploaded to the third-party cloud storage.
+	//
+	//  - "backuped": Some recorded files failed to be uploaded to the third-party cloud storage, and are uploaded to
+	//     the Agora backup cloud, which uploads them to the third-party cloud storage automatically.
+	//
+	//  - "unknown": Unknown status.
+	UploadingStatus string `json:"uploadingStatus"`
+}
+
+// @brief Server response returned by the web recording Stop API.
+//
+// @since v0.13.0
+type StopWebRecordingServerResponse struct {
+	// The state of the extension services.
+	ExtensionServiceState []struct {
+		// Service payload.
+		Payload struct {
+			// Upload status of the recorded files, only returned by the "upload_service" service:
+			//
+			//  - "uploaded": All the recorded files are uploaded to the third-party cloud storage.
+			//
+			//  - "backuped": Some recorded files failed to be uploaded to the third-party cloud storage, and are uploaded to
+			//     the Agora backup cloud, which uploads them to the third-party cloud storage automatically.
+			//
+			//  - "unknown": Unknown status.
+			UploadingStatus string `json:"uploadingStatus"`
+
+			// The file list, only returned by the "web_recorder_service" service.
+			FileList []struct {
+				// The file names of the M3U8 and MP4 files generated during recording.
+				Filename string `json:"filename"`
+
+				// The recording start time of the file, the Unix timestamp, in seconds.
+				SliceStartTime int64 `json:"sliceStartTime"`
+			} `json:"fileList"`
+
+			// Whether the page recording is paused.
+			//
+			//  - true: The page recording is paused.
+			//
+			//  - false: The page recording is running.
+			Onhold bool `json:"onhold"`
+
+			// The state of the page recorder.
+			//
+			//  - "init": The page recorder is initializing.
+			//
+			//  - "inProgress": The page recorder is recording.
+			//
+			//  - "exit": The page recorder has exited.
+			State string `json:"state"`
+		} `json:"payload"`
+
+		// Service name:
+		//
+		//  - "upload_service": The upload service.
+		//
+		//  - "web_recorder_service": The web page recording service.
+		ServiceName string `json:"serviceName"`
+	} `json:"extensionServiceState"`
+}
+
+// @brief Returns the server response of an individual recording, nil if the response is of another mode
+//
+// @since v0.13.0
+func (s *StopSuccessResp) GetIndividualRecordingServerResponse() *StopIndividualRecordingServerResponse {
+	return s.individualRecordingServerResponse
+}
+
+// @brief Returns the server response of an individual recording that only captures video screenshots,
+// nil if the response is of another mode
+//
+// @since v0.13.0
+func (s *StopSuccessResp) GetIndividualVideoScreenshotServerResponse() *StopIndividualVideoScreenshotServerResponse {
+	return s.individualVideoScreenshotResponse
+}
+
+// @brief Returns the server response of a mix recording in hls format, nil if the response is of another mode
+//
+// @since v0.13.0
+func (s *StopSuccessResp) GetMixRecordingHLSServerResponse() *StopMixRecordingHLSServerResponse {
+	return s.mixRecordingHLSServerResponse
+}
+
+// @brief Returns the server response of a mix recording in hls and mp4 format, nil if the response is of another mode
+//
+// @since v0.13.0
+func (s *StopSuccessResp) GetMixRecordingHLSAndMP4ServerResponse() *StopMixRecordingHLSAndMP4ServerResponse {
+	return s.mixRecordingHLSAndMP4ServerResponse
+}
+
+// @brief Returns the server response of a web recording, nil if the response is of another mode
+//
+// @since v0.13.0
+func (s *StopSuccessResp) GetWebRecordingServerResponse() *StopWebRecordingServerResponse {
+	return s.webRecordingServerResponse
+}
+
+// @brief Returns the mode of the server response, StopServerResponseUnknownMode if the response carries none
+//
+// @note The mode is also StopServerResponseUnknownMode when the server response cannot be decoded, read it with GetRawServerResponse.
+//
+// @since v0.13.0
+func (s *StopSuccessResp) GetServerResponseMode() StopRespServerResponseMode {
+	return s.serverResponseMode
+}
+
+// @brief Returns the server response as returned by the server, nil if the response carries none
+//
+// @since v0.13.0
+func (s *StopSuccessResp) GetRawServerResponse() json.RawMessage {
+	return s.rawServerResponse
+}
+
+// fileListMode returns the format of the file list of a server response, "string" or "json".
+//
+// The format is read from the fileListMode field, or from the type of the fileList field when fileListMode is absent.
+// It is empty when the server response carries no file list.
+func fileListMode(serverResponse json.RawMessage) string {
+	if mode := gjson.GetBytes(serverResponse, "fileListMode"); mode.Exists() {
+		return mode.String()
+	}
+	switch fileList := gjson.GetBytes(serverResponse, "fileList"); {
+	case fileList.Type == gjson.String:
+		return "string"
+	case fileList.IsArray():
+		return "json"
+	default:
+		return ""
+	}
+}
+
+// setServerResponse decodes the server response of the mode.
+//
+// The typed server response and its mode are only set when the server response is decoded,
+// the raw server response is kept in any case.
+func (s *StopSuccessResp) setServerResponse(serverResponse json.RawMessage, mode string) error {
+	// An asynchronous stop returns no server response.
+	if len(serverResponse) == 0 || string(serverResponse) == "null" {
+		return nil
+	}
+	s.rawServerResponse = serverResponse
+
+	var serverResponseMode StopRespServerResponseMode
+	switch mode {
+	case IndividualMode:
+		switch listMode := fileListMode(serverResponse); listMode {
+		case "json":
+			serverResponseMode = StopIndividualRecordingServerResponseMode
+			var resp StopIndividualRecordingServerResponse
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
+				return err
+			}
+			s.individualRecordingServerResponse = &resp
+		case "":
+			// Only video screenshots are captured, no recorded file is listed
+			serverResponseMode = StopIndividualVideoScreenshotServerResponseMode
+			var resp StopIndividualVideoScreenshotServerResponse
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
+				return err
+			}
+			s.individualVideoScreenshotResponse = &resp
+		default:
+			return fmt.Errorf("unknown fileList mode %q", listMode)
+		}
+
+	case MixMode:
+		switch listMode := fileListMode(serverResponse); listMode {
+		case "string":
+			serverResponseMode = StopMixRecordingHlsServerResponseMode
+			var resp StopMixRecordingHLSServerResponse
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
+				return err
+			}
+			s.mixRecordingHLSServerResponse = &resp
+		case "json":
+			serverResponseMode = StopMixRecordingHlsAndMp4ServerResponseMode
+			var resp StopMixRecordingHLSAndMP4ServerResponse
+			if err := json.Unmarshal(serverResponse, &resp); err != nil {
+				return err
+			}
+			s.mixRecordingHLSAndMP4ServerResponse = &resp
+		case "":
+			return errors.New("no fileList in the server response")
+		default:
+			return fmt.Errorf("unknown fileList mode %q", listMode)
+		}
+
+	case WebMode:
+		serverResponseMode = StopWebRecordingServerResponseMode
+		var resp StopWebRecordingServerResponse
+		if err := json.Unmarshal(serverResponse, &resp); err != nil {
+			return err
+		}
+		s.webRecordingServerResponse = &resp
+	default:
+		return fmt.Errorf("unknown mode %q", mode)
+	}
+	s.serverResponseMode = serverResponseMode
+	return nil
+}
+
+func (s *Stop) Do(ctx context.Context, resourceId string, sid string, mode string, payload *StopReqBody, opts ...agora.CallOption) (*StopResp, error) {
 	path := s.buildPath(resourceId, sid, mode)
 
-	responseData, err := doRESTWithRetry(ctx, s.module, s.logger, s.retryCount, s.client, path, http.MethodPost, payload)
+	result, err := agora.Call[StopReqBody, stopBody, ErrResponse](ctx, s.client, &agora.Request{
+		Module:      s.module,
+		Method:      http.MethodPost,
+		Path:        path,
+		ErrorKind:   errorKind,
+		ShouldRetry: shouldRetry,
+		Idempotent:  true,
+		ErrorFields: errorFields,
+		Options:     opts,
+	}, payload)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var resp StopResp
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResp StopSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResp); err != nil {
-			return nil, err
-		}
-		resp.SuccessResponse = successResp
-	} else {
-		codeResult := gjson.GetBytes(responseData.RawBody, "code")
-		if !codeResult.Exists() {
-			return nil, agora.NewGatewayErr(responseData.HttpStatusCode, string(responseData.RawBody))
-		}
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
+	resp.BaseResponse = result.Response
+	resp.SuccessResponse = result.SuccessRes.StopSuccessResp
+	resp.ErrResponse = result.ErrResponse
+	if result.IsSuccess() {
+		// The recording is stopped whatever its server response, so a server response that cannot be decoded
+		// is kept raw instead of failing the call.
+		if err = resp.SuccessResponse.setServerResponse(result.SuccessRes.ServerResponse, mode); err != nil {
+			s.client.GetLogger().Debugf(ctx, s.module, "decode server response failed,err:%s", err)
 		}
-		resp.ErrResponse = errResponse
 	}
-	resp.BaseResponse = responseData
 
 	return &resp, nil
 }
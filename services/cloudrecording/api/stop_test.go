@@ -0,0 +1,237 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Server responses of the samples of the Stop API documentation
+const (
+	individualRecordingStopSample = `{
+  "fileListMode": "json",
+  "fileList": [
+    {
+      "fileName": "xxx.m3u8",
+      "trackType": "audio",
+      "uid": "123",
+      "mixedAllUser": false,
+      "isPlayable": true,
+      "sliceStartTime": 1562724971626
+    },
+    {
+      "fileName": "xxx.m3u8",
+      "trackType": "video",
+      "uid": "456",
+      "mixedAllUser": false,
+      "isPlayable": true,
+      "sliceStartTime": 1562724971626
+    }
+  ],
+  "uploadingStatus": "uploaded"
+}`
+	individualVideoScreenshotStopSample = `{"uploadingStatus": "uploaded"}`
+	mixRecordingHLSStopSample           = `{
+  "fileListMode": "string",
+  "fileList": "xxx.m3u8",
+  "uploadingStatus": "uploaded"
+}`
+	mixRecordingHLSAndMP4StopSample = `{
+  "fileListMode": "json",
+  "fileList": [
+    {
+      "fileName": "xxx.m3u8",
+      "trackType": "audio_and_video",
+      "uid": "0",
+      "mixedAllUser": true,
+      "isPlayable": true,
+      "sliceStartTime": 1562724971626
+    },
+    {
+      "fileName": "xxx.mp4",
+      "trackType": "audio_and_video",
+      "uid": "0",
+      "mixedAllUser": true,
+      "isPlayable": true,
+      "sliceStartTime": 1562724971626
+    }
+  ],
+  "uploadingStatus": "uploaded"
+}`
+	webRecordingStopSample = `{
+  "extensionServiceState": [
+    {
+      "payload": {
+        "uploadingStatus": "uploaded"
+      },
+      "serviceName": "upload_service"
+    },
+    {
+      "payload": {
+        "fileList": [
+          {
+            "filename": "xxx.m3u8",
+            "sliceStartTime": 1623037245000
+          },
+          {
+            "filename": "xxx.mp4",
+            "sliceStartTime": 1623037245000
+          }
+        ],
+        "onhold": false,
+        "state": "exit"
+      },
+      "serviceName": "web_recorder_service"
+    }
+  ]
+}`
+)
+
+func TestStopSetServerResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		serverResponse string
+		wantMode       StopRespServerResponseMode
+		check          func(t *testing.T, resp *StopSuccessResp)
+	}{
+		{
+			name:           "individual recording",
+			mode:           IndividualMode,
+			serverResponse: individualRecordingStopSample,
+			wantMode:       StopIndividualRecordingServerResponseMode,
+			check: func(t *testing.T, resp *StopSuccessResp) {
+				serverResponse := resp.GetIndividualRecordingServerResponse()
+				if len(serverResponse.FileList) != 2 || serverResponse.FileList[1].Uid != "456" || serverResponse.FileList[1].TrackType != "video" ||
+					serverResponse.UploadingStatus != "uploaded" {
+					t.Errorf("server response = %+v, want the documented sample", serverResponse)
+				}
+			},
+		},
+		{
+			name:           "individual video screenshot",
+			mode:           IndividualMode,
+			serverResponse: individualVideoScreenshotStopSample,
+			wantMode:       StopIndividualVideoScreenshotServerResponseMode,
+			check: func(t *testing.T, resp *StopSuccessResp) {
+				if serverResponse := resp.GetIndividualVideoScreenshotServerResponse(); serverResponse.UploadingStatus != "uploaded" {
+					t.Errorf("server response = %+v, want the documented sample", serverResponse)
+				}
+			},
+		},
+		{
+			name:           "mix recording in hls",
+			mode:           MixMode,
+			serverResponse: mixRecordingHLSStopSample,
+			wantMode:       StopMixRecordingHlsServerResponseMode,
+			check: func(t *testing.T, resp *StopSuccessResp) {
+				if serverResponse := resp.GetMixRecordingHLSServerResponse(); serverResponse.FileList != "xxx.m3u8" {
+					t.Errorf("server response = %+v, want the documented sample", serverResponse)
+				}
+			},
+		},
+		{
+			name:           "mix recording in hls and mp4",
+			mode:           MixMode,
+			serverResponse: mixRecordingHLSAndMP4StopSample,
+			wantMode:       StopMixRecordingHlsAndMp4ServerResponseMode,
+			check: func(t *testing.T, resp *StopSuccessResp) {
+				serverResponse := resp.GetMixRecordingHLSAndMP4ServerResponse()
+				if len(serverResponse.FileList) != 2 || serverResponse.FileList[1].FileName != "xxx.mp4" || !serverResponse.FileList[1].MixedAllUser {
+					t.Errorf("server response = %+v, want the documented sample", serverResponse)
+				}
+			},
+		},
+		{
+			name:           "web recording",
+			mode:           WebMode,
+			serverResponse: webRecordingStopSample,
+			wantMode:       StopWebRecordingServerResponseMode,
+			check: func(t *testing.T, resp *StopSuccessResp) {
+				serverResponse := resp.GetWebRecordingServerResponse()
+				if len(serverResponse.ExtensionServiceState) != 2 || serverResponse.ExtensionServiceState[0].Payload.UploadingStatus != "uploaded" ||
+					len(serverResponse.ExtensionServiceState[1].Payload.FileList) != 2 || serverResponse.ExtensionServiceState[1].Payload.State != "exit" {
+					t.Errorf("server response = %+v, want the documented sample", serverResponse)
+				}
+			},
+		},
+		{
+			name:           "mix recording in hls without fileListMode",
+			mode:           MixMode,
+			serverResponse: `{"fileList":"xxx.m3u8","uploadingStatus":"uploaded"}`,
+			wantMode:       StopMixRecordingHlsServerResponseMode,
+		},
+		{
+			name:           "mix recording in hls and mp4 without fileListMode",
+			mode:           MixMode,
+			serverResponse: `{"fileList":[{"fileName":"xxx.mp4"}],"uploadingStatus":"uploaded"}`,
+			wantMode:       StopMixRecordingHlsAndMp4ServerResponseMode,
+		},
+		{
+			name:           "individual recording without fileListMode",
+			mode:           IndividualMode,
+			serverResponse: `{"fileList":[{"fileName":"xxx.m3u8"}],"uploadingStatus":"uploaded"}`,
+			wantMode:       StopIndividualRecordingServerResponseMode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp StopSuccessResp
+			if err := resp.setServerResponse(json.RawMessage(tt.serverResponse), tt.mode); err != nil {
+				t.Fatalf("setServerResponse() error = %v", err)
+			}
+			if resp.GetServerResponseMode() != tt.wantMode {
+				t.Errorf("GetServerResponseMode() = %d, want %d", resp.GetServerResponseMode(), tt.wantMode)
+			}
+			if string(resp.GetRawServerResponse()) != tt.serverResponse {
+				t.Errorf("GetRawServerResponse() = %s, want the server response", resp.GetRawServerResponse())
+			}
+			if tt.check != nil {
+				tt.check(t, &resp)
+			}
+		})
+	}
+}
+
+func TestStopSetServerResponseFailure(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		serverResponse string
+	}{
+		{name: "undecodable", mode: IndividualMode, serverResponse: `{"fileListMode":"json","fileList":"xxx.m3u8"}`},
+		{name: "unknown individual fileListMode", mode: IndividualMode, serverResponse: `{"fileListMode":"string","fileList":"xxx.m3u8"}`},
+		{name: "mix recording without fileList", mode: MixMode, serverResponse: `{"uploadingStatus":"uploaded"}`},
+		{name: "unknown mix fileListMode", mode: MixMode, serverResponse: `{"fileListMode":"xml"}`},
+		{name: "unknown mode", mode: "unknown", serverResponse: individualRecordingStopSample},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp StopSuccessResp
+			if err := resp.setServerResponse(json.RawMessage(tt.serverResponse), tt.mode); err == nil {
+				t.Fatal("setServerResponse() error = nil, want an error")
+			}
+			if resp.GetServerResponseMode() != StopServerResponseUnknownMode {
+				t.Errorf("GetServerResponseMode() = %d, want StopServerResponseUnknownMode", resp.GetServerResponseMode())
+			}
+			if string(resp.GetRawServerResponse()) != tt.serverResponse {
+				t.Errorf("GetRawServerResponse() = %s, want the server response", resp.GetRawServerResponse())
+			}
+			if resp.GetIndividualRecordingServerResponse() != nil || resp.GetMixRecordingHLSServerResponse() != nil {
+				t.Error("a typed server response is set")
+			}
+		})
+	}
+}
+
+func TestStopSetServerResponseAsync(t *testing.T) {
+	for _, serverResponse := range []string{"", "null"} {
+		var resp StopSuccessResp
+		if err := resp.setServerResponse(json.RawMessage(serverResponse), IndividualMode); err != nil {
+			t.Fatalf("setServerResponse(%q) error = %v", serverResponse, err)
+		}
+		if resp.GetServerResponseMode() != StopServerResponseUnknownMode || resp.GetRawServerResponse() != nil {
+			t.Errorf("setServerResponse(%q) set mode %d and raw server response %s, want none", serverResponse,
+				resp.GetServerResponseMode(), resp.GetRawServerResponse())
+		}
+	}
+}
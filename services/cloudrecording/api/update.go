@@ -4,9 +4,6 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/pkg/errors"
-	"github.com/tidwall/gjson"
-
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
@@ -16,12 +13,11 @@ type Update struct {
 	baseHandler
 }
 
-func NewUpdate(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Update {
+func NewUpdate(module string, logger log.Logger, client client.Client, prefixPath string) *Update {
 	return &Update{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -46,6 +42,32 @@ type UpdateClientRequest struct {
 	RtmpPublishConfig  *UpdateRtmpPublishConfig  `json:"rtmpPublishConfig,omitempty"`
 }
 
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns a ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *UpdateClientRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	if r.StreamSubscribe == nil && r.WebRecordingConfig == nil && r.RtmpPublishConfig == nil {
+		errs.Add("clientRequest", "one of streamSubscribe, webRecordingConfig or rtmpPublishConfig is required")
+	}
+	if r.RtmpPublishConfig != nil {
+		for i, output := range r.RtmpPublishConfig.Outputs {
+			if output.RtmpURL == "" {
+				errs.Add(indexField("clientRequest.rtmpPublishConfig.outputs", i)+".rtmpUrl", "is required")
+			}
+		}
+	}
+
+	return errs.Err()
+}
+
 // @brief Update subscription lists.
 //
 // @since v0.8.0
@@ -138,35 +160,27 @@ type UpdateSuccessResp struct {
 	Cname string `json:"cname"`
 }
 
-func (u *Update) Do(ctx context.Context, resourceID string, sid string, mode string, payload *UpdateReqBody) (*UpdateResp, error) {
+func (u *Update) Do(ctx context.Context, resourceID string, sid string, mode string, payload *UpdateReqBody, opts ...agora.CallOption) (*UpdateResp, error) {
 	path := u.buildPath(resourceID, sid, mode)
 
-	responseData, err := doRESTWithRetry(ctx, u.module, u.logger, u.retryCount, u.client, path, http.MethodPost, payload)
+	result, err := agora.Call[UpdateReqBody, UpdateSuccessResp, ErrResponse](ctx, u.client, &agora.Request{
+		Module:      u.module,
+		Method:      http.MethodPost,
+		Path:        path,
+		ErrorKind:   errorKind,
+		ShouldRetry: shouldRetry,
+		Idempotent:  true,
+		ErrorFields: errorFields,
+		Options:     opts,
+	}, payload)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
+
 	var resp UpdateResp
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse UpdateSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		resp.SuccessResponse = successResponse
-	} else {
-		codeResult := gjson.GetBytes(responseData.RawBody, "code")
-		if !codeResult.Exists() {
-			return nil, agora.NewGatewayErr(responseData.HttpStatusCode, string(responseData.RawBody))
-		}
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		resp.ErrResponse = errResponse
-	}
-	resp.BaseResponse = responseData
+	resp.BaseResponse = result.Response
+	resp.SuccessResponse = result.SuccessRes
+	resp.ErrResponse = result.ErrResponse
 
 	return &resp, nil
 }
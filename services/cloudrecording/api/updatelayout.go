@@ -2,11 +2,8 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
-	"github.com/tidwall/gjson"
-
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
@@ -16,12 +13,11 @@ type UpdateLayout struct {
 	baseHandler
 }
 
-func NewUpdateLayout(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *UpdateLayout {
+func NewUpdateLayout(module string, logger log.Logger, client client.Client, prefixPath string) *UpdateLayout {
 	return &UpdateLayout{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -50,6 +46,28 @@ type UpdateLayoutClientRequest struct {
 	BackgroundConfig           []BackgroundConfig   `json:"backgroundConfig,omitempty"`
 }
 
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns a ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *UpdateLayoutClientRequest) Validate() error {
+	var errs ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	validateMixedVideoLayout(&errs, "clientRequest.mixedVideoLayout", r.MixedVideoLayout)
+	for i, layout := range r.LayoutConfig {
+		validateLayout(&errs, indexField("clientRequest.layoutConfig", i),
+			layout.XAxis, layout.YAxis, layout.Width, layout.Height, layout.Alpha, layout.RenderMode)
+	}
+	validateBackgroundConfig(&errs, "clientRequest.backgroundConfig", r.BackgroundConfig)
+
+	return errs.Err()
+}
+
 // @brief The layout configuration.
 //
 // @since v0.8.0
@@ -172,36 +190,27 @@ type UpdateLayoutResp struct {
 	SuccessResponse UpdateLayoutSuccessResp
 }
 
-func (u *UpdateLayout) Do(ctx context.Context, resourceID string, sid string, mode string, payload *UpdateLayoutReqBody) (*UpdateLayoutResp, error) {
+func (u *UpdateLayout) Do(ctx context.Context, resourceID string, sid string, mode string, payload *UpdateLayoutReqBody, opts ...agora.CallOption) (*UpdateLayoutResp, error) {
 	path := u.buildPath(resourceID, sid, mode)
 
-	responseData, err := doRESTWithRetry(ctx, u.module, u.logger, u.retryCount, u.client, path, http.MethodPost, payload)
+	result, err := agora.Call[UpdateLayoutReqBody, UpdateLayoutSuccessResp, ErrResponse](ctx, u.client, &agora.Request{
+		Module:      u.module,
+		Method:      http.MethodPost,
+		Path:        path,
+		ErrorKind:   errorKind,
+		ShouldRetry: shouldRetry,
+		Idempotent:  true,
+		ErrorFields: errorFields,
+		Options:     opts,
+	}, payload)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var resp UpdateLayoutResp
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse UpdateLayoutSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		resp.SuccessResponse = successResponse
-	} else {
-		codeResult := gjson.GetBytes(responseData.RawBody, "code")
-		if !codeResult.Exists() {
-			return nil, agora.NewGatewayErr(responseData.HttpStatusCode, string(responseData.RawBody))
-		}
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		resp.ErrResponse = errResponse
-	}
+	resp.BaseResponse = result.Response
+	resp.SuccessResponse = result.SuccessRes
+	resp.ErrResponse = result.ErrResponse
 
-	resp.BaseResponse = responseData
 	return &resp, nil
 }
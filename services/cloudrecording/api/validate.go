@@ -0,0 +1,306 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRequest is matched by ValidationErrors with errors.Is.
+//
+// @since v0.13.0
+var ErrInvalidRequest = errors.New("cloud recording: invalid request")
+
+// @brief FieldError describes an invalid field of a request
+//
+// @since v0.13.0
+type FieldError struct {
+	// Path of the field, made of the JSON names of the fields from the request body, e.g. "clientRequest.snapshotConfig.fileType"
+	Field string
+	// Reason why the field is invalid
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// @brief ValidationErrors is returned when a request is invalid, before it is sent
+//
+// @note Use errors.As to read the invalid fields, and errors.Is with ErrInvalidRequest to check for it.
+//
+// @since v0.13.0
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Error())
+	}
+	return "cloud recording: invalid request: " + strings.Join(messages, "; ")
+}
+
+// Is reports whether target is ErrInvalidRequest.
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// @brief Adds an invalid field
+//
+// @param field Path of the field. See FieldError.Field for details.
+//
+// @param message Reason why the field is invalid.
+//
+// @since v0.13.0
+func (e *ValidationErrors) Add(field string, message string) {
+	*e = append(*e, &FieldError{Field: field, Message: message})
+}
+
+// @brief Returns the errors as an error, nil if no field is invalid
+//
+// @since v0.13.0
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// maxVideoPixels is the maximum product of the width and the height of an output video, i.e. 1920 × 1080.
+const maxVideoPixels = 1920 * 1080
+
+func validateRecordingConfig(errs *ValidationErrors, field string, config *RecordingConfig) {
+	if config == nil {
+		return
+	}
+	if config.ChannelType != 0 && config.ChannelType != 1 {
+		errs.Add(field+".channelType", "must be 0 or 1")
+	}
+	if config.StreamTypes < 0 || config.StreamTypes > 2 {
+		errs.Add(field+".streamTypes", "must be in [0,2]")
+	}
+	switch config.StreamMode {
+	case "", "default", "standard", "original":
+	default:
+		errs.Add(field+".streamMode", `must be "default", "standard" or "original"`)
+	}
+	if config.StreamMode == "original" && config.StreamTypes != 0 {
+		errs.Add(field+".streamMode", `must not be "original" unless streamTypes is 0`)
+	}
+	if config.DecryptionMode < 0 || config.DecryptionMode > 8 {
+		errs.Add(field+".decryptionMode", "must be in [0,8]")
+	}
+	if config.DecryptionMode != 0 && config.Secret == "" {
+		errs.Add(field+".secret", "is required when decryptionMode is not 0")
+	}
+	if (config.DecryptionMode == 7 || config.DecryptionMode == 8) && config.Salt == "" {
+		errs.Add(field+".salt", "is required when decryptionMode is 7 or 8")
+	}
+	if config.AudioProfile < 0 || config.AudioProfile > 2 {
+		errs.Add(field+".audioProfile", "must be in [0,2]")
+	}
+	if config.VideoStreamType != 0 && config.VideoStreamType != 1 {
+		errs.Add(field+".videoStreamType", "must be 0 or 1")
+	}
+	if config.MaxIdleTime != 0 && (config.MaxIdleTime < 5 || config.MaxIdleTime > 259200) {
+		errs.Add(field+".maxIdleTime", "must be in [5,259200]")
+	}
+	if config.SubscribeUidGroup < 0 || config.SubscribeUidGroup > 5 {
+		errs.Add(field+".subscribeUidGroup", "must be in [0,5]")
+	}
+	validateTranscodingConfig(errs, field+".transcodingConfig", config.TranscodingConfig)
+}
+
+func validateTranscodingConfig(errs *ValidationErrors, field string, config *TranscodingConfig) {
+	if config == nil {
+		return
+	}
+	if config.Width < 0 {
+		errs.Add(field+".width", "must not be negative")
+	}
+	if config.Height < 0 {
+		errs.Add(field+".height", "must not be negative")
+	}
+	if config.Width*config.Height > maxVideoPixels {
+		errs.Add(field, "width × height must not exceed 1920 × 1080")
+	}
+	if config.FPS < 0 {
+		errs.Add(field+".fps", "must not be negative")
+	}
+	if config.BitRate < 0 {
+		errs.Add(field+".bitrate", "must not be negative")
+	}
+	validateMixedVideoLayout(errs, field+".mixedVideoLayout", config.MixedVideoLayout)
+	for i, layout := range config.LayoutConfig {
+		validateLayout(errs, indexField(field+".layoutConfig", i),
+			layout.XAxis, layout.YAxis, layout.Width, layout.Height, layout.Alpha, layout.RenderMode)
+	}
+	validateBackgroundConfig(errs, field+".backgroundConfig", config.BackgroundConfig)
+}
+
+func validateMixedVideoLayout(errs *ValidationErrors, field string, mixedVideoLayout int) {
+	if mixedVideoLayout < 0 || mixedVideoLayout > 3 {
+		errs.Add(field, "must be in [0,3]")
+	}
+}
+
+func validateLayout(errs *ValidationErrors, field string, xAxis, yAxis, width, height, alpha float32, renderMode int) {
+	validateRatio(errs, field+".x_axis", xAxis)
+	validateRatio(errs, field+".y_axis", yAxis)
+	validateRatio(errs, field+".width", width)
+	validateRatio(errs, field+".height", height)
+	validateRatio(errs, field+".alpha", alpha)
+	validateRenderMode(errs, field+".render_mode", renderMode)
+}
+
+func validateBackgroundConfig(errs *ValidationErrors, field string, configs []BackgroundConfig) {
+	for i, config := range configs {
+		itemField := indexField(field, i)
+		if config.UID == "" {
+			errs.Add(itemField+".uid", "is required")
+		}
+		if config.ImageURL == "" {
+			errs.Add(itemField+".image_url", "is required")
+		}
+		validateRenderMode(errs, itemField+".render_mode", config.RenderMode)
+	}
+}
+
+func validateRatio(errs *ValidationErrors, field string, value float32) {
+	if value < 0 || value > 1 {
+		errs.Add(field, "must be in [0,1]")
+	}
+}
+
+func validateRenderMode(errs *ValidationErrors, field string, renderMode int) {
+	if renderMode != 0 && renderMode != 1 {
+		errs.Add(field, "must be 0 or 1")
+	}
+}
+
+func validateRecordingFileConfig(errs *ValidationErrors, field string, config *RecordingFileConfig) {
+	if config == nil {
+		return
+	}
+	for i, fileType := range config.AvFileType {
+		if fileType != "hls" && fileType != "mp4" {
+			errs.Add(indexField(field+".avFileType", i), `must be "hls" or "mp4"`)
+		}
+	}
+}
+
+func validateSnapshotConfig(errs *ValidationErrors, field string, config *SnapshotConfig) {
+	if config == nil {
+		return
+	}
+	if config.CaptureInterval != 0 && (config.CaptureInterval < 5 || config.CaptureInterval > 3600) {
+		errs.Add(field+".captureInterval", "must be in [5,3600]")
+	}
+	if len(config.FileType) != 1 || config.FileType[0] != "jpg" {
+		errs.Add(field+".fileType", `must be ["jpg"]`)
+	}
+}
+
+func validateStorageConfig(errs *ValidationErrors, field string, config *StorageConfig) {
+	if config == nil {
+		return
+	}
+	switch config.Vendor {
+	case 1, 2, 3, 5, 6, 7, 8, 11:
+	default:
+		errs.Add(field+".vendor", "must be 1, 2, 3, 5, 6, 7, 8 or 11")
+	}
+	if config.Bucket == "" {
+		errs.Add(field+".bucket", "is required")
+	}
+	if config.AccessKey == "" {
+		errs.Add(field+".accessKey", "is required")
+	}
+	if config.SecretKey == "" {
+		errs.Add(field+".secretKey", "is required")
+	}
+	if config.Vendor == 11 && (config.ExtensionParams == nil || config.ExtensionParams.Endpoint == "") {
+		errs.Add(field+".extensionParams.endpoint", "is required when vendor is 11")
+	}
+}
+
+func validateExtensionServiceConfig(errs *ValidationErrors, field string, config *ExtensionServiceConfig) {
+	if config == nil {
+		return
+	}
+	if len(config.ExtensionServices) == 0 {
+		errs.Add(field+".extensionServices", "is required")
+	}
+	for i, service := range config.ExtensionServices {
+		serviceField := indexField(field+".extensionServices", i)
+		switch service.ServiceName {
+		case "web_recorder_service", "rtmp_publish_service":
+		default:
+			errs.Add(serviceField+".serviceName", `must be "web_recorder_service" or "rtmp_publish_service"`)
+		}
+
+		paramField := serviceField + ".serviceParam"
+		switch param := service.ServiceParam.(type) {
+		case *WebRecordingServiceParam:
+			validateWebRecordingServiceParam(errs, paramField, param)
+		case *RtmpPublishServiceParam:
+			validateRtmpPublishServiceParam(errs, paramField, param)
+		}
+		if service.ServiceParam == nil {
+			errs.Add(paramField, "is required")
+		}
+	}
+}
+
+func validateWebRecordingServiceParam(errs *ValidationErrors, field string, param *WebRecordingServiceParam) {
+	if param == nil {
+		errs.Add(field, "is required")
+		return
+	}
+	if param.URL == "" {
+		errs.Add(field+".url", "is required")
+	}
+	if param.VideoFPS != 0 && (param.VideoFPS < 5 || param.VideoFPS > 60) {
+		errs.Add(field+".videoFps", "must be in [5,60]")
+	}
+	if param.AudioProfile < 0 || param.AudioProfile > 2 {
+		errs.Add(field+".audioProfile", "must be in [0,2]")
+	}
+	if param.VideoWidth <= 0 {
+		errs.Add(field+".videoWidth", "must be positive")
+	}
+	if param.VideoHeight <= 0 {
+		errs.Add(field+".videoHeight", "must be positive")
+	}
+	if param.VideoWidth*param.VideoHeight > maxVideoPixels {
+		errs.Add(field, "videoWidth × videoHeight must not exceed 1920 × 1080")
+	}
+	if param.MaxRecordingHour < 1 || param.MaxRecordingHour > 720 {
+		errs.Add(field+".maxRecordingHour", "must be in [1,720]")
+	}
+	if param.MaxVideoDuration != 0 && (param.MaxVideoDuration < 30 || param.MaxVideoDuration > 240) {
+		errs.Add(field+".maxVideoDuration", "must be in [30,240]")
+	}
+	if param.ReadyTimeout < 0 || param.ReadyTimeout > 60 {
+		errs.Add(field+".readyTimeout", "must be in [0,60]")
+	}
+}
+
+func validateRtmpPublishServiceParam(errs *ValidationErrors, field string, param *RtmpPublishServiceParam) {
+	if param == nil {
+		errs.Add(field, "is required")
+		return
+	}
+	if len(param.Outputs) == 0 {
+		errs.Add(field+".outputs", "is required")
+	}
+	for i, output := range param.Outputs {
+		if output.RtmpURL == "" {
+			errs.Add(indexField(field+".outputs", i)+".rtmpUrl", "is required")
+		}
+	}
+}
+
+func indexField(field string, index int) string {
+	return field + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,693 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// invalidFields returns the paths of the invalid fields reported by err.
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want a ValidationErrors", err)
+	}
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("errors.Is(%v, ErrInvalidRequest) = false, want true", err)
+	}
+	fields := make([]string, 0, len(errs))
+	for _, fieldErr := range errs {
+		fields = append(fields, fieldErr.Field)
+	}
+	return fields
+}
+
+// validStartClientRequest returns a request that passes validation, to be modified by each test case.
+func validStartClientRequest() *StartClientRequest {
+	return &StartClientRequest{
+		RecordingConfig: &RecordingConfig{
+			ChannelType: 1,
+			StreamTypes: 2,
+		},
+		StorageConfig: &StorageConfig{
+			Vendor:    2,
+			Region:    3,
+			Bucket:    "bucket",
+			AccessKey: "accessKey",
+			SecretKey: "secretKey",
+		},
+	}
+}
+
+func validWebRecordingServiceParam() *WebRecordingServiceParam {
+	return &WebRecordingServiceParam{
+		URL:              "https://example.com",
+		VideoWidth:       1280,
+		VideoHeight:      720,
+		MaxRecordingHour: 1,
+	}
+}
+
+func webRecordingRequest(modify func(param *WebRecordingServiceParam)) *StartClientRequest {
+	param := validWebRecordingServiceParam()
+	modify(param)
+	r := validStartClientRequest()
+	r.RecordingConfig = nil
+	r.ExtensionServiceConfig = &ExtensionServiceConfig{
+		ExtensionServices: []ExtensionService{
+			{ServiceName: "web_recorder_service", ServiceParam: param},
+		},
+	}
+	return r
+}
+
+func TestStartClientRequestValidate(t *testing.T) {
+	const (
+		recordingConfig = "clientRequest.recordingConfig"
+		transcoding     = recordingConfig + ".transcodingConfig"
+		snapshotConfig  = "clientRequest.snapshotConfig"
+		storageConfig   = "clientRequest.storageConfig"
+		webParam        = "clientRequest.extensionServiceConfig.extensionServices[0].serviceParam"
+	)
+
+	tests := []struct {
+		name       string
+		request    func() *StartClientRequest
+		wantFields []string
+	}{
+		{
+			name:    "nil request",
+			request: func() *StartClientRequest { return nil },
+			wantFields: []string{
+				"clientRequest",
+			},
+		},
+		{
+			name:    "valid request",
+			request: validStartClientRequest,
+		},
+		{
+			name: "invalid enums",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig = &RecordingConfig{
+					ChannelType:       2,
+					StreamTypes:       3,
+					StreamMode:        "mixed",
+					AudioProfile:      3,
+					VideoStreamType:   2,
+					SubscribeUidGroup: 6,
+				}
+				return r
+			},
+			wantFields: []string{
+				recordingConfig + ".channelType",
+				recordingConfig + ".streamTypes",
+				recordingConfig + ".streamMode",
+				recordingConfig + ".audioProfile",
+				recordingConfig + ".videoStreamType",
+				recordingConfig + ".subscribeUidGroup",
+			},
+		},
+		{
+			name: "original stream mode of audio streams",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.StreamTypes = 0
+				r.RecordingConfig.StreamMode = "original"
+				return r
+			},
+		},
+		{
+			name: "original stream mode of video streams",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.StreamMode = "original"
+				return r
+			},
+			wantFields: []string{recordingConfig + ".streamMode"},
+		},
+		{
+			name: "decryption mode out of range",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.DecryptionMode = 9
+				r.RecordingConfig.Secret = "secret"
+				return r
+			},
+			wantFields: []string{recordingConfig + ".decryptionMode"},
+		},
+		{
+			name: "decryption mode without secret",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.DecryptionMode = 1
+				return r
+			},
+			wantFields: []string{recordingConfig + ".secret"},
+		},
+		{
+			name: "decryption mode 6 without salt",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.DecryptionMode = 6
+				r.RecordingConfig.Secret = "secret"
+				return r
+			},
+		},
+		{
+			name: "decryption mode 7 without salt",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.DecryptionMode = 7
+				r.RecordingConfig.Secret = "secret"
+				return r
+			},
+			wantFields: []string{recordingConfig + ".salt"},
+		},
+		{
+			name: "decryption mode 8 without salt",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.DecryptionMode = 8
+				r.RecordingConfig.Secret = "secret"
+				return r
+			},
+			wantFields: []string{recordingConfig + ".salt"},
+		},
+		{
+			name: "decryption mode 8 with salt",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.DecryptionMode = 8
+				r.RecordingConfig.Secret = "secret"
+				r.RecordingConfig.Salt = "salt"
+				return r
+			},
+		},
+		{
+			name: "transcoding at 1920 × 1080",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.TranscodingConfig = &TranscodingConfig{Width: 1920, Height: 1080}
+				return r
+			},
+		},
+		{
+			name: "transcoding at 1080 × 1920",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.TranscodingConfig = &TranscodingConfig{Width: 1080, Height: 1920}
+				return r
+			},
+		},
+		{
+			name: "transcoding above 1920 × 1080",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.TranscodingConfig = &TranscodingConfig{Width: 1920, Height: 1081}
+				return r
+			},
+			wantFields: []string{transcoding},
+		},
+		{
+			name: "negative transcoding values",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.TranscodingConfig = &TranscodingConfig{Width: -1, Height: -1, FPS: -1, BitRate: -1, MixedVideoLayout: 4}
+				return r
+			},
+			wantFields: []string{
+				transcoding + ".width",
+				transcoding + ".height",
+				transcoding + ".fps",
+				transcoding + ".bitrate",
+				transcoding + ".mixedVideoLayout",
+			},
+		},
+		{
+			name: "transcoding layouts and backgrounds",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.TranscodingConfig = &TranscodingConfig{
+					MixedVideoLayout: 3,
+					LayoutConfig: []LayoutConfig{
+						{XAxis: 0, YAxis: 1, Width: 1, Height: 1, Alpha: 1},
+						{XAxis: 1.1, YAxis: -0.1, Width: 1, Height: 1, Alpha: 1, RenderMode: 2},
+					},
+					BackgroundConfig: []BackgroundConfig{
+						{UID: "1", ImageURL: "https://example.com/1.jpg"},
+						{RenderMode: 2},
+					},
+				}
+				return r
+			},
+			wantFields: []string{
+				transcoding + ".layoutConfig[1].x_axis",
+				transcoding + ".layoutConfig[1].y_axis",
+				transcoding + ".layoutConfig[1].render_mode",
+				transcoding + ".backgroundConfig[1].uid",
+				transcoding + ".backgroundConfig[1].image_url",
+				transcoding + ".backgroundConfig[1].render_mode",
+			},
+		},
+		{
+			name: "file types",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingFileConfig = &RecordingFileConfig{AvFileType: []string{"hls", "mp4", "flv"}}
+				return r
+			},
+			wantFields: []string{"clientRequest.recordingFileConfig.avFileType[2]"},
+		},
+		{
+			name: "snapshot file type",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.SnapshotConfig = &SnapshotConfig{FileType: []string{"png"}}
+				return r
+			},
+			wantFields: []string{snapshotConfig + ".fileType"},
+		},
+		{
+			name: "snapshot without file type",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.SnapshotConfig = &SnapshotConfig{}
+				return r
+			},
+			wantFields: []string{snapshotConfig + ".fileType"},
+		},
+		{
+			name: "storage required fields",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.StorageConfig = &StorageConfig{Vendor: 4}
+				return r
+			},
+			wantFields: []string{
+				storageConfig + ".vendor",
+				storageConfig + ".bucket",
+				storageConfig + ".accessKey",
+				storageConfig + ".secretKey",
+			},
+		},
+		{
+			name: "vendor 11 without extension params",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.StorageConfig.Vendor = 11
+				return r
+			},
+			wantFields: []string{storageConfig + ".extensionParams.endpoint"},
+		},
+		{
+			name: "vendor 11 without endpoint",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.StorageConfig.Vendor = 11
+				r.StorageConfig.ExtensionParams = &ExtensionParams{Tag: "tag"}
+				return r
+			},
+			wantFields: []string{storageConfig + ".extensionParams.endpoint"},
+		},
+		{
+			name: "vendor 11 with endpoint",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.StorageConfig.Vendor = 11
+				r.StorageConfig.ExtensionParams = &ExtensionParams{Endpoint: "https://s3.example.com"}
+				return r
+			},
+		},
+		{
+			name: "extension services required",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.ExtensionServiceConfig = &ExtensionServiceConfig{}
+				return r
+			},
+			wantFields: []string{"clientRequest.extensionServiceConfig.extensionServices"},
+		},
+		{
+			name: "extension service name and param",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.ExtensionServiceConfig = &ExtensionServiceConfig{
+					ExtensionServices: []ExtensionService{
+						{ServiceName: "snapshot_service"},
+						{ServiceName: "web_recorder_service", ServiceParam: (*WebRecordingServiceParam)(nil)},
+					},
+				}
+				return r
+			},
+			wantFields: []string{
+				"clientRequest.extensionServiceConfig.extensionServices[0].serviceName",
+				"clientRequest.extensionServiceConfig.extensionServices[0].serviceParam",
+				"clientRequest.extensionServiceConfig.extensionServices[1].serviceParam",
+			},
+		},
+		{
+			name: "web recording required fields",
+			request: func() *StartClientRequest {
+				return webRecordingRequest(func(param *WebRecordingServiceParam) {
+					*param = WebRecordingServiceParam{AudioProfile: 3}
+				})
+			},
+			wantFields: []string{
+				webParam + ".url",
+				webParam + ".audioProfile",
+				webParam + ".videoWidth",
+				webParam + ".videoHeight",
+				webParam + ".maxRecordingHour",
+			},
+		},
+		{
+			name: "web recording above 1920 × 1080",
+			request: func() *StartClientRequest {
+				return webRecordingRequest(func(param *WebRecordingServiceParam) {
+					param.VideoWidth = 1921
+					param.VideoHeight = 1080
+				})
+			},
+			wantFields: []string{webParam},
+		},
+		{
+			name: "web recording at 1920 × 1080",
+			request: func() *StartClientRequest {
+				return webRecordingRequest(func(param *WebRecordingServiceParam) {
+					param.VideoWidth = 1920
+					param.VideoHeight = 1080
+				})
+			},
+		},
+		{
+			name: "rtmp publish outputs",
+			request: func() *StartClientRequest {
+				r := validStartClientRequest()
+				r.ExtensionServiceConfig = &ExtensionServiceConfig{
+					ExtensionServices: []ExtensionService{
+						{ServiceName: "rtmp_publish_service", ServiceParam: &RtmpPublishServiceParam{}},
+						{ServiceName: "rtmp_publish_service", ServiceParam: &RtmpPublishServiceParam{
+							Outputs: []Outputs{{RtmpURL: "rtmp://example.com/live"}, {}},
+						}},
+						{ServiceName: "rtmp_publish_service", ServiceParam: (*RtmpPublishServiceParam)(nil)},
+					},
+				}
+				return r
+			},
+			wantFields: []string{
+				"clientRequest.extensionServiceConfig.extensionServices[0].serviceParam.outputs",
+				"clientRequest.extensionServiceConfig.extensionServices[1].serviceParam.outputs[1].rtmpUrl",
+				"clientRequest.extensionServiceConfig.extensionServices[2].serviceParam",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalidFields(t, tt.request().Validate()); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("invalid fields = %q, want %q", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidateRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		set     func(value int) *StartClientRequest
+		valid   []int
+		invalid []int
+	}{
+		{
+			name:  "captureInterval",
+			field: "clientRequest.snapshotConfig.captureInterval",
+			set: func(value int) *StartClientRequest {
+				r := validStartClientRequest()
+				r.SnapshotConfig = &SnapshotConfig{CaptureInterval: value, FileType: []string{"jpg"}}
+				return r
+			},
+			valid:   []int{0, 5, 3600},
+			invalid: []int{-1, 4, 3601},
+		},
+		{
+			name:  "maxIdleTime",
+			field: "clientRequest.recordingConfig.maxIdleTime",
+			set: func(value int) *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.MaxIdleTime = value
+				return r
+			},
+			valid:   []int{0, 5, 259200},
+			invalid: []int{-1, 4, 259201},
+		},
+		{
+			name:  "decryptionMode",
+			field: "clientRequest.recordingConfig.decryptionMode",
+			set: func(value int) *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.DecryptionMode = value
+				r.RecordingConfig.Secret = "secret"
+				r.RecordingConfig.Salt = "salt"
+				return r
+			},
+			valid:   []int{0, 1, 8},
+			invalid: []int{-1, 9},
+		},
+		{
+			name:  "subscribeUidGroup",
+			field: "clientRequest.recordingConfig.subscribeUidGroup",
+			set: func(value int) *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.SubscribeUidGroup = value
+				return r
+			},
+			valid:   []int{0, 5},
+			invalid: []int{-1, 6},
+		},
+		{
+			name:  "mixedVideoLayout",
+			field: "clientRequest.recordingConfig.transcodingConfig.mixedVideoLayout",
+			set: func(value int) *StartClientRequest {
+				r := validStartClientRequest()
+				r.RecordingConfig.TranscodingConfig = &TranscodingConfig{MixedVideoLayout: value}
+				return r
+			},
+			valid:   []int{0, 3},
+			invalid: []int{-1, 4},
+		},
+		{
+			name:  "vendor",
+			field: "clientRequest.storageConfig.vendor",
+			set: func(value int) *StartClientRequest {
+				r := validStartClientRequest()
+				r.StorageConfig.Vendor = value
+				r.StorageConfig.ExtensionParams = &ExtensionParams{Endpoint: "https://s3.example.com"}
+				return r
+			},
+			valid:   []int{1, 2, 3, 5, 6, 7, 8, 11},
+			invalid: []int{0, 4, 9, 10, 12},
+		},
+		{
+			name:  "maxRecordingHour",
+			field: "clientRequest.extensionServiceConfig.extensionServices[0].serviceParam.maxRecordingHour",
+			set: func(value int) *StartClientRequest {
+				return webRecordingRequest(func(param *WebRecordingServiceParam) {
+					param.MaxRecordingHour = value
+				})
+			},
+			valid:   []int{1, 720},
+			invalid: []int{0, 721},
+		},
+		{
+			name:  "maxVideoDuration",
+			field: "clientRequest.extensionServiceConfig.extensionServices[0].serviceParam.maxVideoDuration",
+			set: func(value int) *StartClientRequest {
+				return webRecordingRequest(func(param *WebRecordingServiceParam) {
+					param.MaxVideoDuration = value
+				})
+			},
+			valid:   []int{0, 30, 240},
+			invalid: []int{29, 241},
+		},
+		{
+			name:  "videoFps",
+			field: "clientRequest.extensionServiceConfig.extensionServices[0].serviceParam.videoFps",
+			set: func(value int) *StartClientRequest {
+				return webRecordingRequest(func(param *WebRecordingServiceParam) {
+					param.VideoFPS = value
+				})
+			},
+			valid:   []int{0, 5, 60},
+			invalid: []int{4, 61},
+		},
+		{
+			name:  "readyTimeout",
+			field: "clientRequest.extensionServiceConfig.extensionServices[0].serviceParam.readyTimeout",
+			set: func(value int) *StartClientRequest {
+				return webRecordingRequest(func(param *WebRecordingServiceParam) {
+					param.ReadyTimeout = value
+				})
+			},
+			valid:   []int{0, 60},
+			invalid: []int{-1, 61},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, value := range tt.valid {
+				if err := tt.set(value).Validate(); err != nil {
+					t.Errorf("%s %d: Validate() error = %v, want nil", tt.name, value, err)
+				}
+			}
+			for _, value := range tt.invalid {
+				got := invalidFields(t, tt.set(value).Validate())
+				if want := []string{tt.field}; !reflect.DeepEqual(got, want) {
+					t.Errorf("%s %d: invalid fields = %q, want %q", tt.name, value, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateClientRequestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    *UpdateClientRequest
+		wantFields []string
+	}{
+		{
+			name:       "nil request",
+			request:    nil,
+			wantFields: []string{"clientRequest"},
+		},
+		{
+			name:       "empty request",
+			request:    &UpdateClientRequest{},
+			wantFields: []string{"clientRequest"},
+		},
+		{
+			name:    "stream subscribe",
+			request: &UpdateClientRequest{StreamSubscribe: &UpdateStreamSubscribe{}},
+		},
+		{
+			name:    "web recording",
+			request: &UpdateClientRequest{WebRecordingConfig: &UpdateWebRecordingConfig{Onhold: true}},
+		},
+		{
+			name: "rtmp outputs",
+			request: &UpdateClientRequest{RtmpPublishConfig: &UpdateRtmpPublishConfig{
+				Outputs: []UpdateOutput{{RtmpURL: "rtmp://example.com/live"}},
+			}},
+		},
+		{
+			name: "rtmp url missing",
+			request: &UpdateClientRequest{RtmpPublishConfig: &UpdateRtmpPublishConfig{
+				Outputs: []UpdateOutput{{RtmpURL: "rtmp://example.com/live"}, {}},
+			}},
+			wantFields: []string{"clientRequest.rtmpPublishConfig.outputs[1].rtmpUrl"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalidFields(t, tt.request.Validate()); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("invalid fields = %q, want %q", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestUpdateLayoutClientRequestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    *UpdateLayoutClientRequest
+		wantFields []string
+	}{
+		{
+			name:       "nil request",
+			request:    nil,
+			wantFields: []string{"clientRequest"},
+		},
+		{
+			name:    "empty request",
+			request: &UpdateLayoutClientRequest{},
+		},
+		{
+			name:       "mixed video layout",
+			request:    &UpdateLayoutClientRequest{MixedVideoLayout: 4},
+			wantFields: []string{"clientRequest.mixedVideoLayout"},
+		},
+		{
+			name: "layout bounds",
+			request: &UpdateLayoutClientRequest{
+				MixedVideoLayout: 3,
+				LayoutConfig: []UpdateLayoutConfig{
+					{XAxis: 0, YAxis: 0, Width: 1, Height: 1, Alpha: 0, RenderMode: 1},
+					{XAxis: -0.1, YAxis: 1.1, Width: 1.1, Height: -0.1, Alpha: 1.1, RenderMode: -1},
+				},
+			},
+			wantFields: []string{
+				"clientRequest.layoutConfig[1].x_axis",
+				"clientRequest.layoutConfig[1].y_axis",
+				"clientRequest.layoutConfig[1].width",
+				"clientRequest.layoutConfig[1].height",
+				"clientRequest.layoutConfig[1].alpha",
+				"clientRequest.layoutConfig[1].render_mode",
+			},
+		},
+		{
+			name: "background",
+			request: &UpdateLayoutClientRequest{
+				BackgroundConfig: []BackgroundConfig{{UID: "1"}},
+			},
+			wantFields: []string{"clientRequest.backgroundConfig[0].image_url"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalidFields(t, tt.request.Validate()); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("invalid fields = %q, want %q", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	var errs ValidationErrors
+	if err := errs.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+
+	errs.Add("clientRequest.snapshotConfig.captureInterval", "must be in [5,3600]")
+	errs.Add("clientRequest.storageConfig.bucket", "is required")
+	err := errs.Err()
+	if err == nil {
+		t.Fatal("Err() = nil, want an error")
+	}
+
+	want := "cloud recording: invalid request: clientRequest.snapshotConfig.captureInterval: must be in [5,3600]; " +
+		"clientRequest.storageConfig.bucket: is required"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Error("errors.Is(err, ErrInvalidRequest) = false, want true")
+	}
+	if errors.Is(err, errors.New("cloud recording: invalid request")) {
+		t.Error("errors.Is(err, another error) = true, want false")
+	}
+
+	var validationErrs ValidationErrors
+	if !errors.As(err, &validationErrs) || len(validationErrs) != 2 {
+		t.Fatalf("errors.As() = %v, want 2 field errors", validationErrs)
+	}
+	if validationErrs[1].Field != "clientRequest.storageConfig.bucket" || validationErrs[1].Message != "is required" {
+		t.Errorf("field error = %+v, want clientRequest.storageConfig.bucket: is required", validationErrs[1])
+	}
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
+)
+
+const (
+	// StatusInProgress is the status of a recording that is in progress
+	StatusInProgress = 5
+	// StatusExited is the status of a recording that has exited
+	StatusExited = 8
+	// StatusExitedAbnormally is the status of a recording that has exited abnormally
+	StatusExitedAbnormally = 20
+)
+
+var (
+	// ErrRecordingExited is returned by WaitForStatus when the recording exits before the predicate is satisfied
+	ErrRecordingExited = errors.New("cloud recording: the recording has exited")
+	// ErrRecordingExitedAbnormally is returned by WaitForStatus when the recording exits abnormally before the predicate is satisfied
+	ErrRecordingExitedAbnormally = errors.New("cloud recording: the recording has exited abnormally")
+
+	errNilWaitPredicate = errors.New("cloud recording: the predicate of WaitForStatus is required")
+)
+
+const (
+	defaultWaitInitialInterval = time.Second
+	defaultWaitMaxInterval     = 10 * time.Second
+	defaultWaitMultiplier      = 2
+)
+
+// @brief Reports whether the status returned by the Query API is the awaited one, see WaitForStatus
+//
+// @since v0.13.0
+type WaitPredicate func(resp *QueryResp) bool
+
+// @brief Returns a predicate satisfied when the status of the recording is one of statuses
+//
+// @since v0.13.0
+func StatusIn(statuses ...int) WaitPredicate {
+	return func(resp *QueryResp) bool {
+		status, ok := resp.SuccessResponse.GetStatus()
+		if !ok {
+			return false
+		}
+		for _, s := range statuses {
+			if s == status {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// @brief Options of WaitForStatus
+//
+// @since v0.13.0
+type WaitOptions struct {
+	// Interval between the first two queries. The default value is 1 second.
+	InitialInterval time.Duration
+	// Upper bound of the interval between two queries. The default value is 10 seconds.
+	MaxInterval time.Duration
+	// Factor by which the interval grows after each query. The default value is 2.
+	//
+	// Values less than 1 are treated as 1, i.e. a constant interval.
+	Multiplier float64
+	// Randomization factor applied to each interval, the value range is [0,1].
+	//
+	// Values outside the range are clamped to it.
+	Jitter float64
+	// Options of each Query call, e.g. a timeout. See agora.CallOption for details.
+	CallOptions []agora.CallOption
+	// Whether a response matching agora.ErrNotFound ends the wait successfully.(Optional)
+	//
+	// The Query API no longer finds a recording once it has exited and its files are uploaded,
+	// set it to true with the predicate StatusIn(StatusExited) to wait until the upload finishes after an asynchronous Stop.
+	//
+	// The default value is false, the response is returned along with its error.
+	DoneWhenNotFound bool
+	// Whether a transient error ends the wait.(Optional)
+	//
+	// A query fails with a transient error on a network error, when the circuit breakers are open, or when the retries
+	// of a 429 or 5xx status code are exhausted.
+	//
+	// The default value is false, the recording is queried again after the next interval until ctx is done.
+	StopOnTransientError bool
+}
+
+func (o *WaitOptions) backoff() *retry.Policy {
+	policy := &retry.Policy{
+		InitialBackoff: defaultWaitInitialInterval,
+		MaxBackoff:     defaultWaitMaxInterval,
+		Multiplier:     defaultWaitMultiplier,
+	}
+	if o == nil {
+		return policy
+	}
+	if o.InitialInterval > 0 {
+		policy.InitialBackoff = o.InitialInterval
+	}
+	if o.MaxInterval > 0 {
+		policy.MaxBackoff = o.MaxInterval
+	}
+	if o.Multiplier != 0 {
+		policy.Multiplier = o.Multiplier
+	}
+	policy.Jitter = math.Min(math.Max(o.Jitter, 0), 1)
+	return policy
+}
+
+func (o *WaitOptions) doneWhenNotFound() bool {
+	return o != nil && o.DoneWhenNotFound
+}
+
+func (o *WaitOptions) stopOnTransientError() bool {
+	return o != nil && o.StopOnTransientError
+}
+
+func (o *WaitOptions) callOptions() []agora.CallOption {
+	if o == nil {
+		return nil
+	}
+	return o.CallOptions
+}
+
+// @brief Queries the recording until predicate is satisfied
+//
+// @note It returns early when the recording exits, with ErrRecordingExitedAbnormally if its status is 20,
+// and with ErrRecordingExited otherwise. An unsuccessful response, e.g. one matching agora.ErrNotFound once
+// the recording no longer exists, is returned along with its error, see agora.BaseResponse.Err,
+// unless WaitOptions.DoneWhenNotFound is set. A query failing with a transient error is sent again,
+// see WaitOptions.StopOnTransientError.
+//
+// @param ctx Context to control the wait, it returns with the error of ctx when ctx is done.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param mode The recording mode.
+//
+// @param predicate Predicate of the awaited status, e.g. StatusIn(StatusInProgress), it must not be nil. See WaitPredicate for details.
+//
+// @param opts Options of the wait, e.g. the backoff between two queries. See WaitOptions for details.
+//
+// @return Returns the last response of the Query API, nil if no response is received.
+//
+// @return Returns an error object. If the awaited status is not reached, the error object is not nil and contains error information.
+//
+// @since v0.13.0
+func (q *Query) WaitForStatus(ctx context.Context, resourceID string, sid string, mode string,
+	predicate WaitPredicate,
+	opts *WaitOptions,
+) (*QueryResp, error) {
+	if predicate == nil {
+		return nil, errNilWaitPredicate
+	}
+	backoff := opts.backoff()
+
+	var last *QueryResp
+	for retryCount := 0; ; retryCount++ {
+		resp, err := q.Do(ctx, resourceID, sid, mode, opts.callOptions()...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, ctx.Err()
+			}
+			if opts.stopOnTransientError() || !isTransient(err) {
+				return last, err
+			}
+			// The recording is queried again after the interval
+		} else {
+			last = resp
+			if done, err := waitDone(resp, predicate, opts); done {
+				return resp, err
+			}
+		}
+
+		timer := time.NewTimer(backoff.Backoff(retryCount))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return last, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// waitDone reports whether WaitForStatus returns the response, along with the error it returns.
+func waitDone(resp *QueryResp, predicate WaitPredicate, opts *WaitOptions) (bool, error) {
+	if !resp.IsSuccess() {
+		if err := resp.Err(); err != nil {
+			if opts.doneWhenNotFound() && errors.Is(err, agora.ErrNotFound) {
+				return true, nil
+			}
+			return true, err
+		}
+		return true, agora.NewGatewayErr(resp.HttpStatusCode, string(resp.RawBody))
+	}
+	if predicate(resp) {
+		return true, nil
+	}
+	if status, ok := resp.SuccessResponse.GetStatus(); ok {
+		switch status {
+		case StatusExitedAbnormally:
+			return true, ErrRecordingExitedAbnormally
+		case StatusExited:
+			return true, ErrRecordingExited
+		}
+	}
+	return false, nil
+}
+
+// isTransient reports whether a query failed with an error that may not occur when it is sent again.
+func isTransient(err error) bool {
+	var (
+		apiErr      *agora.APIError
+		netErr      net.Error
+		circuitOpen *agora.ErrCircuitOpen
+	)
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.HttpStatusCode == http.StatusTooManyRequests || apiErr.HttpStatusCode >= http.StatusInternalServerError
+	case errors.As(err, &netErr), errors.As(err, &circuitOpen), errors.Is(err, context.DeadlineExceeded):
+		return true
+	default:
+		return false
+	}
+}
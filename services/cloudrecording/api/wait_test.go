@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/auth"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
+)
+
+// queryServer responds to the n-th query, starting at 0, with statuses[n] and bodies[n], the last ones are repeated.
+// A status of 0 closes the connection instead. It records the time of each query.
+type queryServer struct {
+	statuses []int
+	bodies   []string
+
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func statusBody(status int) string {
+	return fmt.Sprintf(`{"resourceId":"resource","sid":"sid","serverResponse":{"status":%d}}`, status)
+}
+
+// okStatuses returns a queryServer responding with the recording statuses.
+func okStatuses(statuses ...int) *queryServer {
+	server := &queryServer{}
+	for _, status := range statuses {
+		server.statuses = append(server.statuses, http.StatusOK)
+		server.bodies = append(server.bodies, statusBody(status))
+	}
+	return server
+}
+
+func (q *queryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q.mu.Lock()
+	n := len(q.times)
+	q.times = append(q.times, time.Now())
+	q.mu.Unlock()
+
+	if n >= len(q.statuses) {
+		n = len(q.statuses) - 1
+	}
+	if q.statuses[n] == 0 {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(q.statuses[n])
+	_, _ = w.Write([]byte(q.bodies[n]))
+}
+
+func (q *queryServer) queryTimes() []time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]time.Time(nil), q.times...)
+}
+
+// newTestQuery returns a Query sending requests to a test server run by handler, each request is sent once.
+func newTestQuery(t *testing.T, handler http.Handler) *Query {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := client.New(&agora.Config{
+		AppID:       "appid",
+		BaseURL:     server.URL,
+		Credential:  auth.NewBasicAuthCredential("customer", "secret"),
+		Logger:      log.DiscardLogger,
+		RetryPolicy: &retry.Policy{MaxAttempts: 1},
+	})
+	if err != nil {
+		t.Fatalf("client.New() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	return NewQuery("cloudRecording:query", log.DiscardLogger, c, "/v1/apps/appid/cloud_recording")
+}
+
+// fastWait returns wait options with short intervals.
+func fastWait() *WaitOptions {
+	return &WaitOptions{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	notFound := func() *queryServer {
+		return &queryServer{statuses: []int{http.StatusNotFound}, bodies: []string{`{"code":404,"reason":"failed to find worker"}`}}
+	}
+
+	tests := []struct {
+		name         string
+		server       *queryServer
+		predicate    WaitPredicate
+		opts         func(opts *WaitOptions)
+		wantErr      error
+		wantAPIError int
+		wantStatus   int
+		wantQueries  int
+		wantNoResult bool
+	}{
+		{name: "predicate satisfied", server: okStatuses(1, 4, 5), wantStatus: StatusInProgress, wantQueries: 3},
+		{name: "exited abnormally", server: okStatuses(1, 20), wantErr: ErrRecordingExitedAbnormally, wantStatus: 20, wantQueries: 2},
+		{name: "exited", server: okStatuses(1, 8), wantErr: ErrRecordingExited, wantStatus: StatusExited, wantQueries: 2},
+		{
+			name:        "exited satisfies the predicate",
+			server:      okStatuses(5, 8),
+			predicate:   StatusIn(StatusExited),
+			wantStatus:  StatusExited,
+			wantQueries: 2,
+		},
+		{name: "not found", server: notFound(), wantErr: agora.ErrNotFound, wantQueries: 1},
+		{
+			name:      "done when not found",
+			server:    notFound(),
+			predicate: StatusIn(StatusExited),
+			opts: func(opts *WaitOptions) {
+				opts.DoneWhenNotFound = true
+			},
+			wantQueries: 1,
+		},
+		{
+			name: "transient errors",
+			server: &queryServer{
+				statuses: []int{http.StatusServiceUnavailable, 0, http.StatusOK},
+				bodies:   []string{`{"code":503}`, "", statusBody(StatusInProgress)},
+			},
+			wantStatus:  StatusInProgress,
+			wantQueries: 3,
+		},
+		{
+			name:         "stop on transient error",
+			server:       &queryServer{statuses: []int{http.StatusServiceUnavailable}, bodies: []string{`{"code":503}`}},
+			opts:         func(opts *WaitOptions) { opts.StopOnTransientError = true },
+			wantAPIError: http.StatusServiceUnavailable,
+			wantQueries:  1,
+			wantNoResult: true,
+		},
+		{
+			name:         "non transient error",
+			server:       &queryServer{statuses: []int{http.StatusOK, http.StatusBadRequest}, bodies: []string{statusBody(1), `{"code":2}`}},
+			wantAPIError: http.StatusBadRequest,
+			wantQueries:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newTestQuery(t, tt.server)
+			predicate := tt.predicate
+			if predicate == nil {
+				predicate = StatusIn(StatusInProgress)
+			}
+			opts := fastWait()
+			if tt.opts != nil {
+				tt.opts(opts)
+			}
+
+			resp, err := q.WaitForStatus(context.Background(), "resource", "sid", IndividualMode, predicate, opts)
+			var apiErr *agora.APIError
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("WaitForStatus() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantAPIError != 0:
+				if !errors.As(err, &apiErr) || apiErr.HttpStatusCode != tt.wantAPIError {
+					t.Errorf("WaitForStatus() error = %v, want an API error with status code %d", err, tt.wantAPIError)
+				}
+			case err != nil:
+				t.Errorf("WaitForStatus() error = %v", err)
+			}
+			if n := len(tt.server.queryTimes()); n != tt.wantQueries {
+				t.Errorf("queries = %d, want %d", n, tt.wantQueries)
+			}
+
+			if tt.wantNoResult {
+				if resp != nil {
+					t.Errorf("WaitForStatus() = %+v, want no response", resp)
+				}
+				return
+			}
+			if resp == nil {
+				t.Fatal("WaitForStatus() returned no response")
+			}
+			if status, _ := resp.SuccessResponse.GetStatus(); status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestWaitForStatusContextDone(t *testing.T) {
+	server := okStatuses(1)
+	q := newTestQuery(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := q.WaitForStatus(ctx, "resource", "sid", IndividualMode, StatusIn(StatusInProgress), fastWait())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForStatus() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if resp == nil {
+		t.Fatal("WaitForStatus() returned no response")
+	}
+	if status, _ := resp.SuccessResponse.GetStatus(); status != 1 {
+		t.Errorf("status = %d, want 1", status)
+	}
+	if n := len(server.queryTimes()); n < 2 {
+		t.Errorf("queries = %d, want at least 2", n)
+	}
+}
+
+func TestWaitForStatusContextDoneOnTransientErrors(t *testing.T) {
+	server := &queryServer{statuses: []int{http.StatusServiceUnavailable}, bodies: []string{`{"code":503}`}}
+	q := newTestQuery(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resp, err := q.WaitForStatus(ctx, "resource", "sid", IndividualMode, StatusIn(StatusInProgress), fastWait())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitForStatus() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if resp != nil {
+		t.Errorf("WaitForStatus() = %+v, want no response", resp)
+	}
+	if n := len(server.queryTimes()); n < 2 {
+		t.Errorf("queries = %d, want at least 2", n)
+	}
+}
+
+func TestWaitForStatusBackoff(t *testing.T) {
+	server := okStatuses(1, 1, 1, 1, 1, StatusInProgress)
+	q := newTestQuery(t, server)
+
+	_, err := q.WaitForStatus(context.Background(), "resource", "sid", IndividualMode, StatusIn(StatusInProgress), &WaitOptions{
+		InitialInterval: 10 * time.Millisecond,
+		MaxInterval:     40 * time.Millisecond,
+		Multiplier:      2,
+	})
+	if err != nil {
+		t.Fatalf("WaitForStatus() error = %v", err)
+	}
+
+	times := server.queryTimes()
+	wantGaps := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	if len(times) != len(wantGaps)+1 {
+		t.Fatalf("queries = %d, want %d", len(times), len(wantGaps)+1)
+	}
+	for i, want := range wantGaps {
+		if gap := times[i+1].Sub(times[i]); gap < want {
+			t.Errorf("interval %d = %v, want at least %v", i, gap, want)
+		}
+	}
+}
+
+func TestWaitOptionsJitter(t *testing.T) {
+	tests := []struct {
+		jitter float64
+		want   float64
+	}{
+		{jitter: -1, want: 0},
+		{jitter: 0, want: 0},
+		{jitter: 0.5, want: 0.5},
+		{jitter: 1, want: 1},
+		{jitter: 5, want: 1},
+	}
+	for _, tt := range tests {
+		if got := (&WaitOptions{Jitter: tt.jitter}).backoff().Jitter; got != tt.want {
+			t.Errorf("backoff().Jitter with Jitter %v = %v, want %v", tt.jitter, got, tt.want)
+		}
+	}
+}
+
+func TestWaitForStatusNilPredicate(t *testing.T) {
+	server := okStatuses(StatusInProgress)
+	q := newTestQuery(t, server)
+
+	if _, err := q.WaitForStatus(context.Background(), "resource", "sid", IndividualMode, nil, nil); err == nil {
+		t.Error("WaitForStatus() error = nil, want an error")
+	}
+	if n := len(server.queryTimes()); n != 0 {
+		t.Errorf("queries = %d, want 0", n)
+	}
+}
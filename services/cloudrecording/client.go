@@ -2,20 +2,27 @@ package cloudrecording
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/auth"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/breaker"
 	agoraClient "github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/domain"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/limit"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/scenario"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/session"
 )
 
 const projectName = "cloud_recording"
 
 type Client struct {
+	client agoraClient.Client
+
 	acquireAPI      *api.Acquire
 	startAPI        *api.Start
 	stopAPI         *api.Stop
@@ -26,6 +33,10 @@ type Client struct {
 	individualRecordingScenario *scenario.IndividualRecording
 	webRecordingScenario        *scenario.WebRecording
 	mixRecordingScenario        *scenario.MixRecording
+	snapshotScenario            *scenario.Snapshot
+	audioOnlyRecordingScenario  *scenario.AudioOnlyRecording
+
+	sessionManager *session.Manager
 }
 
 // @brief Defines the configuration for the Cloud Recording client
@@ -34,26 +45,119 @@ type Client struct {
 type Config struct {
 	// Agora AppID
 	AppID string
-	// Timeout for HTTP requests
+	// App certificate of the project.(Optional)
+	//
+	// When it is set, an RTC or RTM token is minted for the requests that need one but are given none, see the token package.
+	AppCertificate string
+	// Timeout for each HTTP attempt of a call without a deadline. The default value is 10 seconds.
+	//
+	// Pass a context with a deadline, or use agora.WithCallTimeout and agora.WithAttemptTimeout,
+	// to control the timeouts of a single call.
 	HttpTimeout time.Duration
+	// HTTP client used to send requests.(Optional)
+	//
+	// Set it to use your own proxy, mTLS, CA bundle or connection pool settings.
+	// The client is copied, HttpTimeout and Transport are ignored when it is set.
+	HttpClient *http.Client
+	// Transport used by the default HTTP client.(Optional)
+	//
+	// Only takes effect when HttpClient is nil. The default value is http.DefaultTransport.
+	Transport http.RoundTripper
+	// Middlewares applied to every HTTP request.(Optional)
+	//
+	// The first middleware is the outermost one. See agora.Middleware for details.
+	Middlewares []agora.Middleware
+	// Maximum size of a response body in bytes.(Optional)
+	//
+	// A larger response fails the call with an agora.ErrResponseTooLarge. The size is not limited when it is 0.
+	MaxResponseBodySize int64
+	// Whether to request gzip compressed responses and decompress them.(Optional)
+	//
+	// MaxResponseBodySize applies to the decompressed body.
+	EnableGzip bool
+	// Whether to drop agora.BaseResponse.RawBody once the response is decoded, to reduce memory usage of callers that
+	// keep many responses, e.g. when polling Query.(Optional)
+	DiscardRawBody bool
+	// Hooks run around every HTTP attempt, with metadata about the service operation.(Optional)
+	//
+	// See agora.Hook for details.
+	Hooks []agora.Hook
+	// Retry policy for failed requests.(Optional)
+	//
+	// When it is nil, retry.DefaultPolicy() is used with RetryCount as the maximum number of attempts.
+	// See retry.Policy for details.
+	RetryPolicy *retry.Policy
+
+	// Client-side rate and concurrency limits of the requests, e.g. to stay below the QPS limit of the AppID.(Optional)
+	//
+	// Nothing is limited by default. See limit.Policy for details.
+	RateLimit *limit.Policy
+	// Instrumentation that observes every API call and HTTP attempt, e.g. for tracing and metrics.(Optional)
+	//
+	// See agora.Instrumentation for details.
+	Instrumentation agora.Instrumentation
 	// Credential for accessing the Agora service.
 	//
 	// Available credential types:
 	//
 	//  - BasicAuthCredential: See auth.NewBasicAuthCredential for details
+	//
+	//  - TokenCredential: See auth.NewTokenCredential for details
 	Credential auth.Credential
+	// Provider of the credential used by each request, it takes precedence over Credential.(Optional)
+	//
+	// Use it when the credential rotates. See auth.CredentialProvider for details.
+	CredentialProvider auth.CredentialProvider
 
 	// Domain area for the REST Client. See domain.Area for details.
 	DomainArea domain.Area
+	// Region prefixes and domain suffixes used instead of the ones of DomainArea.(Optional)
+	//
+	// See domain.WithDomain for details.
+	Domain *domain.Domain
+	// Resolver that selects the domain suffix.(Optional)
+	//
+	// The default resolver resolves the domain of every suffix concurrently and selects the first one resolved.
+	Resolver domain.Resolver
+	// Fixed base URL all requests are sent to, e.g. for a private deployment or a local stand-in server.(Optional)
+	//
+	// DomainArea, Domain and Resolver are ignored when it is set. See domain.WithBaseURL for details.
+	BaseURL string
+	// Policy of the endpoint health tracking, which selects the endpoint requests are sent to.(Optional)
+	//
+	// The default value is domain.DefaultHealthPolicy(). See domain.HealthPolicy for details.
+	HealthPolicy *domain.HealthPolicy
+	// Policy of the circuit breaker of every endpoint host.(Optional)
+	//
+	// Requests fail fast with an agora.ErrCircuitOpen while the circuits of all the endpoints are open,
+	// and they are sent to another endpoint while the circuit of the best one is open.
+	//
+	// The default value is breaker.DefaultPolicy(). See breaker.Policy for details.
+	CircuitBreaker *breaker.Policy
+	// Interval of the background domain refresh.(Optional)
+	//
+	// When it is set, the domain is resolved by a background goroutine instead of when a request is sent,
+	// call Close to stop it. See domain.WithBackgroundRefresh for details.
+	DomainRefreshInterval time.Duration
+	// Handler of domain resolution errors.(Optional)
+	//
+	// The last known-good domain is kept when the resolution fails, requests are not affected.
+	OnDomainResolveError func(err error)
 
 	// Logger for the REST Client
 	//
 	// Implement the log.Logger interface in your project to output REST Client logs to your logging component.
 	//
 	// Alternatively, you can use the default logging component. See log.NewDefaultLogger for details.
+	//
+	// To output structured logs, wrap a log.StructuredLogger, e.g. log.NewSlogLogger, with log.NewLogger.
+	// The values of sensitive fields in logged request and response bodies are masked, see log.SensitiveFields.
 	Logger log.Logger
 }
 
+// Deprecated: Use Config.RetryPolicy, or agora.MaxAttempts for a single call, instead.
+//
+// RetryCount is the maximum number of attempts used when Config.RetryPolicy is nil, see retry.PolicyOrDefault.
 var RetryCount = 3
 
 // NewClient
@@ -62,63 +166,100 @@ var RetryCount = 3
 //
 // @param config Configuration of the Cloud Recording client instance. See Config for details.
 //
+// @param options Options of the underlying client, e.g. a domain pool shared with other clients. See agoraClient.Option for details.
+//
 // @return Returns the Cloud Recording client instance.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
 //
 // @since v0.8.0
-func NewClient(config *Config) (*Client, error) {
+func NewClient(config *Config, options ...agoraClient.Option) (*Client, error) {
 	prefixPath := "/v1/apps/" + config.AppID + "/" + projectName
 
 	agoraClient, err := agoraClient.New(&agora.Config{
-		AppID:       config.AppID,
-		HttpTimeout: config.HttpTimeout,
-		Credential:  config.Credential,
-		DomainArea:  config.DomainArea,
-		Logger:      config.Logger,
-	})
+		AppID:                 config.AppID,
+		AppCertificate:        config.AppCertificate,
+		HttpTimeout:           config.HttpTimeout,
+		HttpClient:            config.HttpClient,
+		Transport:             config.Transport,
+		Middlewares:           config.Middlewares,
+		MaxResponseBodySize:   config.MaxResponseBodySize,
+		EnableGzip:            config.EnableGzip,
+		DiscardRawBody:        config.DiscardRawBody,
+		Hooks:                 config.Hooks,
+		RetryPolicy:           retry.PolicyOrDefault(config.RetryPolicy, RetryCount),
+		Instrumentation:       config.Instrumentation,
+		RateLimit:             config.RateLimit,
+		CredentialProvider:    config.CredentialProvider,
+		Credential:            config.Credential,
+		DomainArea:            config.DomainArea,
+		Domain:                config.Domain,
+		Resolver:              config.Resolver,
+		BaseURL:               config.BaseURL,
+		HealthPolicy:          config.HealthPolicy,
+		CircuitBreaker:        config.CircuitBreaker,
+		DomainRefreshInterval: config.DomainRefreshInterval,
+		OnDomainResolveError:  config.OnDomainResolveError,
+		Logger:                config.Logger,
+	}, options...)
 	if err != nil {
 		return nil, err
 	}
 
 	c := &Client{
-		acquireAPI:      api.NewAcquire("cloudRecording:acquire", config.Logger, RetryCount, agoraClient, prefixPath),
-		startAPI:        api.NewStart("cloudRecording:start", config.Logger, RetryCount, agoraClient, prefixPath),
-		stopAPI:         api.NewStop("cloudRecording:stop", config.Logger, RetryCount, agoraClient, prefixPath),
-		queryAPI:        api.NewQuery("cloudRecording:query", config.Logger, RetryCount, agoraClient, prefixPath),
-		updateLayoutAPI: api.NewUpdateLayout("cloudRecording:updateLayout", config.Logger, RetryCount, agoraClient, prefixPath),
-		updateAPI:       api.NewUpdate("cloudRecording:update", config.Logger, RetryCount, agoraClient, prefixPath),
+		client:          agoraClient,
+		acquireAPI:      api.NewAcquire("cloudRecording:acquire", config.Logger, agoraClient, prefixPath),
+		startAPI:        api.NewStart("cloudRecording:start", config.Logger, agoraClient, prefixPath),
+		stopAPI:         api.NewStop("cloudRecording:stop", config.Logger, agoraClient, prefixPath),
+		queryAPI:        api.NewQuery("cloudRecording:query", config.Logger, agoraClient, prefixPath),
+		updateLayoutAPI: api.NewUpdateLayout("cloudRecording:updateLayout", config.Logger, agoraClient, prefixPath),
+		updateAPI:       api.NewUpdate("cloudRecording:update", config.Logger, agoraClient, prefixPath),
 	}
 
 	c.individualRecordingScenario = scenario.NewIndividualRecording(c.acquireAPI, c.startAPI, c.stopAPI, c.queryAPI, c.updateAPI)
 	c.webRecordingScenario = scenario.NewWebRecording(c.acquireAPI, c.startAPI, c.stopAPI, c.queryAPI, c.updateAPI)
 	c.mixRecordingScenario = scenario.NewMixRecording(c.acquireAPI, c.startAPI, c.stopAPI, c.queryAPI, c.updateLayoutAPI, c.updateAPI)
+	c.snapshotScenario = scenario.NewSnapshot(c.acquireAPI, c.startAPI, c.stopAPI, c.queryAPI, c.updateAPI)
+	c.audioOnlyRecordingScenario = scenario.NewAudioOnlyRecording(c.acquireAPI, c.startAPI, c.stopAPI, c.queryAPI, c.updateAPI)
+	c.sessionManager = session.NewManager(c)
 
 	return c, nil
 }
 
-func (c *Client) Acquire(ctx context.Context, payload *api.AcquireReqBody) (*api.AcquireResp, error) {
-	return c.acquireAPI.Do(ctx, payload)
+func (c *Client) Acquire(ctx context.Context, payload *api.AcquireReqBody, opts ...agora.CallOption) (*api.AcquireResp, error) {
+	return c.acquireAPI.Do(ctx, payload, opts...)
+}
+
+func (c *Client) Start(ctx context.Context, resourceID string, mode string, payload *api.StartReqBody, opts ...agora.CallOption) (*api.StartResp, error) {
+	return c.startAPI.Do(ctx, resourceID, mode, payload, opts...)
 }
 
-func (c *Client) Start(ctx context.Context, resourceID string, mode string, payload *api.StartReqBody) (*api.StartResp, error) {
-	return c.startAPI.Do(ctx, resourceID, mode, payload)
+func (c *Client) Stop(ctx context.Context, resourceID string, sid string, mode string, payload *api.StopReqBody, opts ...agora.CallOption) (*api.StopResp, error) {
+	return c.stopAPI.Do(ctx, resourceID, sid, mode, payload, opts...)
 }
 
-func (c *Client) Stop(ctx context.Context, resourceID string, sid string, mode string, payload *api.StopReqBody) (*api.StopResp, error) {
-	return c.stopAPI.Do(ctx, resourceID, sid, mode, payload)
+func (c *Client) Query(ctx context.Context, resourceID string, sid string, mode string, opts ...agora.CallOption) (*api.QueryResp, error) {
+	return c.queryAPI.Do(ctx, resourceID, sid, mode, opts...)
 }
 
-func (c *Client) Query(ctx context.Context, resourceID string, sid string, mode string) (*api.QueryResp, error) {
-	return c.queryAPI.Do(ctx, resourceID, sid, mode)
+// @brief Queries the recording until predicate is satisfied, see api.Query.WaitForStatus
+//
+// @since v0.13.0
+//
+// @example Use api.StatusIn(api.StatusExited) with api.WaitOptions.DoneWhenNotFound to wait until the files are uploaded after an asynchronous Stop.
+func (c *Client) WaitForStatus(ctx context.Context, resourceID string, sid string, mode string,
+	predicate api.WaitPredicate,
+	opts *api.WaitOptions,
+) (*api.QueryResp, error) {
+	return c.queryAPI.WaitForStatus(ctx, resourceID, sid, mode, predicate, opts)
 }
 
-func (c *Client) Update(ctx context.Context, resourceID string, sid string, mode string, payload *api.UpdateReqBody) (*api.UpdateResp, error) {
-	return c.updateAPI.Do(ctx, resourceID, sid, mode, payload)
+func (c *Client) Update(ctx context.Context, resourceID string, sid string, mode string, payload *api.UpdateReqBody, opts ...agora.CallOption) (*api.UpdateResp, error) {
+	return c.updateAPI.Do(ctx, resourceID, sid, mode, payload, opts...)
 }
 
-func (c *Client) UpdateLayout(ctx context.Context, resourceID string, sid string, mode string, payload *api.UpdateLayoutReqBody) (*api.UpdateLayoutResp, error) {
-	return c.updateLayoutAPI.Do(ctx, resourceID, sid, mode, payload)
+func (c *Client) UpdateLayout(ctx context.Context, resourceID string, sid string, mode string, payload *api.UpdateLayoutReqBody, opts ...agora.CallOption) (*api.UpdateLayoutResp, error) {
+	return c.updateLayoutAPI.Do(ctx, resourceID, sid, mode, payload, opts...)
 }
 
 // @brief Returns the individual recording scenario instance.
@@ -147,3 +288,64 @@ func (c *Client) WebRecording() *scenario.WebRecording {
 func (c *Client) MixRecording() *scenario.MixRecording {
 	return c.mixRecordingScenario
 }
+
+// @brief Returns the video screenshot capture scenario instance.
+//
+// @return Returns the video screenshot capture scenario instance. See scenario.Snapshot for details.
+//
+// @since v0.13.0
+func (c *Client) Snapshot() *scenario.Snapshot {
+	return c.snapshotScenario
+}
+
+// @brief Returns the audio-only recording scenario instance.
+//
+// @return Returns the audio-only recording scenario instance. See scenario.AudioOnlyRecording for details.
+//
+// @since v0.13.0
+func (c *Client) AudioOnlyRecording() *scenario.AudioOnlyRecording {
+	return c.audioOnlyRecordingScenario
+}
+
+// @brief Returns the session manager, which runs the Acquire, Start, Query and Stop lifecycle of recordings.
+//
+// @return Returns the session manager. See session.Manager for details.
+//
+// @since v0.13.0
+func (c *Client) Sessions() *session.Manager {
+	return c.sessionManager
+}
+
+// @brief Returns the endpoint health state of the domain pool, e.g. for dashboards
+//
+// @return Returns the snapshot. See domain.PoolSnapshot for details.
+//
+// @since v0.13.0
+func (c *Client) DomainPoolSnapshot() domain.PoolSnapshot {
+	return c.client.GetDomainPoolSnapshot()
+}
+
+// @brief Stops the background domain refresh of the client, see Config.DomainRefreshInterval
+//
+// @note The client must not be used after it is closed.
+//
+// @since v0.13.0
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// @brief Returns the statistics of the rate and concurrency limiters of the client, e.g. the time requests waited
+//
+// @return Returns the statistics. See limit.GroupStats for details.
+//
+// @since v0.13.0
+func (c *Client) LimiterStats() limit.GroupStats {
+	return c.client.GetLimiterStats()
+}
+
+// @brief Returns the state of the circuit breaker of every endpoint host used so far, keyed by the host
+//
+// @since v0.13.0
+func (c *Client) CircuitBreakerStates() map[string]breaker.State {
+	return c.client.GetCircuitBreakerStates()
+}
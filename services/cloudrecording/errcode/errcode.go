@@ -0,0 +1,167 @@
+package errcode
+
+// @brief Error code returned by the cloud recording API in ErrResponse.ErrorCode
+//
+// @since v0.13.0
+type Code int
+
+const (
+	// Invalid request parameters
+	InvalidParameter Code = 2
+	// The recording has already been started, or the request was sent repeatedly
+	RecordingAlreadyStarted Code = 7
+	// Invalid HTTP request header
+	InvalidHeader Code = 8
+	// The recording has already been stopped
+	RepeatedStop Code = 49
+	// A recording with the same channel name and UID is already running
+	RecordingAlreadyRunning Code = 53
+	// Cloud recording is not enabled for the App ID
+	ServiceNotEnabled Code = 62
+	// The request failed because of network jitter
+	NetworkJitter Code = 65
+	// The token has expired
+	TokenExpired Code = 109
+	// The token is invalid
+	TokenInvalid Code = 110
+	// The recording has not been started, has already exited, or the resource does not exist
+	ResourceNotFound Code = 404
+	// The request parameters do not match the App ID, the recording mode or the resource ID
+	ParameterMismatch Code = 432
+	// The resource ID has expired
+	ResourceExpired Code = 433
+	// No recorded file was generated because no user sent streams in the channel
+	NoRecordedFile Code = 435
+	// The recording service is exiting
+	RecordingExiting Code = 501
+	// Failed to parse the request body
+	InvalidRequestBody Code = 1001
+	// Invalid App ID
+	InvalidAppID Code = 1003
+	// Invalid channel name
+	InvalidChannelName Code = 1013
+	// Invalid layout parameters in an updateLayout request
+	InvalidLayout Code = 1028
+)
+
+// @brief Details of a cloud recording error code
+//
+// @since v0.13.0
+type Info struct {
+	// Error code
+	Code Code
+	// Description of the error
+	Description string
+	// Whether the failed request did not take effect and can be sent again
+	Retryable bool
+	// Suggested action to resolve the error
+	Action string
+}
+
+var catalog = map[Code]Info{
+	InvalidParameter: {
+		Description: "invalid request parameters",
+		Action:      "check the request parameters against the API reference",
+	},
+	RecordingAlreadyStarted: {
+		Description: "the recording has already been started, or the request was sent repeatedly",
+		Action:      "query the recording status instead of starting it again",
+	},
+	InvalidHeader: {
+		Description: "invalid HTTP request header",
+		Action:      "check the Authorization and Content-Type headers",
+	},
+	RepeatedStop: {
+		Description: "the recording has already been stopped",
+		Action:      "no action is required, the recording is no longer running",
+	},
+	RecordingAlreadyRunning: {
+		Description: "a recording with the same channel name and UID is already running",
+		Action:      "stop the running recording, or start the new one with a different UID",
+	},
+	ServiceNotEnabled: {
+		Description: "cloud recording is not enabled for the App ID",
+		Action:      "enable cloud recording for the project in Agora Console",
+	},
+	NetworkJitter: {
+		Description: "the request failed because of network jitter",
+		Retryable:   true,
+		Action:      "send the request again",
+	},
+	TokenExpired: {
+		Description: "the token has expired",
+		Action:      "generate a new token and start the recording again",
+	},
+	TokenInvalid: {
+		Description: "the token is invalid",
+		Action:      "check that the token is generated with the App ID, channel name and UID of the recording",
+	},
+	ResourceNotFound: {
+		Description: "the recording has not been started, has already exited, or the resource does not exist",
+		Action:      "check the resource ID and sid, the recording may have exited because nobody is in the channel",
+	},
+	ParameterMismatch: {
+		Description: "the request parameters do not match the App ID, the recording mode or the resource ID",
+		Action:      "use the same App ID, channel name, UID and mode as the acquire and start requests",
+	},
+	ResourceExpired: {
+		Description: "the resource ID has expired",
+		Action:      "acquire a new resource ID and start the recording within 5 minutes",
+	},
+	NoRecordedFile: {
+		Description: "no recorded file was generated because no user sent streams in the channel",
+		Action:      "check that users publish streams in the channel during the recording",
+	},
+	RecordingExiting: {
+		Description: "the recording service is exiting",
+		Action:      "wait until the recording has exited, then start a new one if needed",
+	},
+	InvalidRequestBody: {
+		Description: "failed to parse the request body",
+		Action:      "check that the request body is valid JSON",
+	},
+	InvalidAppID: {
+		Description: "invalid App ID",
+		Action:      "check the App ID and that the credential belongs to the same account",
+	},
+	InvalidChannelName: {
+		Description: "invalid channel name",
+		Action:      "check that the channel name only contains supported characters and does not exceed 64 bytes",
+	},
+	InvalidLayout: {
+		Description: "invalid layout parameters in an updateLayout request",
+		Action:      "check the layout parameters, e.g. the region of each user must be within the canvas",
+	},
+}
+
+// @brief Look up a documented cloud recording error code
+//
+// @return Returns the details of the code, and false if the code is not documented.
+//
+// @since v0.13.0
+func Lookup(code int) (Info, bool) {
+	info, ok := catalog[Code(code)]
+	info.Code = Code(code)
+	return info, ok
+}
+
+// @brief Describe a cloud recording error code
+//
+// @return Returns the details of the code. The description of an undocumented code is "unknown error code".
+//
+// @since v0.13.0
+func Describe(code int) Info {
+	info, ok := Lookup(code)
+	if !ok {
+		info.Description = "unknown error code"
+		info.Action = "contact Agora technical support with the request ID"
+	}
+	return info
+}
+
+// @brief Get the details of the code, see Describe
+//
+// @since v0.13.0
+func (c Code) Info() Info {
+	return Describe(int(c))
+}
@@ -0,0 +1,58 @@
+package errcode
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name          string
+		code          int
+		wantOk        bool
+		wantRetryable bool
+	}{
+		{name: "network jitter", code: 65, wantOk: true, wantRetryable: true},
+		{name: "resource expired", code: 433, wantOk: true},
+		{name: "repeated stop", code: 49, wantOk: true},
+		{name: "undocumented", code: 12345},
+		{name: "no code", code: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, ok := Lookup(tt.code)
+			if ok != tt.wantOk || info.Retryable != tt.wantRetryable {
+				t.Errorf("Lookup(%d) = %+v, %t, want retryable %t and %t", tt.code, info, ok, tt.wantRetryable, tt.wantOk)
+			}
+			if info.Code != Code(tt.code) {
+				t.Errorf("Lookup(%d).Code = %d, want %d", tt.code, info.Code, tt.code)
+			}
+			if ok && (info.Description == "" || info.Action == "") {
+				t.Errorf("Lookup(%d) = %+v, want a description and an action", tt.code, info)
+			}
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	for code, info := range catalog {
+		if info.Description == "" || info.Action == "" {
+			t.Errorf("catalog[%d] = %+v, want a description and an action", code, info)
+		}
+		// Only a code that proves the request did not take effect is retryable
+		if info.Retryable != (code == NetworkJitter) {
+			t.Errorf("catalog[%d].Retryable = %t, want %t", code, info.Retryable, code == NetworkJitter)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	if info := Describe(int(TokenExpired)); info.Description != "the token has expired" || info.Code != TokenExpired {
+		t.Errorf("Describe(109) = %+v, want the details of TokenExpired", info)
+	}
+	if info := ResourceNotFound.Info(); info.Code != ResourceNotFound || info.Description == "unknown error code" {
+		t.Errorf("ResourceNotFound.Info() = %+v, want the details of ResourceNotFound", info)
+	}
+
+	info := Describe(12345)
+	if info.Code != 12345 || info.Description != "unknown error code" || info.Retryable {
+		t.Errorf("Describe(12345) = %+v, want a non-retryable unknown error code", info)
+	}
+}
@@ -0,0 +1,120 @@
+package req
+
+import "github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+
+// @brief Client request for acquiring audio-only recording resources.
+//
+// @since v0.13.0
+type AcquireAudioOnlyRecordingClientRequest struct {
+	// The validity period for calling the cloud recording RESTful API.(Optional)
+	//
+	// Start calculating after you successfully initiate the cloud recording service and obtain the sid (Recording ID).
+	//
+	// The calculation unit is hours.
+	//
+	// The value range is [1,720]. The default value is 72.
+	ResourceExpiredHour int
+
+	// The resourceId of another or several other recording tasks.(Optional)
+	ExcludeResourceIds []string
+
+	// Specify regions that the cloud recording service can access.(Optional)
+	//
+	// The region can be set to:
+	//
+	//  - 0: Closest to request origin (default)
+	// 	- 1: China
+	// 	- 2: Southeast Asia
+	// 	- 3: Europe
+	// 	- 4: North America
+	RegionAffinity int
+
+	// StartParameter improves availability and optimizes load balancing.(Optional)
+	StartParameter *StartAudioOnlyRecordingClientRequest
+}
+
+// @brief Client request for starting individual audio-only recording, e.g. for compliance or voice review.
+//
+// @since v0.13.0
+type StartAudioOnlyRecordingClientRequest struct {
+	// Agora App Token.(Optional)
+	Token string
+
+	// Configuration for third-party cloud storage.(Required)
+	StorageConfig *api.StorageConfig
+
+	// Configuration for the recorded audio streams.(Required)
+	//
+	// Only audio streams can be subscribed, i.e. streamTypes must be 0 and subscribeVideoUids must be empty.
+	// The audio is recorded without transcoding it, i.e. streamMode must be "original",
+	// an empty streamMode is sent as "original".
+	RecordingConfig *api.RecordingConfig
+
+	// Configuration for recorded files.(Optional)
+	RecordingFileConfig *api.RecordingFileConfig
+}
+
+// @brief Checks the field combinations of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *StartAudioOnlyRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	if r.StorageConfig == nil {
+		errs.Add("clientRequest.storageConfig", "is required")
+	}
+	if r.RecordingConfig == nil {
+		errs.Add("clientRequest.recordingConfig", "is required")
+		return errs.Err()
+	}
+	if r.RecordingConfig.StreamTypes != 0 {
+		errs.Add("clientRequest.recordingConfig.streamTypes", "must be 0 to record audio only")
+	}
+	if len(r.RecordingConfig.SubscribeVideoUIDs) > 0 {
+		errs.Add("clientRequest.recordingConfig.subscribeVideoUids", "must be empty to record audio only")
+	}
+	if r.RecordingConfig.StreamMode != "" && r.RecordingConfig.StreamMode != "original" {
+		errs.Add("clientRequest.recordingConfig.streamMode", `must be "original" to record audio only`)
+	}
+	appendValidationErrors(&errs, (&api.StartClientRequest{
+		Token:               r.Token,
+		RecordingConfig:     r.RecordingConfig,
+		RecordingFileConfig: r.RecordingFileConfig,
+		StorageConfig:       r.StorageConfig,
+	}).Validate())
+
+	return errs.Err()
+}
+
+// @brief Client request for updating audio-only recording.
+//
+// @since v0.13.0
+type UpdateAudioOnlyRecordingClientRequest struct {
+	// Update subscription lists of the audio streams.(Optional)
+	AudioUidList *api.UpdateAudioUIDList
+}
+
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *UpdateAudioOnlyRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	if r.AudioUidList == nil {
+		errs.Add("clientRequest.streamSubscribe.audioUidList", "is required")
+	}
+
+	return errs.Err()
+}
@@ -53,6 +53,32 @@ type StartIndividualRecordingClientRequest struct {
 	SnapshotConfig *api.SnapshotConfig
 }
 
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *StartIndividualRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	if r.StorageConfig == nil {
+		errs.Add("clientRequest.storageConfig", "is required")
+	}
+	appendValidationErrors(&errs, (&api.StartClientRequest{
+		Token:               r.Token,
+		RecordingConfig:     r.RecordingConfig,
+		RecordingFileConfig: r.RecordingFileConfig,
+		SnapshotConfig:      r.SnapshotConfig,
+		StorageConfig:       r.StorageConfig,
+	}).Validate())
+
+	return errs.Err()
+}
+
 // @brief Client request for updating individual recording.
 //
 // @since v0.8.0
@@ -60,3 +86,22 @@ type UpdateIndividualRecordingClientRequest struct {
 	// Update subscription lists.(Optional)
 	StreamSubscribe *api.UpdateStreamSubscribe
 }
+
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *UpdateIndividualRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	appendValidationErrors(&errs, (&api.UpdateClientRequest{
+		StreamSubscribe: r.StreamSubscribe,
+	}).Validate())
+
+	return errs.Err()
+}
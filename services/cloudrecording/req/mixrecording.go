@@ -53,6 +53,28 @@ type StartMixRecordingClientRequest struct {
 	SnapshotConfig *api.SnapshotConfig
 }
 
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *StartMixRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	appendValidationErrors(&errs, (&api.StartClientRequest{
+		Token:               r.Token,
+		RecordingConfig:     r.RecordingConfig,
+		RecordingFileConfig: r.RecordingFileConfig,
+		StorageConfig:       r.StorageConfig,
+	}).Validate())
+
+	return errs.Err()
+}
+
 // @brief Client request for updating mix recording.
 //
 // @since v0.8.0
@@ -61,6 +83,25 @@ type UpdateMixRecordingClientRequest struct {
 	StreamSubscribe *api.UpdateStreamSubscribe
 }
 
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *UpdateMixRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	appendValidationErrors(&errs, (&api.UpdateClientRequest{
+		StreamSubscribe: r.StreamSubscribe,
+	}).Validate())
+
+	return errs.Err()
+}
+
 // @brief Client request for updating the layout of mix recording.
 //
 // @since v0.8.0
@@ -120,3 +161,28 @@ type UpdateLayoutUpdateMixRecordingClientRequest struct {
 	// The backgroundConfig field is used to set the background of the video windows.
 	BackgroundConfig []api.BackgroundConfig
 }
+
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *UpdateLayoutUpdateMixRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	appendValidationErrors(&errs, (&api.UpdateLayoutClientRequest{
+		MaxResolutionUID:           r.MaxResolutionUID,
+		MixedVideoLayout:           r.MixedVideoLayout,
+		BackgroundColor:            r.BackgroundColor,
+		BackgroundImage:            r.BackgroundImage,
+		DefaultUserBackgroundImage: r.DefaultUserBackgroundImage,
+		LayoutConfig:               r.LayoutConfig,
+		BackgroundConfig:           r.BackgroundConfig,
+	}).Validate())
+
+	return errs.Err()
+}
@@ -0,0 +1,113 @@
+package req
+
+import "github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+
+// @brief Client request for acquiring video screenshot capture resources.
+//
+// @since v0.13.0
+type AcquireSnapshotClientRequest struct {
+	// The validity period for calling the cloud recording RESTful API.(Optional)
+	//
+	// Start calculating after you successfully initiate the cloud recording service and obtain the sid (Recording ID).
+	//
+	// The calculation unit is hours.
+	//
+	// The value range is [1,720]. The default value is 72.
+	ResourceExpiredHour int
+
+	// The resourceId of another or several other recording tasks.(Optional)
+	ExcludeResourceIds []string
+
+	// Specify regions that the cloud recording service can access.(Optional)
+	//
+	// The region can be set to:
+	//
+	//  - 0: Closest to request origin (default)
+	// 	- 1: China
+	// 	- 2: Southeast Asia
+	// 	- 3: Europe
+	// 	- 4: North America
+	RegionAffinity int
+
+	// StartParameter improves availability and optimizes load balancing.(Optional)
+	StartParameter *StartSnapshotClientRequest
+}
+
+// @brief Client request for starting video screenshot capture, without recording audio or video files.
+//
+// @since v0.13.0
+type StartSnapshotClientRequest struct {
+	// Agora App Token.(Optional)
+	Token string
+
+	// Configuration for third-party cloud storage.(Required)
+	StorageConfig *api.StorageConfig
+
+	// Configuration for the subscribed video streams.(Optional)
+	//
+	// Video streams must be subscribed, i.e. streamTypes must be 1 or 2.
+	RecordingConfig *api.RecordingConfig
+
+	// Configurations for screenshot capture.(Required)
+	//
+	// captureInterval must be in [5,3600], and fileType must be ["jpg"].
+	SnapshotConfig *api.SnapshotConfig
+}
+
+// @brief Checks the field combinations of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *StartSnapshotClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	if r.StorageConfig == nil {
+		errs.Add("clientRequest.storageConfig", "is required")
+	}
+	if r.RecordingConfig != nil && r.RecordingConfig.StreamTypes == 0 {
+		errs.Add("clientRequest.recordingConfig.streamTypes", "must be 1 or 2 to capture video screenshots")
+	}
+	if r.SnapshotConfig == nil {
+		errs.Add("clientRequest.snapshotConfig", "is required")
+	}
+	appendValidationErrors(&errs, (&api.StartClientRequest{
+		Token:           r.Token,
+		RecordingConfig: r.RecordingConfig,
+		SnapshotConfig:  r.SnapshotConfig,
+		StorageConfig:   r.StorageConfig,
+	}).Validate())
+
+	return errs.Err()
+}
+
+// @brief Client request for updating video screenshot capture.
+//
+// @since v0.13.0
+type UpdateSnapshotClientRequest struct {
+	// Update subscription lists.(Optional)
+	StreamSubscribe *api.UpdateStreamSubscribe
+}
+
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *UpdateSnapshotClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	appendValidationErrors(&errs, (&api.UpdateClientRequest{
+		StreamSubscribe: r.StreamSubscribe,
+	}).Validate())
+
+	return errs.Err()
+}
@@ -0,0 +1,15 @@
+package req
+
+import (
+	"errors"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+)
+
+// appendValidationErrors appends the invalid fields reported by err, the result of an api Validate method, to errs.
+func appendValidationErrors(errs *api.ValidationErrors, err error) {
+	var validationErrs api.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		*errs = append(*errs, validationErrs...)
+	}
+}
@@ -0,0 +1,178 @@
+package req
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+)
+
+// invalidFields returns the paths of the invalid fields reported by err.
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var errs api.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want an api.ValidationErrors", err)
+	}
+	fields := make([]string, 0, len(errs))
+	for _, fieldErr := range errs {
+		fields = append(fields, fieldErr.Field)
+	}
+	return fields
+}
+
+func storageConfig() *api.StorageConfig {
+	return &api.StorageConfig{
+		Vendor:    2,
+		Region:    3,
+		Bucket:    "bucket",
+		AccessKey: "accessKey",
+		SecretKey: "secretKey",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    interface{ Validate() error }
+		wantFields []string
+	}{
+		{
+			name:       "nil snapshot request",
+			request:    (*StartSnapshotClientRequest)(nil),
+			wantFields: []string{"clientRequest"},
+		},
+		{
+			name:    "snapshot",
+			request: &StartSnapshotClientRequest{StorageConfig: storageConfig(), SnapshotConfig: &api.SnapshotConfig{CaptureInterval: 5, FileType: []string{"jpg"}}},
+		},
+		{
+			name:    "snapshot required fields",
+			request: &StartSnapshotClientRequest{},
+			wantFields: []string{
+				"clientRequest.storageConfig",
+				"clientRequest.snapshotConfig",
+			},
+		},
+		{
+			name: "snapshot of audio streams",
+			request: &StartSnapshotClientRequest{
+				RecordingConfig: &api.RecordingConfig{StreamTypes: 0},
+				StorageConfig:   storageConfig(),
+				SnapshotConfig:  &api.SnapshotConfig{CaptureInterval: 4, FileType: []string{"jpg"}},
+			},
+			wantFields: []string{
+				"clientRequest.recordingConfig.streamTypes",
+				"clientRequest.snapshotConfig.captureInterval",
+			},
+		},
+		{
+			name:    "audio only",
+			request: &StartAudioOnlyRecordingClientRequest{StorageConfig: storageConfig(), RecordingConfig: &api.RecordingConfig{StreamTypes: 0}},
+		},
+		{
+			name:    "audio only in original stream mode",
+			request: &StartAudioOnlyRecordingClientRequest{StorageConfig: storageConfig(), RecordingConfig: &api.RecordingConfig{StreamMode: "original"}},
+		},
+		{
+			name:       "audio only in standard stream mode",
+			request:    &StartAudioOnlyRecordingClientRequest{StorageConfig: storageConfig(), RecordingConfig: &api.RecordingConfig{StreamMode: "standard"}},
+			wantFields: []string{"clientRequest.recordingConfig.streamMode"},
+		},
+		{
+			name:    "audio only required fields",
+			request: &StartAudioOnlyRecordingClientRequest{},
+			wantFields: []string{
+				"clientRequest.storageConfig",
+				"clientRequest.recordingConfig",
+			},
+		},
+		{
+			name: "audio only with video",
+			request: &StartAudioOnlyRecordingClientRequest{
+				StorageConfig: storageConfig(),
+				RecordingConfig: &api.RecordingConfig{
+					StreamTypes:        2,
+					SubscribeVideoUIDs: []string{"1"},
+					MaxIdleTime:        259201,
+				},
+			},
+			wantFields: []string{
+				"clientRequest.recordingConfig.streamTypes",
+				"clientRequest.recordingConfig.subscribeVideoUids",
+				"clientRequest.recordingConfig.maxIdleTime",
+			},
+		},
+		{
+			name:       "audio only update without audio uids",
+			request:    &UpdateAudioOnlyRecordingClientRequest{},
+			wantFields: []string{"clientRequest.streamSubscribe.audioUidList"},
+		},
+		{
+			name:       "individual recording without storage",
+			request:    &StartIndividualRecordingClientRequest{RecordingConfig: &api.RecordingConfig{StreamTypes: 2}},
+			wantFields: []string{"clientRequest.storageConfig"},
+		},
+		{
+			name: "mix recording above 1920 × 1080",
+			request: &StartMixRecordingClientRequest{
+				StorageConfig: storageConfig(),
+				RecordingConfig: &api.RecordingConfig{
+					StreamTypes:       2,
+					TranscodingConfig: &api.TranscodingConfig{Width: 1920, Height: 1088},
+				},
+			},
+			wantFields: []string{"clientRequest.recordingConfig.transcodingConfig"},
+		},
+		{
+			name:       "mix recording update without stream subscribe",
+			request:    &UpdateMixRecordingClientRequest{},
+			wantFields: []string{"clientRequest"},
+		},
+		{
+			name:       "mix recording layout",
+			request:    &UpdateLayoutUpdateMixRecordingClientRequest{LayoutConfig: []api.UpdateLayoutConfig{{XAxis: 1.1, Width: 1, Height: 1}}},
+			wantFields: []string{"clientRequest.layoutConfig[0].x_axis"},
+		},
+		{
+			name: "web recording",
+			request: &StartWebRecordingClientRequest{
+				StorageConfig: storageConfig(),
+				ExtensionServiceConfig: &api.ExtensionServiceConfig{
+					ExtensionServices: []api.ExtensionService{
+						{ServiceName: "web_recorder_service", ServiceParam: &api.WebRecordingServiceParam{
+							URL:              "https://example.com",
+							VideoWidth:       1280,
+							VideoHeight:      720,
+							MaxRecordingHour: 721,
+						}},
+					},
+				},
+			},
+			wantFields: []string{"clientRequest.extensionServiceConfig.extensionServices[0].serviceParam.maxRecordingHour"},
+		},
+		{
+			name: "web recording update without rtmp url",
+			request: &UpdateWebRecordingClientRequest{
+				RtmpPublishConfig: &api.UpdateRtmpPublishConfig{Outputs: []api.UpdateOutput{{}}},
+			},
+			wantFields: []string{"clientRequest.rtmpPublishConfig.outputs[0].rtmpUrl"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if got := invalidFields(t, err); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("invalid fields = %q, want %q", got, tt.wantFields)
+			}
+			if err != nil && !errors.Is(err, api.ErrInvalidRequest) {
+				t.Errorf("errors.Is(%v, api.ErrInvalidRequest) = false, want true", err)
+			}
+		})
+	}
+}
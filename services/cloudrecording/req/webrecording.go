@@ -47,6 +47,27 @@ type StartWebRecordingClientRequest struct {
 	ExtensionServiceConfig *api.ExtensionServiceConfig
 }
 
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *StartWebRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	appendValidationErrors(&errs, (&api.StartClientRequest{
+		RecordingFileConfig:    r.RecordingFileConfig,
+		StorageConfig:          r.StorageConfig,
+		ExtensionServiceConfig: r.ExtensionServiceConfig,
+	}).Validate())
+
+	return errs.Err()
+}
+
 // @brief Client request for updating web page recording.
 //
 // @since v0.8.0
@@ -56,3 +77,23 @@ type UpdateWebRecordingClientRequest struct {
 	// Used to update the configurations for pushing web page recording to the CDN.(Optional)
 	RtmpPublishConfig *api.UpdateRtmpPublishConfig
 }
+
+// @brief Checks the documented constraints of the request before it is sent
+//
+// @return Returns an api.ValidationErrors describing every invalid field, or nil if the request is valid.
+//
+// @since v0.13.0
+func (r *UpdateWebRecordingClientRequest) Validate() error {
+	var errs api.ValidationErrors
+	if r == nil {
+		errs.Add("clientRequest", "is required")
+		return errs.Err()
+	}
+
+	appendValidationErrors(&errs, (&api.UpdateClientRequest{
+		WebRecordingConfig: r.WebRecordingConfig,
+		RtmpPublishConfig:  r.RtmpPublishConfig,
+	}).Validate())
+
+	return errs.Err()
+}
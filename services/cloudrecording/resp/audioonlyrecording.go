@@ -0,0 +1,53 @@
+package resp
+
+import "github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+
+// @brief Response returned by the audio-only recording Query API.
+//
+// @since v0.13.0
+type QueryAudioOnlyRecordingResp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see QueryAudioOnlyRecordingSuccessResp for details
+	SuccessResponse QueryAudioOnlyRecordingSuccessResp
+}
+
+// @brief Successful response returned by the audio-only recording Query API.
+//
+// @since v0.13.0
+type QueryAudioOnlyRecordingSuccessResp struct {
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see QueryIndividualRecordingServerResponse for details
+	ServerResponse *api.QueryIndividualRecordingServerResponse
+}
+
+// @brief Response returned by the audio-only recording Stop API.
+//
+// @since v0.13.0
+type StopAudioOnlyRecordingResp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see StopAudioOnlyRecordingSuccessResp for details
+	SuccessResponse StopAudioOnlyRecordingSuccessResp
+}
+
+// @brief Successful response returned by the audio-only recording Stop API.
+//
+// @since v0.13.0
+type StopAudioOnlyRecordingSuccessResp struct {
+	// Name of the channel to be recorded
+	Cname string
+	// User ID used by the cloud recording service in the RTC channel to identify the recording service in the channel
+	UID string
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see StopIndividualRecordingServerResponse for details
+	//
+	// It is nil when the recording is stopped asynchronously.
+	ServerResponse *api.StopIndividualRecordingServerResponse
+}
@@ -45,3 +45,59 @@ type QueryIndividualRecordingVideoScreenshotSuccessResp struct {
 	// Server response, see QueryIndividualVideoScreenshotServerResponse for details
 	ServerResponse *api.QueryIndividualVideoScreenshotServerResponse
 }
+
+// @brief Response returned by the individual recording StopRecording API.
+//
+// @since v0.13.0
+type StopIndividualRecordingResp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see StopIndividualRecordingSuccessResp for details
+	SuccessResponse StopIndividualRecordingSuccessResp
+}
+
+// @brief Successful response returned by the individual recording StopRecording API.
+//
+// @since v0.13.0
+type StopIndividualRecordingSuccessResp struct {
+	// Name of the channel to be recorded
+	Cname string
+	// User ID used by the cloud recording service in the RTC channel to identify the recording service in the channel
+	UID string
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see StopIndividualRecordingServerResponse for details
+	//
+	// It is nil when the recording is stopped asynchronously.
+	ServerResponse *api.StopIndividualRecordingServerResponse
+}
+
+// @brief Response returned by the individual recording StopVideoScreenshot API.
+//
+// @since v0.13.0
+type StopIndividualRecordingVideoScreenshotResp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see StopIndividualRecordingVideoScreenshotSuccessResp for details
+	SuccessResponse StopIndividualRecordingVideoScreenshotSuccessResp
+}
+
+// @brief Successful response returned by the individual recording StopVideoScreenshot API.
+//
+// @since v0.13.0
+type StopIndividualRecordingVideoScreenshotSuccessResp struct {
+	// Name of the channel to be recorded
+	Cname string
+	// User ID used by the cloud recording service in the RTC channel to identify the recording service in the channel
+	UID string
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see StopIndividualVideoScreenshotServerResponse for details
+	//
+	// It is nil when the recording is stopped asynchronously.
+	ServerResponse *api.StopIndividualVideoScreenshotServerResponse
+}
@@ -45,3 +45,59 @@ type QueryMixRecordingHLSAndMP4Resp struct {
 	// Successful response, see QueryMixRecordingHLSAndMP4SuccessResp for details
 	SuccessResponse QueryMixRecordingHLSAndMP4SuccessResp
 }
+
+// @brief Response returned by the mix recording StopHLS API.
+//
+// @since v0.13.0
+type StopMixRecordingHLSResp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see StopMixRecordingHLSSuccessResp for details
+	SuccessResponse StopMixRecordingHLSSuccessResp
+}
+
+// @brief Successful response returned by the mix recording StopHLS API.
+//
+// @since v0.13.0
+type StopMixRecordingHLSSuccessResp struct {
+	// Name of the channel to be recorded
+	Cname string
+	// User ID used by the cloud recording service in the RTC channel to identify the recording service in the channel
+	UID string
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see StopMixRecordingHLSServerResponse for details
+	//
+	// It is nil when the recording is stopped asynchronously.
+	ServerResponse *api.StopMixRecordingHLSServerResponse
+}
+
+// @brief Response returned by the mix recording StopHLSAndMP4 API.
+//
+// @since v0.13.0
+type StopMixRecordingHLSAndMP4Resp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see StopMixRecordingHLSAndMP4SuccessResp for details
+	SuccessResponse StopMixRecordingHLSAndMP4SuccessResp
+}
+
+// @brief Successful response returned by the mix recording StopHLSAndMP4 API.
+//
+// @since v0.13.0
+type StopMixRecordingHLSAndMP4SuccessResp struct {
+	// Name of the channel to be recorded
+	Cname string
+	// User ID used by the cloud recording service in the RTC channel to identify the recording service in the channel
+	UID string
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see StopMixRecordingHLSAndMP4ServerResponse for details
+	//
+	// It is nil when the recording is stopped asynchronously.
+	ServerResponse *api.StopMixRecordingHLSAndMP4ServerResponse
+}
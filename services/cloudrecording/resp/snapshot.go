@@ -0,0 +1,53 @@
+package resp
+
+import "github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+
+// @brief Response returned by the snapshot Query API.
+//
+// @since v0.13.0
+type QuerySnapshotResp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see QuerySnapshotSuccessResp for details
+	SuccessResponse QuerySnapshotSuccessResp
+}
+
+// @brief Successful response returned by the snapshot Query API.
+//
+// @since v0.13.0
+type QuerySnapshotSuccessResp struct {
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see QueryIndividualVideoScreenshotServerResponse for details
+	ServerResponse *api.QueryIndividualVideoScreenshotServerResponse
+}
+
+// @brief Response returned by the snapshot Stop API.
+//
+// @since v0.13.0
+type StopSnapshotResp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see StopSnapshotSuccessResp for details
+	SuccessResponse StopSnapshotSuccessResp
+}
+
+// @brief Successful response returned by the snapshot Stop API.
+//
+// @since v0.13.0
+type StopSnapshotSuccessResp struct {
+	// Name of the channel to be recorded
+	Cname string
+	// User ID used by the cloud recording service in the RTC channel to identify the recording service in the channel
+	UID string
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see StopIndividualVideoScreenshotServerResponse for details
+	//
+	// It is nil when the recording is stopped asynchronously.
+	ServerResponse *api.StopIndividualVideoScreenshotServerResponse
+}
@@ -45,3 +45,31 @@ type QueryRtmpPublishSuccessResp struct {
 	// Server response, see QueryRtmpPublishServerResponse for details
 	ServerResponse *api.QueryRtmpPublishServerResponse
 }
+
+// @brief Response returned by the web recording StopWebRecording API.
+//
+// @since v0.13.0
+type StopWebRecordingResp struct {
+	// Response returned by the cloud recording API, see Response for details
+	api.Response
+	// Successful response, see StopWebRecordingSuccessResp for details
+	SuccessResponse StopWebRecordingSuccessResp
+}
+
+// @brief Successful response returned by the web recording StopWebRecording API.
+//
+// @since v0.13.0
+type StopWebRecordingSuccessResp struct {
+	// Name of the channel to be recorded
+	Cname string
+	// User ID used by the cloud recording service in the RTC channel to identify the recording service in the channel
+	UID string
+	// Unique identifier of the resource
+	ResourceId string
+	// Unique identifier of the recording session
+	Sid string
+	// Server response, see StopWebRecordingServerResponse for details
+	//
+	// It is nil when the recording is stopped asynchronously.
+	ServerResponse *api.StopWebRecordingServerResponse
+}
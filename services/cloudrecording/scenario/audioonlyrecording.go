@@ -0,0 +1,295 @@
+package scenario
+
+import (
+	"context"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/req"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/resp"
+)
+
+// @brief Audio-only recording in individual mode, e.g. for compliance or voice review.
+//
+// @since v0.13.0
+type AudioOnlyRecording struct {
+	acquireAPI *api.Acquire
+	startAPI   *api.Start
+	stopAPI    *api.Stop
+	queryAPI   *api.Query
+	updateAPI  *api.Update
+}
+
+func NewAudioOnlyRecording(
+	acquireAPI *api.Acquire,
+	startAPI *api.Start,
+	stopAPI *api.Stop,
+	queryAPI *api.Query,
+	updateAPI *api.Update,
+) *AudioOnlyRecording {
+	return &AudioOnlyRecording{
+		acquireAPI: acquireAPI,
+		startAPI:   startAPI,
+		stopAPI:    stopAPI,
+		queryAPI:   queryAPI,
+		updateAPI:  updateAPI,
+	}
+}
+
+// @brief Get a resource ID for audio-only recording.
+//
+// @since v0.13.0
+//
+// @post After receiving the resource ID, call the Start API to start recording.
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param cname The name of the channel to be recorded.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param clientRequest The request body. See req.AcquireAudioOnlyRecordingClientRequest for details.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *AcquireResp. See api.AcquireResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (a *AudioOnlyRecording) Acquire(ctx context.Context, cname string, uid string,
+	clientRequest *req.AcquireAudioOnlyRecordingClientRequest,
+	opts ...agora.CallOption,
+) (*api.AcquireResp, error) {
+	var startParameter *api.StartClientRequest
+	if clientRequest.StartParameter != nil {
+		startParameter = &api.StartClientRequest{
+			Token:               clientRequest.StartParameter.Token,
+			StorageConfig:       clientRequest.StartParameter.StorageConfig,
+			RecordingConfig:     originalStreamMode(clientRequest.StartParameter.RecordingConfig),
+			RecordingFileConfig: clientRequest.StartParameter.RecordingFileConfig,
+		}
+	}
+
+	return a.acquireAPI.Do(ctx, &api.AcquireReqBody{
+		Cname: cname,
+		Uid:   uid,
+		ClientRequest: &api.AcquireClientRequest{
+			Scene:               0,
+			ResourceExpiredHour: clientRequest.ResourceExpiredHour,
+			ExcludeResourceIds:  clientRequest.ExcludeResourceIds,
+			RegionAffinity:      clientRequest.RegionAffinity,
+			StartParameter:      startParameter,
+		},
+	}, opts...)
+}
+
+// originalStreamMode returns a copy of config recording the audio without transcoding it, the config of the caller is left unchanged.
+func originalStreamMode(config *api.RecordingConfig) *api.RecordingConfig {
+	if config == nil {
+		return nil
+	}
+	original := *config
+	original.StreamMode = "original"
+	return &original
+}
+
+// @brief Start audio-only recording.
+//
+// @since v0.13.0
+//
+// @note The request is validated before it is sent, see req.StartAudioOnlyRecordingClientRequest.Validate.
+// The streamMode of the recording config is sent as "original".
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param cname Channel name.
+//
+// @param uid User ID.
+//
+// @param clientRequest The request body. See req.StartAudioOnlyRecordingClientRequest for details.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StartResp. See api.StartResp for details.
+//
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
+func (a *AudioOnlyRecording) Start(ctx context.Context, resourceId string, cname string, uid string,
+	clientRequest *req.StartAudioOnlyRecordingClientRequest,
+	opts ...agora.CallOption,
+) (*api.StartResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a.startAPI.Do(ctx, resourceId, api.IndividualMode, &api.StartReqBody{
+		Cname: cname,
+		Uid:   uid,
+		ClientRequest: &api.StartClientRequest{
+			Token:               clientRequest.Token,
+			RecordingConfig:     originalStreamMode(clientRequest.RecordingConfig),
+			RecordingFileConfig: clientRequest.RecordingFileConfig,
+			StorageConfig:       clientRequest.StorageConfig,
+		},
+	}, opts...)
+}
+
+// @brief Query the status of audio-only recording.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *QueryAudioOnlyRecordingResp. See resp.QueryAudioOnlyRecordingResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (a *AudioOnlyRecording) Query(ctx context.Context, resourceId string, sid string, opts ...agora.CallOption) (*resp.QueryAudioOnlyRecordingResp, error) {
+	respData, err := a.queryAPI.Do(ctx, resourceId, sid, api.IndividualMode, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var audioResp resp.QueryAudioOnlyRecordingResp
+
+	audioResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		audioResp.SuccessResponse = resp.QueryAudioOnlyRecordingSuccessResp{
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetIndividualRecordingServerResponse(),
+		}
+	}
+
+	return &audioResp, nil
+}
+
+// @brief Update the subscribed audio streams of audio-only recording.
+//
+// @since v0.13.0
+//
+// @note The request is validated before it is sent, see req.UpdateAudioOnlyRecordingClientRequest.Validate.
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be recorded.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param clientRequest The request body. See req.UpdateAudioOnlyRecordingClientRequest for details.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *UpdateResp. See api.UpdateResp for details.
+//
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
+func (a *AudioOnlyRecording) Update(ctx context.Context, resourceId string, sid string, cname string, uid string,
+	clientRequest *req.UpdateAudioOnlyRecordingClientRequest,
+	opts ...agora.CallOption,
+) (*api.UpdateResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a.updateAPI.Do(ctx, resourceId, sid, api.IndividualMode, &api.UpdateReqBody{
+		Cname: cname,
+		Uid:   uid,
+		ClientRequest: &api.UpdateClientRequest{
+			StreamSubscribe: &api.UpdateStreamSubscribe{
+				AudioUidList: clientRequest.AudioUidList,
+			},
+		},
+	}, opts...)
+}
+
+// @brief Stop audio-only recording, and return the final file list.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be recorded.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param asyncStop Whether to stop the recording asynchronously.
+//   - true: Stop the recording asynchronously, the response carries no server response.
+//   - false: Stop the recording synchronously.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StopAudioOnlyRecordingResp. See resp.StopAudioOnlyRecordingResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (a *AudioOnlyRecording) Stop(ctx context.Context, resourceId string, sid string, cname string, uid string,
+	asyncStop bool,
+	opts ...agora.CallOption,
+) (*resp.StopAudioOnlyRecordingResp, error) {
+	respData, err := a.stopAPI.Do(ctx, resourceId, sid, api.IndividualMode, &api.StopReqBody{
+		Cname: cname,
+		Uid:   uid,
+		ClientRequest: &api.StopClientRequest{
+			AsyncStop: asyncStop,
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var audioResp resp.StopAudioOnlyRecordingResp
+
+	audioResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		audioResp.SuccessResponse = resp.StopAudioOnlyRecordingSuccessResp{
+			Cname:          successResp.Cname,
+			UID:            successResp.UID,
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetIndividualRecordingServerResponse(),
+		}
+	}
+
+	return &audioResp, nil
+}
+
+// @brief Query the status of audio-only recording until predicate is satisfied.
+//
+// @since v0.13.0
+//
+// @example Use api.StatusIn(api.StatusExited) with api.WaitOptions.DoneWhenNotFound to wait until the files are uploaded after an asynchronous Stop.
+//
+// @param ctx Context to control the wait.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param predicate Predicate of the awaited status. See api.WaitPredicate for details.
+//
+// @param opts Options of the wait, e.g. the backoff between two queries. See api.WaitOptions for details.
+//
+// @return Returns the last response *QueryResp. See api.QueryResp for details.
+//
+// @return Returns an error object. If the awaited status is not reached, the error object is not nil and contains error information.
+// See api.Query.WaitForStatus for details.
+func (a *AudioOnlyRecording) WaitForStatus(ctx context.Context, resourceID string, sid string,
+	predicate api.WaitPredicate,
+	opts *api.WaitOptions,
+) (*api.QueryResp, error) {
+	return a.queryAPI.WaitForStatus(ctx, resourceID, sid, api.IndividualMode, predicate, opts)
+}
@@ -0,0 +1,130 @@
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/auth"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/req"
+)
+
+// newTestAudioOnlyRecording returns an AudioOnlyRecording sending requests to a test server,
+// which decodes each request body into the value returned by body and responds with respBody.
+func newTestAudioOnlyRecording(t *testing.T, body func() interface{}, respBody string) *AudioOnlyRecording {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read request body: %v", err)
+		}
+		if err := json.Unmarshal(data, body()); err != nil {
+			t.Errorf("decode request body %s: %v", data, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(respBody))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(&agora.Config{
+		AppID:       "appid",
+		BaseURL:     server.URL,
+		Credential:  auth.NewBasicAuthCredential("customer", "secret"),
+		Logger:      log.DiscardLogger,
+		RetryPolicy: &retry.Policy{MaxAttempts: 1},
+	})
+	if err != nil {
+		t.Fatalf("client.New() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+
+	const prefixPath = "/v1/apps/appid/cloud_recording"
+	return NewAudioOnlyRecording(
+		api.NewAcquire("cloudRecording:acquire", log.DiscardLogger, c, prefixPath),
+		api.NewStart("cloudRecording:start", log.DiscardLogger, c, prefixPath),
+		api.NewStop("cloudRecording:stop", log.DiscardLogger, c, prefixPath),
+		api.NewQuery("cloudRecording:query", log.DiscardLogger, c, prefixPath),
+		api.NewUpdate("cloudRecording:update", log.DiscardLogger, c, prefixPath),
+	)
+}
+
+func TestAudioOnlyRecordingStreamMode(t *testing.T) {
+	storageConfig := &api.StorageConfig{
+		Vendor:    2,
+		Region:    3,
+		Bucket:    "bucket",
+		AccessKey: "accessKey",
+		SecretKey: "secretKey",
+	}
+
+	t.Run("start", func(t *testing.T) {
+		var body api.StartReqBody
+		recording := newTestAudioOnlyRecording(t, func() interface{} { return &body }, `{"cname":"channel","uid":"1","resourceId":"resource","sid":"sid"}`)
+
+		recordingConfig := &api.RecordingConfig{ChannelType: 1, SubscribeAudioUIDs: []string{"#allstream#"}}
+		resp, err := recording.Start(context.Background(), "resource", "channel", "1", &req.StartAudioOnlyRecordingClientRequest{
+			StorageConfig:   storageConfig,
+			RecordingConfig: recordingConfig,
+		})
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		if !resp.IsSuccess() {
+			t.Fatalf("Start() = %+v, want a successful response", resp)
+		}
+		if got := body.ClientRequest.RecordingConfig.StreamMode; got != "original" {
+			t.Errorf("sent streamMode = %q, want %q", got, "original")
+		}
+		if recordingConfig.StreamMode != "" {
+			t.Errorf("streamMode of the caller = %q, want it unchanged", recordingConfig.StreamMode)
+		}
+	})
+
+	t.Run("acquire", func(t *testing.T) {
+		var body api.AcquireReqBody
+		recording := newTestAudioOnlyRecording(t, func() interface{} { return &body }, `{"resourceId":"resource"}`)
+
+		resp, err := recording.Acquire(context.Background(), "channel", "1", &req.AcquireAudioOnlyRecordingClientRequest{
+			StartParameter: &req.StartAudioOnlyRecordingClientRequest{
+				StorageConfig:   storageConfig,
+				RecordingConfig: &api.RecordingConfig{ChannelType: 1},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		if !resp.IsSuccess() {
+			t.Fatalf("Acquire() = %+v, want a successful response", resp)
+		}
+		if got := body.ClientRequest.StartParameter.RecordingConfig.StreamMode; got != "original" {
+			t.Errorf("sent streamMode = %q, want %q", got, "original")
+		}
+	})
+
+	t.Run("standard", func(t *testing.T) {
+		recording := newTestAudioOnlyRecording(t, func() interface{} {
+			t.Error("the invalid request was sent")
+			return &api.StartReqBody{}
+		}, `{}`)
+
+		_, err := recording.Start(context.Background(), "resource", "channel", "1", &req.StartAudioOnlyRecordingClientRequest{
+			StorageConfig:   storageConfig,
+			RecordingConfig: &api.RecordingConfig{StreamMode: "standard"},
+		})
+		if !errors.Is(err, api.ErrInvalidRequest) {
+			t.Errorf("Start() error = %v, want %v", err, api.ErrInvalidRequest)
+		}
+	})
+}
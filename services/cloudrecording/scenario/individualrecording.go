@@ -3,6 +3,7 @@ package scenario
 import (
 	"context"
 
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/req"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/resp"
@@ -46,11 +47,14 @@ func NewIndividualRecording(
 //
 // @param clientRequest The request body.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *AcquireResp. See api.AcquireResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
 func (i *IndividualRecording) Acquire(ctx context.Context, cname string, uid string,
 	clientRequest *req.AcquireIndividualRecordingClientRequest,
+	opts ...agora.CallOption,
 ) (*api.AcquireResp, error) {
 	var startParameter *api.StartClientRequest
 	if clientRequest.StartParameter != nil {
@@ -73,13 +77,15 @@ func (i *IndividualRecording) Acquire(ctx context.Context, cname string, uid str
 			RegionAffinity:      clientRequest.RegionAffinity,
 			StartParameter:      startParameter,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Start individual cloud recording.
 //
 // @since v0.8.0
 //
+// @note The request is validated before it is sent, see req.StartIndividualRecordingClientRequest.Validate.
+//
 // @param ctx Context to control the request lifecycle.
 //
 // @param resourceID The resource ID.
@@ -90,12 +96,19 @@ func (i *IndividualRecording) Acquire(ctx context.Context, cname string, uid str
 //
 // @param clientRequest The request body. See req.StartIndividualRecordingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *StartResp. See api.StartResp for details.
 //
-// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
 func (i *IndividualRecording) Start(ctx context.Context, resourceId string, cname string, uid string,
 	clientRequest *req.StartIndividualRecordingClientRequest,
+	opts ...agora.CallOption,
 ) (*api.StartResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
 	return i.startAPI.Do(ctx, resourceId, api.IndividualMode, &api.StartReqBody{
 		Cname: cname,
 		Uid:   uid,
@@ -106,7 +119,7 @@ func (i *IndividualRecording) Start(ctx context.Context, resourceId string, cnam
 			SnapshotConfig:      clientRequest.SnapshotConfig,
 			StorageConfig:       clientRequest.StorageConfig,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Query the status of individual cloud recording when video screenshot capture is turned off.
@@ -119,11 +132,13 @@ func (i *IndividualRecording) Start(ctx context.Context, resourceId string, cnam
 //
 // @param sid The recording ID, identifying a recording cycle.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *QueryIndividualRecordingResp. See resp.QueryIndividualRecordingResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (i *IndividualRecording) Query(ctx context.Context, resourceId string, sid string) (*resp.QueryIndividualRecordingResp, error) {
-	respData, err := i.queryAPI.Do(ctx, resourceId, sid, api.IndividualMode)
+func (i *IndividualRecording) Query(ctx context.Context, resourceId string, sid string, opts ...agora.CallOption) (*resp.QueryIndividualRecordingResp, error) {
+	respData, err := i.queryAPI.Do(ctx, resourceId, sid, api.IndividualMode, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -153,11 +168,13 @@ func (i *IndividualRecording) Query(ctx context.Context, resourceId string, sid
 //
 // @param sid The recording ID, identifying a recording cycle.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *QueryIndividualRecordingVideoScreenshotResp. See resp.QueryIndividualRecordingVideoScreenshotResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (i *IndividualRecording) QueryVideoScreenshot(ctx context.Context, resourceId string, sid string) (*resp.QueryIndividualRecordingVideoScreenshotResp, error) {
-	respData, err := i.queryAPI.Do(ctx, resourceId, sid, api.IndividualMode)
+func (i *IndividualRecording) QueryVideoScreenshot(ctx context.Context, resourceId string, sid string, opts ...agora.CallOption) (*resp.QueryIndividualRecordingVideoScreenshotResp, error) {
+	respData, err := i.queryAPI.Do(ctx, resourceId, sid, api.IndividualMode, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -181,6 +198,8 @@ func (i *IndividualRecording) QueryVideoScreenshot(ctx context.Context, resource
 //
 // @since v0.8.0
 //
+// @note The request is validated before it is sent, see req.UpdateIndividualRecordingClientRequest.Validate.
+//
 // @param ctx Context to control the request lifecycle.
 //
 // @param resourceID The resource ID.
@@ -193,23 +212,32 @@ func (i *IndividualRecording) QueryVideoScreenshot(ctx context.Context, resource
 //
 // @param clientRequest The request body. See req.UpdateIndividualRecordingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *UpdateResp. See api.UpdateResp for details.
 //
-// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
 func (i *IndividualRecording) Update(ctx context.Context, resourceId string, sid string, cname string, uid string,
 	clientRequest *req.UpdateIndividualRecordingClientRequest,
+	opts ...agora.CallOption,
 ) (*api.UpdateResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
 	return i.updateAPI.Do(ctx, resourceId, sid, api.IndividualMode, &api.UpdateReqBody{
 		Cname: cname,
 		Uid:   uid,
 		ClientRequest: &api.UpdateClientRequest{
 			StreamSubscribe: clientRequest.StreamSubscribe,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Stop individual cloud recording.
 //
+// @note Use StopRecording or StopVideoScreenshot to read the typed server response.
+//
 // @since v0.8.0
 //
 // @param ctx Context to control the request lifecycle.
@@ -226,15 +254,144 @@ func (i *IndividualRecording) Update(ctx context.Context, resourceId string, sid
 //   - true: Stop the recording asynchronously.
 //   - false: Stop the recording synchronously.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *StopResp. See api.StopResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (i *IndividualRecording) Stop(ctx context.Context, resourceId string, sid string, cname string, uid string, asyncStop bool) (*api.StopResp, error) {
+func (i *IndividualRecording) Stop(ctx context.Context, resourceId string, sid string, cname string, uid string, asyncStop bool, opts ...agora.CallOption) (*api.StopResp, error) {
 	return i.stopAPI.Do(ctx, resourceId, sid, api.IndividualMode, &api.StopReqBody{
 		Cname: cname,
 		Uid:   uid,
 		ClientRequest: &api.StopClientRequest{
 			AsyncStop: asyncStop,
 		},
-	})
+	}, opts...)
+}
+
+// @brief Stop individual cloud recording, and return the final file list.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be recorded.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param asyncStop Whether to stop the recording asynchronously.
+//   - true: Stop the recording asynchronously, the response carries no server response.
+//   - false: Stop the recording synchronously.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StopIndividualRecordingResp. See resp.StopIndividualRecordingResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (i *IndividualRecording) StopRecording(ctx context.Context, resourceId string, sid string, cname string, uid string,
+	asyncStop bool,
+	opts ...agora.CallOption,
+) (*resp.StopIndividualRecordingResp, error) {
+	respData, err := i.Stop(ctx, resourceId, sid, cname, uid, asyncStop, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var individualResp resp.StopIndividualRecordingResp
+
+	individualResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		individualResp.SuccessResponse = resp.StopIndividualRecordingSuccessResp{
+			Cname:          successResp.Cname,
+			UID:            successResp.UID,
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetIndividualRecordingServerResponse(),
+		}
+	}
+
+	return &individualResp, nil
+}
+
+// @brief Stop individual cloud recording that only captures video screenshots, and return the upload status.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be recorded.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param asyncStop Whether to stop the recording asynchronously.
+//   - true: Stop the recording asynchronously, the response carries no server response.
+//   - false: Stop the recording synchronously.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StopIndividualRecordingVideoScreenshotResp. See resp.StopIndividualRecordingVideoScreenshotResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (i *IndividualRecording) StopVideoScreenshot(ctx context.Context, resourceId string, sid string, cname string, uid string,
+	asyncStop bool,
+	opts ...agora.CallOption,
+) (*resp.StopIndividualRecordingVideoScreenshotResp, error) {
+	respData, err := i.Stop(ctx, resourceId, sid, cname, uid, asyncStop, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var individualResp resp.StopIndividualRecordingVideoScreenshotResp
+
+	individualResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		individualResp.SuccessResponse = resp.StopIndividualRecordingVideoScreenshotSuccessResp{
+			Cname:          successResp.Cname,
+			UID:            successResp.UID,
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetIndividualVideoScreenshotServerResponse(),
+		}
+	}
+
+	return &individualResp, nil
+}
+
+// @brief Query the status of individual cloud recording until predicate is satisfied.
+//
+// @since v0.13.0
+//
+// @example Use api.StatusIn(api.StatusInProgress) to wait until the recording is in progress after Start.
+//
+// @example Use api.StatusIn(api.StatusExited) with api.WaitOptions.DoneWhenNotFound to wait until the files are uploaded after an asynchronous Stop.
+//
+// @param ctx Context to control the wait.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param predicate Predicate of the awaited status. See api.WaitPredicate for details.
+//
+// @param opts Options of the wait, e.g. the backoff between two queries. See api.WaitOptions for details.
+//
+// @return Returns the last response *QueryResp. See api.QueryResp for details.
+//
+// @return Returns an error object. If the awaited status is not reached, the error object is not nil and contains error information.
+// See api.Query.WaitForStatus for details.
+func (i *IndividualRecording) WaitForStatus(ctx context.Context, resourceID string, sid string,
+	predicate api.WaitPredicate,
+	opts *api.WaitOptions,
+) (*api.QueryResp, error) {
+	return i.queryAPI.WaitForStatus(ctx, resourceID, sid, api.IndividualMode, predicate, opts)
 }
@@ -3,6 +3,7 @@ package scenario
 import (
 	"context"
 
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/req"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/resp"
@@ -49,11 +50,14 @@ func NewMixRecording(
 //
 // @param clientRequest The request body.See req.AcquireMixRecodingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *AcquireResp. See api.AcquireResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
 func (m *MixRecording) Acquire(ctx context.Context, cname string, uid string,
 	clientRequest *req.AcquireMixRecodingClientRequest,
+	opts ...agora.CallOption,
 ) (*api.AcquireResp, error) {
 	var startParameter *api.StartClientRequest
 	if clientRequest.StartParameter != nil {
@@ -75,13 +79,15 @@ func (m *MixRecording) Acquire(ctx context.Context, cname string, uid string,
 			RegionAffinity:      clientRequest.RegionAffinity,
 			StartParameter:      startParameter,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Start mix cloud recording.
 //
 // @since v0.8.0
 //
+// @note The request is validated before it is sent, see req.StartMixRecordingClientRequest.Validate.
+//
 // @param ctx Context to control the request lifecycle.
 //
 // @param resourceID The resource ID.
@@ -92,12 +98,19 @@ func (m *MixRecording) Acquire(ctx context.Context, cname string, uid string,
 //
 // @param clientRequest The request body.See req.StartMixRecordingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *StartResp. See api.StartResp for details.
 //
-// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
 func (m *MixRecording) Start(ctx context.Context, resourceId string, cname string, uid string,
 	clientRequest *req.StartMixRecordingClientRequest,
+	opts ...agora.CallOption,
 ) (*api.StartResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
 	return m.startAPI.Do(ctx, resourceId, api.MixMode, &api.StartReqBody{
 		Cname: cname,
 		Uid:   uid,
@@ -107,7 +120,7 @@ func (m *MixRecording) Start(ctx context.Context, resourceId string, cname strin
 			RecordingConfig:     clientRequest.RecordingConfig,
 			StorageConfig:       clientRequest.StorageConfig,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Query the status of mix cloud recording when the video file format is hls.
@@ -120,12 +133,15 @@ func (m *MixRecording) Start(ctx context.Context, resourceId string, cname strin
 //
 // @param sid The recording ID, identifying a recording cycle.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *QueryMixRecordingHLSResp. See resp.QueryMixRecordingHLSResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
 func (m *MixRecording) QueryHLS(ctx context.Context, resourceId string, sid string,
+	opts ...agora.CallOption,
 ) (*resp.QueryMixRecordingHLSResp, error) {
-	respData, err := m.queryAPI.Do(ctx, resourceId, sid, api.MixMode)
+	respData, err := m.queryAPI.Do(ctx, resourceId, sid, api.MixMode, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -155,12 +171,15 @@ func (m *MixRecording) QueryHLS(ctx context.Context, resourceId string, sid stri
 //
 // @param sid The recording ID, identifying a recording cycle.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *QueryMixRecordingHLSResp. See resp.QueryMixRecordingHLSResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
 func (m *MixRecording) QueryHLSAndMP4(ctx context.Context, resourceId string, sid string,
+	opts ...agora.CallOption,
 ) (*resp.QueryMixRecordingHLSAndMP4Resp, error) {
-	respData, err := m.queryAPI.Do(ctx, resourceId, sid, api.MixMode)
+	respData, err := m.queryAPI.Do(ctx, resourceId, sid, api.MixMode, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +203,8 @@ func (m *MixRecording) QueryHLSAndMP4(ctx context.Context, resourceId string, si
 //
 // @since v0.8.0
 //
+// @note The request is validated before it is sent, see req.UpdateMixRecordingClientRequest.Validate.
+//
 // @param ctx Context to control the request lifecycle.
 //
 // @param resourceID The resource ID.
@@ -196,25 +217,34 @@ func (m *MixRecording) QueryHLSAndMP4(ctx context.Context, resourceId string, si
 //
 // @param clientRequest The request body. See req.UpdateMixRecordingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *UpdateResp. See api.UpdateResp for details.
 //
-// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
 func (m *MixRecording) Update(ctx context.Context, resourceId string, sid string, cname string, uid string,
 	clientRequest *req.UpdateMixRecordingClientRequest,
+	opts ...agora.CallOption,
 ) (*api.UpdateResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
 	return m.updateAPI.Do(ctx, resourceId, sid, api.MixMode, &api.UpdateReqBody{
 		Cname: cname,
 		Uid:   uid,
 		ClientRequest: &api.UpdateClientRequest{
 			StreamSubscribe: clientRequest.StreamSubscribe,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Update the mix cloud recording layout.
 //
 // @since v0.8.0
 //
+// @note The request is validated before it is sent, see req.UpdateLayoutUpdateMixRecordingClientRequest.Validate.
+//
 // @param ctx Context to control the request lifecycle.
 //
 // @param resourceID The resource ID.
@@ -227,12 +257,19 @@ func (m *MixRecording) Update(ctx context.Context, resourceId string, sid string
 //
 // @param clientRequest The request body. See req.UpdateLayoutUpdateMixRecordingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *UpdateLayoutResp. See api.UpdateLayoutResp for details.
 //
-// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
 func (m *MixRecording) UpdateLayout(ctx context.Context, resourceId string, sid string, cname string, uid string,
 	clientRequest *req.UpdateLayoutUpdateMixRecordingClientRequest,
+	opts ...agora.CallOption,
 ) (*api.UpdateLayoutResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
 	return m.updateLayoutAPI.Do(ctx, resourceId, sid, api.MixMode, &api.UpdateLayoutReqBody{
 		Cname: cname,
 		Uid:   uid,
@@ -245,11 +282,13 @@ func (m *MixRecording) UpdateLayout(ctx context.Context, resourceId string, sid
 			LayoutConfig:               clientRequest.LayoutConfig,
 			BackgroundConfig:           clientRequest.BackgroundConfig,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Stop mix cloud recording.
 //
+// @note Use StopHLS or StopHLSAndMP4 to read the typed server response.
+//
 // @since v0.8.0
 //
 // @param ctx Context to control the request lifecycle.
@@ -266,11 +305,14 @@ func (m *MixRecording) UpdateLayout(ctx context.Context, resourceId string, sid
 //   - true: Stop the recording asynchronously.
 //   - false: Stop the recording synchronously.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *StopResp. See api.StopResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
 func (m *MixRecording) Stop(ctx context.Context, resourceId string, sid string, cname string, uid string,
 	asyncStop bool,
+	opts ...agora.CallOption,
 ) (*api.StopResp, error) {
 	return m.stopAPI.Do(ctx, resourceId, sid, api.MixMode, &api.StopReqBody{
 		Cname: cname,
@@ -278,5 +320,132 @@ func (m *MixRecording) Stop(ctx context.Context, resourceId string, sid string,
 		ClientRequest: &api.StopClientRequest{
 			AsyncStop: asyncStop,
 		},
-	})
+	}, opts...)
+}
+
+// @brief Stop mix cloud recording when the video file format is hls, and return the final file list.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be recorded.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param asyncStop Whether to stop the recording asynchronously.
+//   - true: Stop the recording asynchronously, the response carries no server response.
+//   - false: Stop the recording synchronously.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StopMixRecordingHLSResp. See resp.StopMixRecordingHLSResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (m *MixRecording) StopHLS(ctx context.Context, resourceId string, sid string, cname string, uid string,
+	asyncStop bool,
+	opts ...agora.CallOption,
+) (*resp.StopMixRecordingHLSResp, error) {
+	respData, err := m.Stop(ctx, resourceId, sid, cname, uid, asyncStop, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var mixResp resp.StopMixRecordingHLSResp
+
+	mixResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		mixResp.SuccessResponse = resp.StopMixRecordingHLSSuccessResp{
+			Cname:          successResp.Cname,
+			UID:            successResp.UID,
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetMixRecordingHLSServerResponse(),
+		}
+	}
+
+	return &mixResp, nil
+}
+
+// @brief Stop mix cloud recording when the video file format is hls and mp4, and return the final file list.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be recorded.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param asyncStop Whether to stop the recording asynchronously.
+//   - true: Stop the recording asynchronously, the response carries no server response.
+//   - false: Stop the recording synchronously.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StopMixRecordingHLSAndMP4Resp. See resp.StopMixRecordingHLSAndMP4Resp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (m *MixRecording) StopHLSAndMP4(ctx context.Context, resourceId string, sid string, cname string, uid string,
+	asyncStop bool,
+	opts ...agora.CallOption,
+) (*resp.StopMixRecordingHLSAndMP4Resp, error) {
+	respData, err := m.Stop(ctx, resourceId, sid, cname, uid, asyncStop, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var mixResp resp.StopMixRecordingHLSAndMP4Resp
+
+	mixResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		mixResp.SuccessResponse = resp.StopMixRecordingHLSAndMP4SuccessResp{
+			Cname:          successResp.Cname,
+			UID:            successResp.UID,
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetMixRecordingHLSAndMP4ServerResponse(),
+		}
+	}
+
+	return &mixResp, nil
+}
+
+// @brief Query the status of mix cloud recording until predicate is satisfied.
+//
+// @since v0.13.0
+//
+// @example Use api.StatusIn(api.StatusInProgress) to wait until the recording is in progress after Start.
+//
+// @example Use api.StatusIn(api.StatusExited) with api.WaitOptions.DoneWhenNotFound to wait until the files are uploaded after an asynchronous Stop.
+//
+// @param ctx Context to control the wait.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param predicate Predicate of the awaited status. See api.WaitPredicate for details.
+//
+// @param opts Options of the wait, e.g. the backoff between two queries. See api.WaitOptions for details.
+//
+// @return Returns the last response *QueryResp. See api.QueryResp for details.
+//
+// @return Returns an error object. If the awaited status is not reached, the error object is not nil and contains error information.
+// See api.Query.WaitForStatus for details.
+func (m *MixRecording) WaitForStatus(ctx context.Context, resourceID string, sid string,
+	predicate api.WaitPredicate,
+	opts *api.WaitOptions,
+) (*api.QueryResp, error) {
+	return m.queryAPI.WaitForStatus(ctx, resourceID, sid, api.MixMode, predicate, opts)
 }
@@ -0,0 +1,282 @@
+package scenario
+
+import (
+	"context"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/req"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/resp"
+)
+
+// @brief Video screenshot capture in individual mode, without recording audio or video files.
+//
+// @since v0.13.0
+type Snapshot struct {
+	acquireAPI *api.Acquire
+	startAPI   *api.Start
+	stopAPI    *api.Stop
+	queryAPI   *api.Query
+	updateAPI  *api.Update
+}
+
+func NewSnapshot(
+	acquireAPI *api.Acquire,
+	startAPI *api.Start,
+	stopAPI *api.Stop,
+	queryAPI *api.Query,
+	updateAPI *api.Update,
+) *Snapshot {
+	return &Snapshot{
+		acquireAPI: acquireAPI,
+		startAPI:   startAPI,
+		stopAPI:    stopAPI,
+		queryAPI:   queryAPI,
+		updateAPI:  updateAPI,
+	}
+}
+
+// @brief Get a resource ID for video screenshot capture.
+//
+// @since v0.13.0
+//
+// @post After receiving the resource ID, call the Start API to start capturing screenshots.
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param cname The name of the channel to be captured.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param clientRequest The request body. See req.AcquireSnapshotClientRequest for details.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *AcquireResp. See api.AcquireResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (s *Snapshot) Acquire(ctx context.Context, cname string, uid string,
+	clientRequest *req.AcquireSnapshotClientRequest,
+	opts ...agora.CallOption,
+) (*api.AcquireResp, error) {
+	var startParameter *api.StartClientRequest
+	if clientRequest.StartParameter != nil {
+		startParameter = &api.StartClientRequest{
+			Token:           clientRequest.StartParameter.Token,
+			StorageConfig:   clientRequest.StartParameter.StorageConfig,
+			RecordingConfig: clientRequest.StartParameter.RecordingConfig,
+			SnapshotConfig:  clientRequest.StartParameter.SnapshotConfig,
+		}
+	}
+
+	return s.acquireAPI.Do(ctx, &api.AcquireReqBody{
+		Cname: cname,
+		Uid:   uid,
+		ClientRequest: &api.AcquireClientRequest{
+			Scene:               0,
+			ResourceExpiredHour: clientRequest.ResourceExpiredHour,
+			ExcludeResourceIds:  clientRequest.ExcludeResourceIds,
+			RegionAffinity:      clientRequest.RegionAffinity,
+			StartParameter:      startParameter,
+		},
+	}, opts...)
+}
+
+// @brief Start video screenshot capture.
+//
+// @since v0.13.0
+//
+// @note The request is validated before it is sent, see req.StartSnapshotClientRequest.Validate.
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param cname Channel name.
+//
+// @param uid User ID.
+//
+// @param clientRequest The request body. See req.StartSnapshotClientRequest for details.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StartResp. See api.StartResp for details.
+//
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
+func (s *Snapshot) Start(ctx context.Context, resourceId string, cname string, uid string,
+	clientRequest *req.StartSnapshotClientRequest,
+	opts ...agora.CallOption,
+) (*api.StartResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.startAPI.Do(ctx, resourceId, api.IndividualMode, &api.StartReqBody{
+		Cname: cname,
+		Uid:   uid,
+		ClientRequest: &api.StartClientRequest{
+			Token:           clientRequest.Token,
+			RecordingConfig: clientRequest.RecordingConfig,
+			SnapshotConfig:  clientRequest.SnapshotConfig,
+			StorageConfig:   clientRequest.StorageConfig,
+		},
+	}, opts...)
+}
+
+// @brief Query the status of video screenshot capture.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *QuerySnapshotResp. See resp.QuerySnapshotResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (s *Snapshot) Query(ctx context.Context, resourceId string, sid string, opts ...agora.CallOption) (*resp.QuerySnapshotResp, error) {
+	respData, err := s.queryAPI.Do(ctx, resourceId, sid, api.IndividualMode, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshotResp resp.QuerySnapshotResp
+
+	snapshotResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		snapshotResp.SuccessResponse = resp.QuerySnapshotSuccessResp{
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetIndividualVideoScreenshotServerResponse(),
+		}
+	}
+
+	return &snapshotResp, nil
+}
+
+// @brief Update the subscribed video streams of video screenshot capture.
+//
+// @since v0.13.0
+//
+// @note The request is validated before it is sent, see req.UpdateSnapshotClientRequest.Validate.
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be captured.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param clientRequest The request body. See req.UpdateSnapshotClientRequest for details.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *UpdateResp. See api.UpdateResp for details.
+//
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
+func (s *Snapshot) Update(ctx context.Context, resourceId string, sid string, cname string, uid string,
+	clientRequest *req.UpdateSnapshotClientRequest,
+	opts ...agora.CallOption,
+) (*api.UpdateResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.updateAPI.Do(ctx, resourceId, sid, api.IndividualMode, &api.UpdateReqBody{
+		Cname: cname,
+		Uid:   uid,
+		ClientRequest: &api.UpdateClientRequest{
+			StreamSubscribe: clientRequest.StreamSubscribe,
+		},
+	}, opts...)
+}
+
+// @brief Stop video screenshot capture, and return the upload status.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be captured.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param asyncStop Whether to stop the capture asynchronously.
+//   - true: Stop the capture asynchronously, the response carries no server response.
+//   - false: Stop the capture synchronously.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StopSnapshotResp. See resp.StopSnapshotResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (s *Snapshot) Stop(ctx context.Context, resourceId string, sid string, cname string, uid string,
+	asyncStop bool,
+	opts ...agora.CallOption,
+) (*resp.StopSnapshotResp, error) {
+	respData, err := s.stopAPI.Do(ctx, resourceId, sid, api.IndividualMode, &api.StopReqBody{
+		Cname: cname,
+		Uid:   uid,
+		ClientRequest: &api.StopClientRequest{
+			AsyncStop: asyncStop,
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshotResp resp.StopSnapshotResp
+
+	snapshotResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		snapshotResp.SuccessResponse = resp.StopSnapshotSuccessResp{
+			Cname:          successResp.Cname,
+			UID:            successResp.UID,
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetIndividualVideoScreenshotServerResponse(),
+		}
+	}
+
+	return &snapshotResp, nil
+}
+
+// @brief Query the status of video screenshot capture until predicate is satisfied.
+//
+// @since v0.13.0
+//
+// @example Use api.StatusIn(api.StatusExited) with api.WaitOptions.DoneWhenNotFound to wait until the files are uploaded after an asynchronous Stop.
+//
+// @param ctx Context to control the wait.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param predicate Predicate of the awaited status. See api.WaitPredicate for details.
+//
+// @param opts Options of the wait, e.g. the backoff between two queries. See api.WaitOptions for details.
+//
+// @return Returns the last response *QueryResp. See api.QueryResp for details.
+//
+// @return Returns an error object. If the awaited status is not reached, the error object is not nil and contains error information.
+// See api.Query.WaitForStatus for details.
+func (s *Snapshot) WaitForStatus(ctx context.Context, resourceID string, sid string,
+	predicate api.WaitPredicate,
+	opts *api.WaitOptions,
+) (*api.QueryResp, error) {
+	return s.queryAPI.WaitForStatus(ctx, resourceID, sid, api.IndividualMode, predicate, opts)
+}
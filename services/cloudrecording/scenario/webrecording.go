@@ -3,6 +3,7 @@ package scenario
 import (
 	"context"
 
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/req"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/resp"
@@ -46,10 +47,12 @@ func NewWebRecording(
 //
 // @param clientRequest The request body. See req.AcquireWebRecodingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *AcquireResp. See api.AcquireResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (w *WebRecording) Acquire(ctx context.Context, cname string, uid string, clientRequest *req.AcquireWebRecodingClientRequest) (*api.AcquireResp, error) {
+func (w *WebRecording) Acquire(ctx context.Context, cname string, uid string, clientRequest *req.AcquireWebRecodingClientRequest, opts ...agora.CallOption) (*api.AcquireResp, error) {
 	var startParameter *api.StartClientRequest
 	if clientRequest.StartParameter != nil {
 		startParameter = &api.StartClientRequest{
@@ -69,13 +72,15 @@ func (w *WebRecording) Acquire(ctx context.Context, cname string, uid string, cl
 			RegionAffinity:      clientRequest.RegionAffinity,
 			StartParameter:      startParameter,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Start web recording.
 //
 // @since v0.8.0
 //
+// @note The request is validated before it is sent, see req.StartWebRecordingClientRequest.Validate.
+//
 // @param ctx Context to control the request lifecycle.
 //
 // @param resourceID The resource ID.
@@ -86,10 +91,16 @@ func (w *WebRecording) Acquire(ctx context.Context, cname string, uid string, cl
 //
 // @param clientRequest The request body. See req.StartWebRecordingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *StartResp. See api.StartResp for details.
 //
-// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (w *WebRecording) Start(ctx context.Context, resourceID string, cname string, uid string, clientRequest *req.StartWebRecordingClientRequest) (*api.StartResp, error) {
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
+func (w *WebRecording) Start(ctx context.Context, resourceID string, cname string, uid string, clientRequest *req.StartWebRecordingClientRequest, opts ...agora.CallOption) (*api.StartResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
 	return w.startAPI.Do(ctx, resourceID, api.WebMode, &api.StartReqBody{
 		Cname: cname,
 		Uid:   uid,
@@ -98,7 +109,7 @@ func (w *WebRecording) Start(ctx context.Context, resourceID string, cname strin
 			StorageConfig:          clientRequest.StorageConfig,
 			ExtensionServiceConfig: clientRequest.ExtensionServiceConfig,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Query the status of web recording.
@@ -111,11 +122,13 @@ func (w *WebRecording) Start(ctx context.Context, resourceID string, cname strin
 //
 // @param sid The recording ID, identifying a recording cycle.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *QueryWebRecordingResp. See resp.QueryWebRecordingResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (w *WebRecording) Query(ctx context.Context, resourceID string, sid string) (*resp.QueryWebRecordingResp, error) {
-	respData, err := w.queryAPI.Do(ctx, resourceID, sid, api.WebMode)
+func (w *WebRecording) Query(ctx context.Context, resourceID string, sid string, opts ...agora.CallOption) (*resp.QueryWebRecordingResp, error) {
+	respData, err := w.queryAPI.Do(ctx, resourceID, sid, api.WebMode, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -145,11 +158,13 @@ func (w *WebRecording) Query(ctx context.Context, resourceID string, sid string)
 //
 // @param sid The recording ID, identifying a recording cycle.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *QueryRtmpPublishResp. See resp.QueryRtmpPublishResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (w *WebRecording) QueryRtmpPublish(ctx context.Context, resourceID string, sid string) (*resp.QueryRtmpPublishResp, error) {
-	respData, err := w.queryAPI.Do(ctx, resourceID, sid, api.WebMode)
+func (w *WebRecording) QueryRtmpPublish(ctx context.Context, resourceID string, sid string, opts ...agora.CallOption) (*resp.QueryRtmpPublishResp, error) {
+	respData, err := w.queryAPI.Do(ctx, resourceID, sid, api.WebMode, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -173,6 +188,8 @@ func (w *WebRecording) QueryRtmpPublish(ctx context.Context, resourceID string,
 //
 // @since v0.8.0
 //
+// @note The request is validated before it is sent, see req.UpdateWebRecordingClientRequest.Validate.
+//
 // @param ctx Context to control the request lifecycle.
 //
 // @param resourceID The resource ID.
@@ -185,10 +202,16 @@ func (w *WebRecording) QueryRtmpPublish(ctx context.Context, resourceID string,
 //
 // @param clientRequest The request body. See req.UpdateWebRecordingClientRequest for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *UpdateResp. See api.UpdateResp for details.
 //
-// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (w *WebRecording) Update(ctx context.Context, resourceID string, sid string, cname string, uid string, clientRequest *req.UpdateWebRecordingClientRequest) (*api.UpdateResp, error) {
+// @return Returns an error object. If the request is invalid or fails, the error object is not nil and contains error information.
+func (w *WebRecording) Update(ctx context.Context, resourceID string, sid string, cname string, uid string, clientRequest *req.UpdateWebRecordingClientRequest, opts ...agora.CallOption) (*api.UpdateResp, error) {
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
 	return w.updateAPI.Do(ctx, resourceID, sid, api.WebMode, &api.UpdateReqBody{
 		Cname: cname,
 		Uid:   uid,
@@ -196,11 +219,13 @@ func (w *WebRecording) Update(ctx context.Context, resourceID string, sid string
 			WebRecordingConfig: clientRequest.WebRecordingConfig,
 			RtmpPublishConfig:  clientRequest.RtmpPublishConfig,
 		},
-	})
+	}, opts...)
 }
 
 // @brief Stop web recording.
 //
+// @note Use StopWebRecording to read the typed server response.
+//
 // @since v0.8.0
 //
 // @param ctx Context to control the request lifecycle.
@@ -217,15 +242,95 @@ func (w *WebRecording) Update(ctx context.Context, resourceID string, sid string
 //   - true: Stop the recording asynchronously.
 //   - false: Stop the recording synchronously.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *StopResp. See api.StopResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (w *WebRecording) Stop(ctx context.Context, resourceID string, sid string, cname string, uid string, asyncStop bool) (*api.StopResp, error) {
+func (w *WebRecording) Stop(ctx context.Context, resourceID string, sid string, cname string, uid string, asyncStop bool, opts ...agora.CallOption) (*api.StopResp, error) {
 	return w.stopAPI.Do(ctx, resourceID, sid, api.WebMode, &api.StopReqBody{
 		Cname: cname,
 		Uid:   uid,
 		ClientRequest: &api.StopClientRequest{
 			AsyncStop: asyncStop,
 		},
-	})
+	}, opts...)
+}
+
+// @brief Stop web page recording, and return the final file list.
+//
+// @since v0.13.0
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param cname The name of the channel to be recorded.
+//
+// @param uid The user ID used by the cloud recording service in the RTC channel to identify the recording service in the channel.
+//
+// @param asyncStop Whether to stop the recording asynchronously.
+//   - true: Stop the recording asynchronously, the response carries no server response.
+//   - false: Stop the recording synchronously.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StopWebRecordingResp. See resp.StopWebRecordingResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+func (w *WebRecording) StopWebRecording(ctx context.Context, resourceID string, sid string, cname string, uid string,
+	asyncStop bool,
+	opts ...agora.CallOption,
+) (*resp.StopWebRecordingResp, error) {
+	respData, err := w.Stop(ctx, resourceID, sid, cname, uid, asyncStop, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var webResp resp.StopWebRecordingResp
+
+	webResp.Response = respData.Response
+	if respData.IsSuccess() {
+		successResp := respData.SuccessResponse
+		webResp.SuccessResponse = resp.StopWebRecordingSuccessResp{
+			Cname:          successResp.Cname,
+			UID:            successResp.UID,
+			ResourceId:     successResp.ResourceId,
+			Sid:            successResp.Sid,
+			ServerResponse: successResp.GetWebRecordingServerResponse(),
+		}
+	}
+
+	return &webResp, nil
+}
+
+// @brief Query the status of web recording until predicate is satisfied.
+//
+// @since v0.13.0
+//
+// @example Use api.StatusIn(api.StatusInProgress) to wait until the recording is in progress after Start.
+//
+// @example Use api.StatusIn(api.StatusExited) with api.WaitOptions.DoneWhenNotFound to wait until the files are uploaded after an asynchronous Stop.
+//
+// @param ctx Context to control the wait.
+//
+// @param resourceID The resource ID.
+//
+// @param sid The recording ID, identifying a recording cycle.
+//
+// @param predicate Predicate of the awaited status. See api.WaitPredicate for details.
+//
+// @param opts Options of the wait, e.g. the backoff between two queries. See api.WaitOptions for details.
+//
+// @return Returns the last response *QueryResp. See api.QueryResp for details.
+//
+// @return Returns an error object. If the awaited status is not reached, the error object is not nil and contains error information.
+// See api.Query.WaitForStatus for details.
+func (w *WebRecording) WaitForStatus(ctx context.Context, resourceID string, sid string,
+	predicate api.WaitPredicate,
+	opts *api.WaitOptions,
+) (*api.QueryResp, error) {
+	return w.queryAPI.WaitForStatus(ctx, resourceID, sid, api.WebMode, predicate, opts)
 }
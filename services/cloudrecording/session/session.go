@@ -0,0 +1,509 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/errcode"
+)
+
+// @brief Cloud recording API used by sessions, implemented by cloudrecording.Client
+//
+// @since v0.13.0
+type API interface {
+	Acquire(ctx context.Context, payload *api.AcquireReqBody, opts ...agora.CallOption) (*api.AcquireResp, error)
+	Start(ctx context.Context, resourceID string, mode string, payload *api.StartReqBody, opts ...agora.CallOption) (*api.StartResp, error)
+	Stop(ctx context.Context, resourceID string, sid string, mode string, payload *api.StopReqBody, opts ...agora.CallOption) (*api.StopResp, error)
+	Query(ctx context.Context, resourceID string, sid string, mode string, opts ...agora.CallOption) (*api.QueryResp, error)
+	Update(ctx context.Context, resourceID string, sid string, mode string, payload *api.UpdateReqBody, opts ...agora.CallOption) (*api.UpdateResp, error)
+	UpdateLayout(ctx context.Context, resourceID string, sid string, mode string, payload *api.UpdateLayoutReqBody, opts ...agora.CallOption) (*api.UpdateLayoutResp, error)
+}
+
+// DefaultPollInterval is the interval of the status polling used when Spec.PollInterval is 0.
+const DefaultPollInterval = 10 * time.Second
+
+// transitionBufferSize is the capacity of the channel returned by Session.Transitions.
+const transitionBufferSize = 16
+
+// ErrSessionEnded is returned by the methods of a session that has ended, see Session.Done.
+//
+// @since v0.13.0
+var ErrSessionEnded = errors.New("session: the session has ended")
+
+// @brief Specification of a cloud recording session
+//
+// @since v0.13.0
+type Spec struct {
+	// Recording mode, one of api.IndividualMode, api.MixMode and api.WebMode
+	Mode string
+	// Name of the channel to be recorded
+	Cname string
+	// User ID used by the cloud recording service in the RTC channel to identify the recording service in the channel
+	Uid string
+	// Request body of the Acquire API.(Optional)
+	//
+	// By default, the scene is 1 in web mode and 0 otherwise.
+	Acquire *api.AcquireClientRequest
+	// Request body of the Start API
+	Start *api.StartClientRequest
+	// Interval of the background status polling.(Optional)
+	//
+	// The default value is DefaultPollInterval. The status is not polled when it is negative.
+	PollInterval time.Duration
+	// Callback invoked with every status transition of the session.(Optional)
+	//
+	// It is called synchronously in the order of the transitions, it must not block and must not call Stop.
+	OnTransition func(transition Transition)
+}
+
+// @brief Starts and tracks cloud recording sessions
+//
+// @since v0.13.0
+type Manager struct {
+	api API
+}
+
+// @brief Creates a session manager
+//
+// @param client Cloud recording API used by the sessions, e.g. a cloudrecording.Client.
+//
+// @since v0.13.0
+func NewManager(client API) *Manager {
+	return &Manager{api: client}
+}
+
+// @brief Acquires a resource, starts the recording and polls its status in the background
+//
+// @note A resource ID that expires before the recording starts is acquired again once.
+// The start request is validated before anything is sent, see api.StartClientRequest.Validate.
+//
+// @param ctx Context to control the Acquire and Start requests, the session outlives it. See Session.Close to stop the polling.
+//
+// @param spec Specification of the session. See Spec for details.
+//
+// @param opts Options of the Acquire and Start calls, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the session.
+//
+// @return Returns an error object. If the recording is not started, the error object is not nil and contains error information.
+//
+// @since v0.13.0
+func (m *Manager) StartSession(ctx context.Context, spec *Spec, opts ...agora.CallOption) (*Session, error) {
+	if spec == nil || spec.Start == nil {
+		return nil, errors.New("session: the start request is required")
+	}
+	switch spec.Mode {
+	case api.IndividualMode, api.MixMode, api.WebMode:
+	default:
+		return nil, fmt.Errorf("session: unknown mode %q", spec.Mode)
+	}
+	if err := spec.Start.Validate(); err != nil {
+		return nil, err
+	}
+
+	startResp, err := m.start(ctx, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		api:          m.api,
+		mode:         spec.Mode,
+		cname:        spec.Cname,
+		uid:          spec.Uid,
+		resourceID:   startResp.SuccessResponse.ResourceId,
+		sid:          startResp.SuccessResponse.Sid,
+		onTransition: spec.OnTransition,
+		transitions:  make(chan Transition, transitionBufferSize),
+		done:         make(chan struct{}),
+	}
+	s.pollCtx, s.stopPolling = context.WithCancel(context.Background())
+
+	interval := spec.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+	if interval > 0 {
+		go s.poll(interval)
+	}
+
+	return s, nil
+}
+
+func (m *Manager) start(ctx context.Context, spec *Spec, opts []agora.CallOption) (*api.StartResp, error) {
+	var startResp *api.StartResp
+	for attempt := 0; attempt < 2; attempt++ {
+		resourceID, err := m.acquire(ctx, spec, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		startResp, err = m.api.Start(ctx, resourceID, spec.Mode, &api.StartReqBody{
+			Cname:         spec.Cname,
+			Uid:           spec.Uid,
+			ClientRequest: spec.Start,
+		}, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if startResp.IsSuccess() {
+			return startResp, nil
+		}
+		if !errors.Is(startResp.Err(), agora.ErrResourceExpired) {
+			break
+		}
+	}
+
+	return nil, responseErr("start", startResp.Response)
+}
+
+func (m *Manager) acquire(ctx context.Context, spec *Spec, opts []agora.CallOption) (string, error) {
+	clientRequest := spec.Acquire
+	if clientRequest == nil {
+		clientRequest = &api.AcquireClientRequest{}
+		if spec.Mode == api.WebMode {
+			clientRequest.Scene = 1
+		}
+	}
+
+	acquireResp, err := m.api.Acquire(ctx, &api.AcquireReqBody{
+		Cname:         spec.Cname,
+		Uid:           spec.Uid,
+		ClientRequest: clientRequest,
+	}, opts...)
+	if err != nil {
+		return "", err
+	}
+	if !acquireResp.IsSuccess() {
+		return "", responseErr("acquire", acquireResp.Response)
+	}
+
+	return acquireResp.SuccessRes.ResourceId, nil
+}
+
+// responseErr returns the error of an unsuccessful response.
+func responseErr(operation string, resp api.Response) error {
+	if err := resp.Err(); err != nil {
+		return err
+	}
+	if resp.BaseResponse == nil {
+		return fmt.Errorf("session: %s failed", operation)
+	}
+	return fmt.Errorf("session: %s failed with http status code %d", operation, resp.HttpStatusCode)
+}
+
+// @brief Handle of a started cloud recording session
+//
+// @note The session ends when the Query API reports that the recording has exited, when the recording or its resource
+// no longer exists, or when Stop succeeds. Its status is no longer polled once it has ended or Close is called.
+//
+// @since v0.13.0
+type Session struct {
+	api          API
+	mode         string
+	cname        string
+	uid          string
+	resourceID   string
+	sid          string
+	onTransition func(transition Transition)
+
+	mu     sync.Mutex
+	status Status
+	ended  bool
+	err    error
+
+	// emitMu orders the delivery of transitions and guards the closing of transitions.
+	emitMu      sync.Mutex
+	transitions chan Transition
+	done        chan struct{}
+
+	// pollCtx is done once the session has ended or is closed, it stops the status polling and cancels its pending query.
+	pollCtx     context.Context
+	stopPolling context.CancelFunc
+}
+
+// @brief Returns the recording mode of the session
+//
+// @since v0.13.0
+func (s *Session) Mode() string {
+	return s.mode
+}
+
+// @brief Returns the name of the recorded channel
+//
+// @since v0.13.0
+func (s *Session) Cname() string {
+	return s.cname
+}
+
+// @brief Returns the user ID of the recording service in the channel
+//
+// @since v0.13.0
+func (s *Session) Uid() string {
+	return s.uid
+}
+
+// @brief Returns the resource ID of the session
+//
+// @since v0.13.0
+func (s *Session) ResourceID() string {
+	return s.resourceID
+}
+
+// @brief Returns the recording ID of the session
+//
+// @since v0.13.0
+func (s *Session) Sid() string {
+	return s.sid
+}
+
+// @brief Returns the last known status of the session
+//
+// @since v0.13.0
+func (s *Session) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// @brief Returns the error that ended the session, nil if the session is running or ended normally
+//
+// @since v0.13.0
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// @brief Returns the status transitions of the session
+//
+// @note The channel is closed when the session ends. Transitions are dropped while the buffer of the channel is full,
+// use Spec.OnTransition to observe every transition.
+//
+// @since v0.13.0
+func (s *Session) Transitions() <-chan Transition {
+	return s.transitions
+}
+
+// @brief Returns a channel that is closed when the session ends
+//
+// @since v0.13.0
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// @brief Queries the status of the session and records it
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *QueryResp. See api.QueryResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+//
+// @since v0.13.0
+func (s *Session) Query(ctx context.Context, opts ...agora.CallOption) (*api.QueryResp, error) {
+	resp, err := s.api.Query(ctx, s.resourceID, s.sid, s.mode, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsSuccess() {
+		if status, ok := resp.SuccessResponse.GetStatus(); ok {
+			s.transition(Status(status), resp, nil)
+		}
+		return resp, nil
+	}
+
+	if err := resp.Err(); errors.Is(err, agora.ErrNotFound) || errors.Is(err, agora.ErrResourceExpired) {
+		s.transition(StatusExited, nil, err)
+	}
+	return resp, nil
+}
+
+// @brief Updates the subscriptions or the web recording configuration of the session
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param clientRequest The request body. See api.UpdateClientRequest for details.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *UpdateResp. See api.UpdateResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+//
+// @since v0.13.0
+func (s *Session) Update(ctx context.Context, clientRequest *api.UpdateClientRequest, opts ...agora.CallOption) (*api.UpdateResp, error) {
+	if s.hasEnded() {
+		return nil, ErrSessionEnded
+	}
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.api.Update(ctx, s.resourceID, s.sid, s.mode, &api.UpdateReqBody{
+		Cname:         s.cname,
+		Uid:           s.uid,
+		ClientRequest: clientRequest,
+	}, opts...)
+}
+
+// @brief Updates the video layout of a mix mode session
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param clientRequest The request body. See api.UpdateLayoutClientRequest for details.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *UpdateLayoutResp. See api.UpdateLayoutResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+//
+// @since v0.13.0
+func (s *Session) UpdateLayout(ctx context.Context, clientRequest *api.UpdateLayoutClientRequest, opts ...agora.CallOption) (*api.UpdateLayoutResp, error) {
+	if s.mode != api.MixMode {
+		return nil, fmt.Errorf("session: the layout of a %s mode session cannot be updated", s.mode)
+	}
+	if s.hasEnded() {
+		return nil, ErrSessionEnded
+	}
+	if err := clientRequest.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.api.UpdateLayout(ctx, s.resourceID, s.sid, s.mode, &api.UpdateLayoutReqBody{
+		Cname:         s.cname,
+		Uid:           s.uid,
+		ClientRequest: clientRequest,
+	}, opts...)
+}
+
+// @brief Stops the recording and ends the session
+//
+// @note The session also ends when the recording has already been stopped or no longer exists.
+// It keeps running when the request fails, Stop can be called again.
+//
+// @param ctx Context to control the request lifecycle.
+//
+// @param async Whether the response is returned without waiting for the recorded files to be uploaded.
+//
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
+// @return Returns the response *StopResp. See api.StopResp for details.
+//
+// @return Returns an error object. If the request fails, the error object is not nil and contains error information.
+//
+// @since v0.13.0
+func (s *Session) Stop(ctx context.Context, async bool, opts ...agora.CallOption) (*api.StopResp, error) {
+	if s.hasEnded() {
+		return nil, ErrSessionEnded
+	}
+
+	resp, err := s.api.Stop(ctx, s.resourceID, s.sid, s.mode, &api.StopReqBody{
+		Cname: s.cname,
+		Uid:   s.uid,
+		ClientRequest: &api.StopClientRequest{
+			AsyncStop: async,
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.IsSuccess():
+		s.transition(StatusExited, nil, nil)
+	case errcode.Code(resp.ErrResponse.ErrorCode) == errcode.RepeatedStop:
+		s.transition(StatusExited, nil, nil)
+	case errors.Is(resp.Err(), agora.ErrNotFound) || errors.Is(resp.Err(), agora.ErrResourceExpired):
+		s.transition(StatusExited, nil, resp.Err())
+	}
+
+	return resp, nil
+}
+
+// @brief Stops the background status polling of the session
+//
+// @note The recording is not stopped and the session does not end, Query, Update and Stop can still be called.
+// Call Close when the session is no longer tracked, e.g. when the recording is left running, so that the polling goroutine exits.
+// Close can be called more than once, the polling also stops when the session ends.
+//
+// @since v0.13.0
+func (s *Session) Close() {
+	s.stopPolling()
+}
+
+func (s *Session) hasEnded() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ended
+}
+
+// transition records status and reports the transition to it, the session ends when status is terminal.
+func (s *Session) transition(status Status, resp *api.QueryResp, err error) {
+	s.emitMu.Lock()
+	defer s.emitMu.Unlock()
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	from := s.status
+	s.status = status
+	end := status.IsTerminal()
+	if end {
+		s.ended = true
+		s.err = err
+	}
+	s.mu.Unlock()
+
+	if from != status || end {
+		t := Transition{
+			From:          from,
+			To:            status,
+			At:            time.Now(),
+			QueryResponse: resp,
+			Err:           err,
+		}
+		if s.onTransition != nil {
+			s.onTransition(t)
+		}
+		select {
+		case s.transitions <- t:
+		default:
+		}
+	}
+
+	if end {
+		close(s.transitions)
+		close(s.done)
+		s.stopPolling()
+	}
+}
+
+func (s *Session) poll(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.pollCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(s.pollCtx, interval)
+		// Failed queries are ignored, the status is queried again at the next tick.
+		_, _ = s.Query(ctx)
+		cancel()
+	}
+}
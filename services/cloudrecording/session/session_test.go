@@ -0,0 +1,545 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+)
+
+const (
+	resourceExpiredBody = `{"code":433,"reason":"resource expired"}`
+	notFoundBody        = `{"code":404,"reason":"failed to find worker"}`
+	repeatedStopBody    = `{"code":49,"reason":"repeated stop"}`
+	stopBody            = `{"resourceId":"resource-1","sid":"sid-1","serverResponse":{"fileListMode":"json","fileList":[],"uploadingStatus":"uploaded"}}`
+)
+
+// fakeResponse is a response of fakeClient, err is returned instead of a response when it is not nil.
+type fakeResponse struct {
+	status int
+	body   string
+	err    error
+}
+
+func ok(body string) fakeResponse {
+	return fakeResponse{status: http.StatusOK, body: body}
+}
+
+func queryStatus(status Status) fakeResponse {
+	return ok(fmt.Sprintf(`{"resourceId":"resource-1","sid":"sid-1","serverResponse":{"status":%d}}`, status))
+}
+
+// fakeClient answers the requests of an operation, named after the last element of the request path,
+// with the responses queued for it in order, the last response is repeated. It fails the requests of
+// an operation without responses.
+type fakeClient struct {
+	client.Client
+
+	mu        sync.Mutex
+	responses map[string][]fakeResponse
+	paths     map[string][]string
+}
+
+func (f *fakeClient) DoRESTWithRetry(ctx context.Context, request *agora.Request) (*agora.BaseResponse, error) {
+	operation := path.Base(request.Path)
+
+	f.mu.Lock()
+	if f.paths == nil {
+		f.paths = make(map[string][]string)
+	}
+	n := len(f.paths[operation])
+	f.paths[operation] = append(f.paths[operation], request.Path)
+	responses := f.responses[operation]
+	f.mu.Unlock()
+
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("unexpected %s request", operation)
+	}
+	if n >= len(responses) {
+		n = len(responses) - 1
+	}
+	r := responses[n]
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// Mimic the client, which returns an unsuccessful response along with an agora.InternalErr
+	resp := &agora.BaseResponse{RawBody: []byte(r.body), HttpStatusCode: r.status}
+	if r.status == http.StatusOK {
+		return resp, nil
+	}
+	apiErr := agora.NewAPIError(resp, request.Module, 1)
+	if request.ErrorKind != nil {
+		apiErr.Kind = request.ErrorKind(apiErr)
+	}
+	resp.SetErr(apiErr)
+	return resp, agora.NewInternalErr(fmt.Sprintf("http status code is %d", r.status))
+}
+
+func (f *fakeClient) GetAppID() string {
+	return "appid"
+}
+
+// GetAppCertificate returns no App certificate, so that no token is minted.
+func (f *fakeClient) GetAppCertificate() string {
+	return ""
+}
+
+func (f *fakeClient) requests(operation string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.paths[operation]...)
+}
+
+// fakeAPI is the cloud recording API, backed by a fakeClient.
+type fakeAPI struct {
+	*fakeClient
+	acquire      *api.Acquire
+	start        *api.Start
+	stop         *api.Stop
+	query        *api.Query
+	update       *api.Update
+	updateLayout *api.UpdateLayout
+}
+
+var _ API = (*fakeAPI)(nil)
+
+func newFakeAPI(responses map[string][]fakeResponse) *fakeAPI {
+	c := &fakeClient{responses: responses}
+	prefixPath := "/v1/apps/appid/cloud_recording"
+	return &fakeAPI{
+		fakeClient:   c,
+		acquire:      api.NewAcquire("cloudRecording:acquire", log.DiscardLogger, c, prefixPath),
+		start:        api.NewStart("cloudRecording:start", log.DiscardLogger, c, prefixPath),
+		stop:         api.NewStop("cloudRecording:stop", log.DiscardLogger, c, prefixPath),
+		query:        api.NewQuery("cloudRecording:query", log.DiscardLogger, c, prefixPath),
+		update:       api.NewUpdate("cloudRecording:update", log.DiscardLogger, c, prefixPath),
+		updateLayout: api.NewUpdateLayout("cloudRecording:updateLayout", log.DiscardLogger, c, prefixPath),
+	}
+}
+
+func (f *fakeAPI) Acquire(ctx context.Context, payload *api.AcquireReqBody, opts ...agora.CallOption) (*api.AcquireResp, error) {
+	return f.acquire.Do(ctx, payload, opts...)
+}
+
+func (f *fakeAPI) Start(ctx context.Context, resourceID string, mode string, payload *api.StartReqBody, opts ...agora.CallOption) (*api.StartResp, error) {
+	return f.start.Do(ctx, resourceID, mode, payload, opts...)
+}
+
+func (f *fakeAPI) Stop(ctx context.Context, resourceID string, sid string, mode string, payload *api.StopReqBody, opts ...agora.CallOption) (*api.StopResp, error) {
+	return f.stop.Do(ctx, resourceID, sid, mode, payload, opts...)
+}
+
+func (f *fakeAPI) Query(ctx context.Context, resourceID string, sid string, mode string, opts ...agora.CallOption) (*api.QueryResp, error) {
+	return f.query.Do(ctx, resourceID, sid, mode, opts...)
+}
+
+func (f *fakeAPI) Update(ctx context.Context, resourceID string, sid string, mode string, payload *api.UpdateReqBody, opts ...agora.CallOption) (*api.UpdateResp, error) {
+	return f.update.Do(ctx, resourceID, sid, mode, payload, opts...)
+}
+
+func (f *fakeAPI) UpdateLayout(ctx context.Context, resourceID string, sid string, mode string, payload *api.UpdateLayoutReqBody, opts ...agora.CallOption) (*api.UpdateLayoutResp, error) {
+	return f.updateLayout.Do(ctx, resourceID, sid, mode, payload, opts...)
+}
+
+// startResponses returns the responses of a successful Acquire and Start, along with the given query responses.
+func startResponses(queries ...fakeResponse) map[string][]fakeResponse {
+	return map[string][]fakeResponse{
+		"acquire": {ok(`{"resourceId":"resource-1"}`)},
+		"start":   {ok(`{"resourceId":"resource-1","sid":"sid-1"}`)},
+		"query":   queries,
+	}
+}
+
+func newSpec(pollInterval time.Duration, onTransition func(transition Transition)) *Spec {
+	return &Spec{
+		Mode:  api.IndividualMode,
+		Cname: "channel",
+		Uid:   "1",
+		Start: &api.StartClientRequest{
+			RecordingConfig: &api.RecordingConfig{ChannelType: 1},
+		},
+		PollInterval: pollInterval,
+		OnTransition: onTransition,
+	}
+}
+
+// waitFor waits until cond is true, it fails the test after a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met after a second")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// isClosed reports whether the channel is closed, it must not have pending values.
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestStartSession(t *testing.T) {
+	errNetwork := errors.New("connection reset")
+	acquired := []fakeResponse{ok(`{"resourceId":"resource-1"}`), ok(`{"resourceId":"resource-2"}`)}
+	started := ok(`{"resourceId":"resource-2","sid":"sid-1"}`)
+	expired := fakeResponse{status: http.StatusBadRequest, body: resourceExpiredBody}
+
+	tests := []struct {
+		name      string
+		responses map[string][]fakeResponse
+		spec      *Spec
+		// Resource IDs the recording is started with
+		wantStarts []string
+		wantFail   bool
+		wantErr    error
+	}{
+		{
+			name:       "started",
+			responses:  map[string][]fakeResponse{"acquire": acquired, "start": {ok(`{"resourceId":"resource-1","sid":"sid-1"}`)}},
+			wantStarts: []string{"resource-1"},
+		},
+		{
+			name:       "resource expired once",
+			responses:  map[string][]fakeResponse{"acquire": acquired, "start": {expired, started}},
+			wantStarts: []string{"resource-1", "resource-2"},
+		},
+		{
+			name:       "resource expired twice",
+			responses:  map[string][]fakeResponse{"acquire": acquired, "start": {expired}},
+			wantStarts: []string{"resource-1", "resource-2"},
+			wantFail:   true,
+			wantErr:    agora.ErrResourceExpired,
+		},
+		{
+			name: "start failed",
+			responses: map[string][]fakeResponse{
+				"acquire": acquired,
+				"start":   {{status: http.StatusBadRequest, body: `{"code":2,"reason":"invalid parameter"}`}},
+			},
+			wantStarts: []string{"resource-1"},
+			wantFail:   true,
+		},
+		{
+			name:      "acquire failed",
+			responses: map[string][]fakeResponse{"acquire": {{err: errNetwork}}},
+			wantFail:  true,
+			wantErr:   errNetwork,
+		},
+		{
+			name: "invalid start request",
+			spec: &Spec{
+				Mode:  api.IndividualMode,
+				Start: &api.StartClientRequest{RecordingConfig: &api.RecordingConfig{ChannelType: 2}},
+			},
+			wantFail: true,
+		},
+		{name: "unknown mode", spec: &Spec{Mode: "unknown", Start: &api.StartClientRequest{}}, wantFail: true},
+		{name: "no start request", spec: &Spec{Mode: api.IndividualMode}, wantFail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeAPI(tt.responses)
+			spec := tt.spec
+			if spec == nil {
+				spec = newSpec(-1, nil)
+			}
+
+			s, err := NewManager(fake).StartSession(context.Background(), spec)
+
+			var starts []string
+			for _, p := range fake.requests("start") {
+				// .../resourceid/{resourceid}/mode/{mode}/start
+				starts = append(starts, path.Base(path.Dir(path.Dir(path.Dir(p)))))
+			}
+			if fmt.Sprint(starts) != fmt.Sprint(tt.wantStarts) {
+				t.Errorf("started with resource IDs %v, want %v", starts, tt.wantStarts)
+			}
+
+			if tt.wantFail {
+				if err == nil {
+					t.Fatalf("StartSession() = %+v, want an error", s)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("StartSession() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StartSession() error = %v", err)
+			}
+			defer s.Close()
+			if s.ResourceID() != tt.wantStarts[len(tt.wantStarts)-1] || s.Sid() != "sid-1" {
+				t.Errorf("session resource ID = %s, sid = %s, want %s and sid-1", s.ResourceID(), s.Sid(), tt.wantStarts[len(tt.wantStarts)-1])
+			}
+		})
+	}
+}
+
+func TestSessionPolling(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		changes []Transition
+	)
+	fake := newFakeAPI(startResponses(
+		queryStatus(StatusInitialized),
+		queryStatus(StatusInProgress),
+		queryStatus(StatusInProgress),
+		queryStatus(StatusExited),
+	))
+
+	s, err := NewManager(fake).StartSession(context.Background(), newSpec(time.Millisecond, func(transition Transition) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, transition)
+	}))
+	if err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+
+	var received []Transition
+	for transition := range s.Transitions() {
+		received = append(received, transition)
+	}
+
+	want := []Transition{
+		{From: StatusNotStarted, To: StatusInitialized},
+		{From: StatusInitialized, To: StatusInProgress},
+		{From: StatusInProgress, To: StatusExited},
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for name, got := range map[string][]Transition{"OnTransition": changes, "Transitions": received} {
+		if len(got) != len(want) {
+			t.Fatalf("%s got %+v, want %+v", name, got, want)
+		}
+		for i, transition := range got {
+			if transition.From != want[i].From || transition.To != want[i].To || transition.QueryResponse == nil || transition.Err != nil {
+				t.Errorf("%s transition %d = %+v, want %+v with the query response", name, i, transition, want[i])
+			}
+		}
+	}
+
+	if !isClosed(s.Done()) || !isClosed(s.pollCtx.Done()) {
+		t.Error("Done and the polling are not closed once the session has ended")
+	}
+	if s.Status() != StatusExited || s.Err() != nil {
+		t.Errorf("Status() = %s, Err() = %v, want exited and nil", s.Status(), s.Err())
+	}
+	// The status is no longer polled
+	queries := len(fake.requests("query"))
+	time.Sleep(10 * time.Millisecond)
+	if n := len(fake.requests("query")); n != queries {
+		t.Errorf("queries = %d after the session has ended, want %d", n, queries)
+	}
+}
+
+func TestSessionPollingNotFound(t *testing.T) {
+	fake := newFakeAPI(startResponses(queryStatus(StatusInProgress), fakeResponse{status: http.StatusNotFound, body: notFoundBody}))
+
+	s, err := NewManager(fake).StartSession(context.Background(), newSpec(time.Millisecond, nil))
+	if err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+
+	<-s.Done()
+	if s.Status() != StatusExited || !errors.Is(s.Err(), agora.ErrNotFound) {
+		t.Errorf("Status() = %s, Err() = %v, want exited and %v", s.Status(), s.Err(), agora.ErrNotFound)
+	}
+}
+
+func TestSessionTransitionOrder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		changes []Transition
+	)
+	statuses := []Status{StatusInitialized, StatusStarting, StatusPartiallyReady, StatusReady, StatusInProgress, StatusStopRequested, StatusStopped}
+	var queries []fakeResponse
+	for i := 0; i < 20; i++ {
+		for _, status := range statuses {
+			queries = append(queries, queryStatus(status))
+		}
+	}
+	fake := newFakeAPI(startResponses(queries...))
+
+	s, err := NewManager(fake).StartSession(context.Background(), newSpec(time.Millisecond, func(transition Transition) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, transition)
+	}))
+	if err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+	defer s.Close()
+
+	// Queries of several goroutines race with the polling
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := s.Query(context.Background()); err != nil {
+					t.Errorf("Query() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) == 0 {
+		t.Fatal("no transition reported")
+	}
+	from := StatusNotStarted
+	for i, transition := range changes {
+		if transition.From != from || transition.From == transition.To {
+			t.Fatalf("transition %d = %s to %s, want a transition from %s", i, transition.From, transition.To, from)
+		}
+		from = transition.To
+	}
+}
+
+func TestSessionStop(t *testing.T) {
+	errNetwork := errors.New("connection reset")
+	tests := []struct {
+		name     string
+		stop     fakeResponse
+		wantEnd  bool
+		wantErr  error
+		wantCall error
+	}{
+		{name: "stopped", stop: ok(stopBody), wantEnd: true},
+		{name: "repeated stop", stop: fakeResponse{status: http.StatusBadRequest, body: repeatedStopBody}, wantEnd: true},
+		{name: "not found", stop: fakeResponse{status: http.StatusNotFound, body: notFoundBody}, wantEnd: true, wantErr: agora.ErrNotFound},
+		{
+			name:    "resource expired",
+			stop:    fakeResponse{status: http.StatusBadRequest, body: resourceExpiredBody},
+			wantEnd: true,
+			wantErr: agora.ErrResourceExpired,
+		},
+		{name: "failed", stop: fakeResponse{status: http.StatusBadRequest, body: `{"code":2,"reason":"invalid parameter"}`}},
+		{name: "network error", stop: fakeResponse{err: errNetwork}, wantCall: errNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := startResponses()
+			responses["stop"] = []fakeResponse{tt.stop}
+			fake := newFakeAPI(responses)
+			var changes []Transition
+			s, err := NewManager(fake).StartSession(context.Background(), newSpec(-1, func(transition Transition) {
+				changes = append(changes, transition)
+			}))
+			if err != nil {
+				t.Fatalf("StartSession() error = %v", err)
+			}
+			defer s.Close()
+
+			resp, err := s.Stop(context.Background(), false)
+			if tt.wantCall != nil {
+				if !errors.Is(err, tt.wantCall) {
+					t.Errorf("Stop() error = %v, want %v", err, tt.wantCall)
+				}
+			} else if err != nil || resp == nil {
+				t.Fatalf("Stop() = %+v, %v, want a response", resp, err)
+			}
+
+			if ended := isClosed(s.Done()); ended != tt.wantEnd {
+				t.Fatalf("session ended = %v, want %v", ended, tt.wantEnd)
+			}
+			if !tt.wantEnd {
+				if len(changes) != 0 {
+					t.Errorf("transitions = %+v, want none", changes)
+				}
+				// Stop can be called again
+				fake.mu.Lock()
+				fake.responses["stop"] = []fakeResponse{ok(stopBody)}
+				fake.mu.Unlock()
+				if _, err = s.Stop(context.Background(), false); err != nil || !isClosed(s.Done()) {
+					t.Errorf("second Stop() error = %v, want the session to end", err)
+				}
+				return
+			}
+
+			if s.Status() != StatusExited || !errors.Is(s.Err(), tt.wantErr) || (tt.wantErr == nil && s.Err() != nil) {
+				t.Errorf("Status() = %s, Err() = %v, want exited and %v", s.Status(), s.Err(), tt.wantErr)
+			}
+			if len(changes) != 1 || changes[0].To != StatusExited || changes[0].QueryResponse != nil {
+				t.Errorf("transitions = %+v, want a single transition to exited", changes)
+			}
+			if _, ok := <-s.Transitions(); !ok {
+				t.Error("the transition to exited is not delivered")
+			}
+			if _, ok := <-s.Transitions(); ok {
+				t.Error("Transitions is not closed")
+			}
+			if !isClosed(s.pollCtx.Done()) {
+				t.Error("the polling is not stopped")
+			}
+
+			// An ended session cannot be stopped or updated
+			if _, err = s.Stop(context.Background(), false); !errors.Is(err, ErrSessionEnded) {
+				t.Errorf("Stop() error = %v, want %v", err, ErrSessionEnded)
+			}
+			if _, err = s.Update(context.Background(), &api.UpdateClientRequest{}); !errors.Is(err, ErrSessionEnded) {
+				t.Errorf("Update() error = %v, want %v", err, ErrSessionEnded)
+			}
+			if n := len(fake.requests("stop")); n != 1 {
+				t.Errorf("stop requests = %d, want 1", n)
+			}
+		})
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	fake := newFakeAPI(startResponses(queryStatus(StatusInProgress)))
+	fake.responses["stop"] = []fakeResponse{ok(stopBody)}
+
+	s, err := NewManager(fake).StartSession(context.Background(), newSpec(time.Millisecond, nil))
+	if err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+	waitFor(t, func() bool {
+		return s.Status() == StatusInProgress
+	})
+
+	s.Close()
+	s.Close()
+	if !isClosed(s.pollCtx.Done()) {
+		t.Fatal("the polling is not stopped")
+	}
+	// A query may have been pending
+	time.Sleep(5 * time.Millisecond)
+	queries := len(fake.requests("query"))
+	time.Sleep(10 * time.Millisecond)
+	if n := len(fake.requests("query")); n != queries {
+		t.Errorf("queries = %d after Close, want %d", n, queries)
+	}
+
+	// The session is still running
+	if isClosed(s.Done()) {
+		t.Fatal("Close ended the session")
+	}
+	if _, err = s.Stop(context.Background(), false); err != nil || !isClosed(s.Done()) {
+		t.Errorf("Stop() error = %v, want the session to end", err)
+	}
+}
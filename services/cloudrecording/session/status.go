@@ -0,0 +1,81 @@
+package session
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudrecording/api"
+)
+
+// @brief Status of the cloud recording service, as reported by the Query API
+//
+// @since v0.13.0
+type Status int
+
+const (
+	// The cloud service has not started
+	StatusNotStarted Status = 0
+	// The cloud service initialization is complete
+	StatusInitialized Status = 1
+	// The cloud service components are starting
+	StatusStarting Status = 2
+	// Some cloud service components are ready
+	StatusPartiallyReady Status = 3
+	// All cloud service components are ready
+	StatusReady Status = 4
+	// The cloud service is in progress
+	StatusInProgress Status = 5
+	// The cloud service receives the request to stop
+	StatusStopRequested Status = 6
+	// All components of the cloud service stop
+	StatusStopped Status = 7
+	// The cloud service exits
+	StatusExited Status = 8
+	// The cloud service exits abnormally
+	StatusExitedAbnormally Status = 20
+)
+
+var statusNames = map[Status]string{
+	StatusNotStarted:       "not started",
+	StatusInitialized:      "initialized",
+	StatusStarting:         "starting",
+	StatusPartiallyReady:   "partially ready",
+	StatusReady:            "ready",
+	StatusInProgress:       "in progress",
+	StatusStopRequested:    "stop requested",
+	StatusStopped:          "stopped",
+	StatusExited:           "exited",
+	StatusExitedAbnormally: "exited abnormally",
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return "status " + strconv.Itoa(int(s))
+}
+
+// @brief Reports whether the cloud recording service has exited, normally or abnormally
+//
+// @since v0.13.0
+func (s Status) IsTerminal() bool {
+	return s == StatusExited || s == StatusExitedAbnormally
+}
+
+// @brief Transition of the status of a session
+//
+// @since v0.13.0
+type Transition struct {
+	// Status before the transition
+	From Status
+	// Status after the transition
+	To Status
+	// Time the transition was observed
+	At time.Time
+	// Response of the Query API that reported the transition.(Optional)
+	//
+	// It is nil when the transition is caused by Stop or by an error.
+	QueryResponse *api.QueryResp
+	// Error that ended the session, e.g. an error matching agora.ErrResourceExpired.(Optional)
+	Err error
+}
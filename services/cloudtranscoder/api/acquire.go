@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -14,12 +13,11 @@ type Acquire struct {
 	baseHandler
 }
 
-func NewAcquire(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Acquire {
+func NewAcquire(module string, logger log.Logger, client client.Client, prefixPath string) *Acquire {
 	return &Acquire{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -57,34 +55,25 @@ type AcquireSuccessResp struct {
 	TokenName string `json:"tokenName"`
 }
 
-func (a *Acquire) Do(ctx context.Context, payload *AcquireReqBody) (*AcquireResp, error) {
+func (a *Acquire) Do(ctx context.Context, payload *AcquireReqBody, opts ...agora.CallOption) (*AcquireResp, error) {
 	path := a.buildPath()
 
-	responseData, err := doRESTWithRetry(ctx, a.module, a.logger, a.retryCount, a.client, path, http.MethodPost, payload)
+	result, err := agora.Call[AcquireReqBody, AcquireSuccessResp, ErrResponse](ctx, a.client, &agora.Request{
+		Module: a.module,
+		Method: http.MethodPost,
+		Path:   path,
+		// A duplicated acquire only leaves an unused builder token
+		Idempotent: true,
+		Options:    opts,
+	}, payload)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var resp AcquireResp
-
-	resp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse AcquireSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		resp.SuccessResp = successResponse
-	} else {
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		resp.ErrResponse = errResponse
-	}
+	resp.BaseResponse = result.Response
+	resp.SuccessResp = result.SuccessRes
+	resp.ErrResponse = result.ErrResponse
 
 	return &resp, nil
 }
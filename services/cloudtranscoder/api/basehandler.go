@@ -9,6 +9,5 @@ type baseHandler struct {
 	module     string
 	logger     log.Logger
 	client     client.Client
-	retryCount int
 	prefixPath string // /v1/projects/{appid}/rtsc/cloud-transcoder
 }
@@ -2,22 +2,22 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/token"
 )
 
 type Create struct {
 	baseHandler
 }
 
-func NewCreate(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Create {
+func NewCreate(module string, logger log.Logger, client client.Client, prefixPath string) *Create {
 	return &Create{
 		baseHandler: baseHandler{
-			module: module, logger: logger, retryCount: retryCount, client: client, prefixPath: prefixPath,
+			module: module, logger: logger, client: client, prefixPath: prefixPath,
 		},
 	}
 }
@@ -274,33 +274,89 @@ type CreateResp struct {
 	SuccessResp CreateSuccessResp
 }
 
-func (c *Create) Do(ctx context.Context, tokenName string, payload *CreateReqBody) (*CreateResp, error) {
-	path := c.buildPath(tokenName)
-	responseData, err := doRESTWithRetry(ctx, c.module, c.logger, c.retryCount, c.client, path, http.MethodPost, payload)
-	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
+// withTokens returns a copy of payload in which the RTC inputs and outputs without a token carry a token minted with the App certificate.
+//
+// Input tokens are minted for uid 0, output tokens for the uid of the output.
+// The payload is returned as it is when the App certificate is not configured.
+func (c *Create) withTokens(payload *CreateReqBody) (*CreateReqBody, error) {
+	appCertificate := c.client.GetAppCertificate()
+	if appCertificate == "" || payload == nil || payload.Services == nil || payload.Services.CloudTranscoder == nil ||
+		payload.Services.CloudTranscoder.Config == nil || payload.Services.CloudTranscoder.Config.Transcoder == nil {
+		return payload, nil
+	}
+
+	mint := func(rtc *CloudTranscoderRtc, uid int, role token.Role) (*CloudTranscoderRtc, error) {
+		if rtc == nil || rtc.RtcToken != "" {
+			return rtc, nil
+		}
+		rtcToken, err := token.BuildRtcTokenWithUID(c.client.GetAppID(), appCertificate, rtc.RtcChannel, uint32(uid),
+			role, token.DefaultExpire, token.DefaultExpire)
+		if err != nil {
 			return nil, err
 		}
+		withToken := *rtc
+		withToken.RtcToken = rtcToken
+		return &withToken, nil
 	}
 
-	var resp CreateResp
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse CreateSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
+	var err error
+	transcoder := *payload.Services.CloudTranscoder.Config.Transcoder
+	transcoder.AudioInputs = append([]CloudTranscoderAudioInput(nil), transcoder.AudioInputs...)
+	for i := range transcoder.AudioInputs {
+		if transcoder.AudioInputs[i].Rtc, err = mint(transcoder.AudioInputs[i].Rtc, 0, token.RoleSubscriber); err != nil {
+			return nil, err
+		}
+	}
+	transcoder.VideoInputs = append([]CloudTranscoderVideoInput(nil), transcoder.VideoInputs...)
+	for i := range transcoder.VideoInputs {
+		if transcoder.VideoInputs[i].Rtc, err = mint(transcoder.VideoInputs[i].Rtc, 0, token.RoleSubscriber); err != nil {
 			return nil, err
 		}
-		resp.SuccessResp = successResponse
-	} else {
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
+	}
+	transcoder.Outputs = append([]CloudTranscoderOutput(nil), transcoder.Outputs...)
+	for i := range transcoder.Outputs {
+		rtc := transcoder.Outputs[i].Rtc
+		if rtc == nil {
+			continue
+		}
+		if transcoder.Outputs[i].Rtc, err = mint(rtc, rtc.RtcUID, token.RolePublisher); err != nil {
 			return nil, err
 		}
-		resp.ErrResponse = errResponse
 	}
 
-	resp.BaseResponse = responseData
+	config := *payload.Services.CloudTranscoder.Config
+	config.Transcoder = &transcoder
+	cloudTranscoder := *payload.Services.CloudTranscoder
+	cloudTranscoder.Config = &config
+	services := *payload.Services
+	services.CloudTranscoder = &cloudTranscoder
+
+	return &CreateReqBody{Services: &services}, nil
+}
+
+func (c *Create) Do(ctx context.Context, tokenName string, payload *CreateReqBody, opts ...agora.CallOption) (*CreateResp, error) {
+	path := c.buildPath(tokenName)
+
+	payload, err := c.withTokens(payload)
+	if err != nil {
+		return nil, err
+	}
+	result, err := agora.Call[CreateReqBody, CreateSuccessResp, ErrResponse](ctx, c.client, &agora.Request{
+		Module: c.module,
+		Method: http.MethodPost,
+		Path:   path,
+		// Create starts a billable transcoding task
+		Idempotent: false,
+		Options:    opts,
+	}, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CreateResp
+	resp.BaseResponse = result.Response
+	resp.SuccessResp = result.SuccessRes
+	resp.ErrResponse = result.ErrResponse
 
 	return &resp, nil
 }
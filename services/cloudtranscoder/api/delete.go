@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -14,12 +13,11 @@ type Delete struct {
 	baseHandler
 }
 
-func NewDelete(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Delete {
+func NewDelete(module string, logger log.Logger, client client.Client, prefixPath string) *Delete {
 	return &Delete{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -69,33 +67,23 @@ type DeleteSuccessResp struct {
 	Status string `json:"status"`
 }
 
-func (d *Delete) Do(ctx context.Context, taskId string, tokenName string) (*DeleteResp, error) {
+func (d *Delete) Do(ctx context.Context, taskId string, tokenName string, opts ...agora.CallOption) (*DeleteResp, error) {
 	path := d.buildPath(taskId, tokenName)
-	responseData, err := doRESTWithRetry(ctx, d.module, d.logger, d.retryCount, d.client, path, http.MethodDelete, nil)
+	result, err := agora.Call[agora.NoBody, DeleteSuccessResp, ErrResponse](ctx, d.client, &agora.Request{
+		Module:     d.module,
+		Method:     http.MethodDelete,
+		Path:       path,
+		Idempotent: true,
+		Options:    opts,
+	}, nil)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var resp DeleteResp
-
-	resp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse DeleteSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		resp.SuccessResp = successResponse
-	} else {
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		resp.ErrResponse = errResponse
-	}
+	resp.BaseResponse = result.Response
+	resp.SuccessResp = result.SuccessRes
+	resp.ErrResponse = result.ErrResponse
 
 	return &resp, nil
 }
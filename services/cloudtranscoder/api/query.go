@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -14,10 +13,10 @@ type Query struct {
 	baseHandler
 }
 
-func NewQuery(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Query {
+func NewQuery(module string, logger log.Logger, client client.Client, prefixPath string) *Query {
 	return &Query{
 		baseHandler: baseHandler{
-			module: module, logger: logger, retryCount: retryCount, client: client, prefixPath: prefixPath,
+			module: module, logger: logger, client: client, prefixPath: prefixPath,
 		},
 	}
 }
@@ -65,33 +64,23 @@ type QuerySuccessResp struct {
 	Status string `json:"status"`
 }
 
-func (q *Query) Do(ctx context.Context, taskId string, tokenName string) (*QueryResp, error) {
+func (q *Query) Do(ctx context.Context, taskId string, tokenName string, opts ...agora.CallOption) (*QueryResp, error) {
 	path := q.buildPath(taskId, tokenName)
-	responseData, err := doRESTWithRetry(ctx, q.module, q.logger, q.retryCount, q.client, path, http.MethodGet, nil)
+	result, err := agora.Call[agora.NoBody, QuerySuccessResp, ErrResponse](ctx, q.client, &agora.Request{
+		Module:     q.module,
+		Method:     http.MethodGet,
+		Path:       path,
+		Idempotent: true,
+		Options:    opts,
+	}, nil)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var resp QueryResp
-
-	resp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse QuerySuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		resp.SuccessRes = successResponse
-	} else {
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		resp.ErrResponse = errResponse
-	}
+	resp.BaseResponse = result.Response
+	resp.SuccessRes = result.SuccessRes
+	resp.ErrResponse = result.ErrResponse
 
 	return &resp, nil
 }
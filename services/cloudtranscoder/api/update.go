@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -15,10 +14,10 @@ type Update struct {
 	baseHandler
 }
 
-func NewUpdate(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Update {
+func NewUpdate(module string, logger log.Logger, client client.Client, prefixPath string) *Update {
 	return &Update{
 		baseHandler: baseHandler{
-			module: module, logger: logger, retryCount: retryCount, client: client, prefixPath: prefixPath,
+			module: module, logger: logger, client: client, prefixPath: prefixPath,
 		},
 	}
 }
@@ -40,30 +39,23 @@ type UpdateResp struct {
 	Response
 }
 
-func (u *Update) Do(ctx context.Context, taskId string, tokenName string, sequenceId uint, updateMask string, payload *UpdateReqBody) (*UpdateResp, error) {
+func (u *Update) Do(ctx context.Context, taskId string, tokenName string, sequenceId uint, updateMask string, payload *UpdateReqBody, opts ...agora.CallOption) (*UpdateResp, error) {
 	path := u.buildPath(taskId, tokenName, sequenceId, updateMask)
 
-	responseData, err := doRESTWithRetry(ctx, u.module, u.logger, u.retryCount, u.client, path, http.MethodPatch, payload)
+	result, err := agora.Call[UpdateReqBody, agora.NoBody, ErrResponse](ctx, u.client, &agora.Request{
+		Module:     u.module,
+		Method:     http.MethodPatch,
+		Path:       path,
+		Idempotent: true,
+		Options:    opts,
+	}, payload)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var resp UpdateResp
-
-	resp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		return &resp, nil
-	} else {
-		var errResponse ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		resp.ErrResponse = errResponse
-	}
+	resp.BaseResponse = result.Response
+	resp.ErrResponse = result.ErrResponse
 
 	return &resp, nil
 }
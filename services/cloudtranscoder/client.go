@@ -4,13 +4,19 @@ import (
 	"context"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/breaker"
 	agoraClient "github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/domain"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/limit"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/cloudtranscoder/api"
 )
 
 const projectName = "rtsc/cloud-transcoder"
 
 type Client struct {
+	client agoraClient.Client
+
 	acquireAPI *api.Acquire
 	createAPI  *api.Create
 	queryAPI   *api.Query
@@ -18,42 +24,84 @@ type Client struct {
 	updateAPI  *api.Update
 }
 
+// Deprecated: Use agora.Config.RetryPolicy, or agora.MaxAttempts for a single call, instead.
+//
+// RetryCount is the maximum number of attempts used when agora.Config.RetryPolicy is nil, see retry.PolicyOrDefault.
 var RetryCount = 3
 
-func NewClient(config *agora.Config) (*Client, error) {
+func NewClient(config *agora.Config, options ...agoraClient.Option) (*Client, error) {
 	prefixPath := "/v1/projects/" + config.AppID + "/" + projectName
-	c, err := agoraClient.New(config)
+
+	cfg := *config
+	cfg.RetryPolicy = retry.PolicyOrDefault(cfg.RetryPolicy, RetryCount)
+	c, err := agoraClient.New(&cfg, options...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
-		acquireAPI: api.NewAcquire("cloudTranscoder:acquire", config.Logger, RetryCount, c, prefixPath),
-		createAPI:  api.NewCreate("cloudTranscoder:create", config.Logger, RetryCount, c, prefixPath),
-		queryAPI:   api.NewQuery("cloudTranscoder:query", config.Logger, RetryCount, c, prefixPath),
-		deleteAPI:  api.NewDelete("cloudTranscoder:delete", config.Logger, RetryCount, c, prefixPath),
-		updateAPI:  api.NewUpdate("cloudTranscoder:update", config.Logger, RetryCount, c, prefixPath),
+		client:     c,
+		acquireAPI: api.NewAcquire("cloudTranscoder:acquire", cfg.Logger, c, prefixPath),
+		createAPI:  api.NewCreate("cloudTranscoder:create", cfg.Logger, c, prefixPath),
+		queryAPI:   api.NewQuery("cloudTranscoder:query", cfg.Logger, c, prefixPath),
+		deleteAPI:  api.NewDelete("cloudTranscoder:delete", cfg.Logger, c, prefixPath),
+		updateAPI:  api.NewUpdate("cloudTranscoder:update", cfg.Logger, c, prefixPath),
 	}, nil
 }
 
-func (a *Client) Acquire(ctx context.Context, payload *api.AcquireReqBody) (*api.AcquireResp, error) {
-	return a.acquireAPI.Do(ctx, payload)
+func (a *Client) Acquire(ctx context.Context, payload *api.AcquireReqBody, opts ...agora.CallOption) (*api.AcquireResp, error) {
+	return a.acquireAPI.Do(ctx, payload, opts...)
 }
 
-func (a *Client) Create(ctx context.Context, tokenName string, payload *api.CreateReqBody) (*api.CreateResp, error) {
-	return a.createAPI.Do(ctx, tokenName, payload)
+func (a *Client) Create(ctx context.Context, tokenName string, payload *api.CreateReqBody, opts ...agora.CallOption) (*api.CreateResp, error) {
+	return a.createAPI.Do(ctx, tokenName, payload, opts...)
 }
 
-func (a *Client) Query(ctx context.Context, taskId string, tokenName string) (*api.QueryResp, error) {
-	return a.queryAPI.Do(ctx, taskId, tokenName)
+func (a *Client) Query(ctx context.Context, taskId string, tokenName string, opts ...agora.CallOption) (*api.QueryResp, error) {
+	return a.queryAPI.Do(ctx, taskId, tokenName, opts...)
 }
 
-func (a *Client) Delete(ctx context.Context, taskId string, tokenName string) (*api.DeleteResp, error) {
-	return a.deleteAPI.Do(ctx, taskId, tokenName)
+func (a *Client) Delete(ctx context.Context, taskId string, tokenName string, opts ...agora.CallOption) (*api.DeleteResp, error) {
+	return a.deleteAPI.Do(ctx, taskId, tokenName, opts...)
 }
 
 func (a *Client) Update(ctx context.Context, taskId string, tokenName string, sequenceId uint, updateMask string,
 	payload *api.UpdateReqBody,
+	opts ...agora.CallOption,
 ) (*api.UpdateResp, error) {
-	return a.updateAPI.Do(ctx, taskId, tokenName, sequenceId, updateMask, payload)
+	return a.updateAPI.Do(ctx, taskId, tokenName, sequenceId, updateMask, payload, opts...)
+}
+
+// @brief Returns the endpoint health state of the domain pool, e.g. for dashboards
+//
+// @return Returns the snapshot. See domain.PoolSnapshot for details.
+//
+// @since v0.13.0
+func (a *Client) DomainPoolSnapshot() domain.PoolSnapshot {
+	return a.client.GetDomainPoolSnapshot()
+}
+
+// @brief Stops the background domain refresh of the client, see Config.DomainRefreshInterval
+//
+// @note The client must not be used after it is closed.
+//
+// @since v0.13.0
+func (a *Client) Close() error {
+	return a.client.Close()
+}
+
+// @brief Returns the statistics of the rate and concurrency limiters of the client, e.g. the time requests waited
+//
+// @return Returns the statistics. See limit.GroupStats for details.
+//
+// @since v0.13.0
+func (a *Client) LimiterStats() limit.GroupStats {
+	return a.client.GetLimiterStats()
+}
+
+// @brief Returns the state of the circuit breaker of every endpoint host used so far, keyed by the host
+//
+// @since v0.13.0
+func (a *Client) CircuitBreakerStates() map[string]breaker.State {
+	return a.client.GetCircuitBreakerStates()
 }
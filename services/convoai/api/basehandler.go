@@ -9,6 +9,5 @@ type baseHandler struct {
 	module     string
 	logger     log.Logger
 	client     client.Client
-	retryCount int
 	prefixPath string // /api/conversational-ai-agent/v2/projects/{appid}
 }
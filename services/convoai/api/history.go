@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -15,12 +14,11 @@ type History struct {
 	baseHandler
 }
 
-func NewHistory(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *History {
+func NewHistory(module string, logger log.Logger, client client.Client, prefixPath string) *History {
 	return &History{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -33,33 +31,23 @@ func (h *History) buildPath(agentId string) string {
 	return h.prefixPath + "/agents/" + agentId + "/history"
 }
 
-func (h *History) Do(ctx context.Context, agentId string) (*resp.HistoryResp, error) {
+func (h *History) Do(ctx context.Context, agentId string, opts ...agora.CallOption) (*resp.HistoryResp, error) {
 	path := h.buildPath(agentId)
-	responseData, err := doRESTWithRetry(ctx, h.module, h.logger, h.retryCount, h.client, path, http.MethodGet, nil)
+	result, err := agora.Call[agora.NoBody, resp.HistorySuccessResp, resp.ErrResponse](ctx, h.client, &agora.Request{
+		Module:     h.module,
+		Method:     http.MethodGet,
+		Path:       path,
+		Idempotent: true,
+		Options:    opts,
+	}, nil)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var historyResp resp.HistoryResp
-
-	historyResp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse resp.HistorySuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		historyResp.SuccessRes = successResponse
-	} else {
-		var errResponse resp.ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		historyResp.ErrResponse = errResponse
-	}
+	historyResp.BaseResponse = result.Response
+	historyResp.SuccessRes = result.SuccessRes
+	historyResp.ErrResponse = result.ErrResponse
 
 	return &historyResp, nil
 }
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -15,12 +14,11 @@ type Interrupt struct {
 	baseHandler
 }
 
-func NewInterrupt(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Interrupt {
+func NewInterrupt(module string, logger log.Logger, client client.Client, prefixPath string) *Interrupt {
 	return &Interrupt{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -34,33 +32,23 @@ func (i *Interrupt) buildPath(agentId string) string {
 	return i.prefixPath + "/agents/" + agentId + "/interrupt"
 }
 
-func (i *Interrupt) Do(ctx context.Context, agentId string) (*resp.InterruptResp, error) {
+func (i *Interrupt) Do(ctx context.Context, agentId string, opts ...agora.CallOption) (*resp.InterruptResp, error) {
 	path := i.buildPath(agentId)
-	responseData, err := doRESTWithRetry(ctx, i.module, i.logger, i.retryCount, i.client, path, http.MethodPost, map[string]any{})
+	result, err := agora.Call[agora.NoBody, resp.InterruptSuccessResp, resp.ErrResponse](ctx, i.client, &agora.Request{
+		Module:     i.module,
+		Method:     http.MethodPost,
+		Path:       path,
+		Idempotent: true,
+		Options:    opts,
+	}, &agora.NoBody{})
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var interruptResp resp.InterruptResp
-
-	interruptResp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse resp.InterruptSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		interruptResp.SuccessRes = successResponse
-	} else {
-		var errResponse resp.ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		interruptResp.ErrResponse = errResponse
-	}
+	interruptResp.BaseResponse = result.Response
+	interruptResp.SuccessRes = result.SuccessRes
+	interruptResp.ErrResponse = result.ErrResponse
 
 	return &interruptResp, nil
 }
@@ -2,29 +2,38 @@ package api
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/token"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/convoai/req"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/convoai/resp"
 )
 
+// joinReconcileClockSkew is the tolerated clock skew when looking up agents started by a previous Join attempt
+const joinReconcileClockSkew = 5 * time.Second
+
+// listModule is the module name of the List calls made to reconcile a Join, the same as the one of the List API
+const listModule = "convoai:list"
+
 type Join struct {
 	baseHandler
+	list *List
 }
 
-func NewJoin(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Join {
+func NewJoin(module string, logger log.Logger, client client.Client, prefixPath string) *Join {
 	return &Join{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
+		list: NewList(listModule, logger, client, prefixPath),
 	}
 }
 
@@ -35,37 +44,103 @@ func (d *Join) buildPath() string {
 	return d.prefixPath + "/join"
 }
 
-func (d *Join) Do(ctx context.Context, name string, propertiesBody *req.JoinPropertiesReqBody) (*resp.JoinResp, error) {
+// reconcile checks whether a previous Join attempt may have started an agent in the channel since fromTime.
+//
+// The List API returns neither the agent names nor the agent RTC uids, so an agent started by the previous attempt
+// cannot be told apart from another one started in the channel at the same time. The Join is only retried when
+// no agent that is not stopped or failed was started in the channel since fromTime, otherwise an error is returned
+// and the Join is not retried.
+func (d *Join) reconcile(ctx context.Context, channel string, fromTime int) (bool, error) {
+	listResp, err := d.list.Do(ctx, req.WithChannel(channel), req.WithFromTime(fromTime))
+	if err != nil {
+		return false, err
+	}
+	if !listResp.IsSuccess() {
+		return false, fmt.Errorf("list agents failed,http status code:%d", listResp.HttpStatusCode)
+	}
+
+	for _, agent := range listResp.SuccessRes.Data.List {
+		if agent.StartTs < int64(fromTime) {
+			continue
+		}
+		switch agent.Status {
+		case "STOPPING", "STOPPED", "FAILED":
+			continue
+		}
+		return false, fmt.Errorf("agent %s started in channel %s may have been started by a previous attempt", agent.AgentId, channel)
+	}
+	return false, nil
+}
+
+// withToken returns a copy of propertiesBody carrying a token minted with the App certificate when propertiesBody has no token.
+//
+// The token also grants RTM login when advanced_features.enable_rtm is true.
+// The propertiesBody is returned as it is when the App certificate is not configured.
+func (d *Join) withToken(propertiesBody *req.JoinPropertiesReqBody) (*req.JoinPropertiesReqBody, error) {
+	appCertificate := d.client.GetAppCertificate()
+	if appCertificate == "" || propertiesBody == nil || propertiesBody.Token != "" {
+		return propertiesBody, nil
+	}
+
+	var (
+		agentToken string
+		err        error
+	)
+	advancedFeatures := propertiesBody.AdvancedFeatures
+	if advancedFeatures != nil && advancedFeatures.EnableRtm != nil && *advancedFeatures.EnableRtm {
+		agentToken, err = token.BuildRtcRtmToken(d.client.GetAppID(), appCertificate, propertiesBody.Channel, propertiesBody.AgentRtcUId,
+			token.RolePublisher, token.DefaultExpire, token.DefaultExpire)
+	} else {
+		agentToken, err = token.BuildRtcToken(d.client.GetAppID(), appCertificate, propertiesBody.Channel, propertiesBody.AgentRtcUId,
+			token.RolePublisher, token.DefaultExpire, token.DefaultExpire)
+	}
+	if err != nil {
+		return nil, err
+	}
+	withToken := *propertiesBody
+	withToken.Token = agentToken
+
+	return &withToken, nil
+}
+
+func (d *Join) Do(ctx context.Context, name string, propertiesBody *req.JoinPropertiesReqBody, opts ...agora.CallOption) (*resp.JoinResp, error) {
 	path := d.buildPath()
+
+	propertiesBody, err := d.withToken(propertiesBody)
+	if err != nil {
+		return nil, err
+	}
 	request := map[string]any{
 		"name":       name,
 		"properties": propertiesBody,
 	}
-	responseData, err := doRESTWithRetry(ctx, d.module, d.logger, d.retryCount, d.client, path, http.MethodPost, request)
-	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
+
+	var reconcile agora.ReconcileFunc
+	if propertiesBody != nil && propertiesBody.Channel != "" {
+		// Allow for clock skew between the client and the server
+		fromTime := int(time.Now().Add(-joinReconcileClockSkew).Unix())
+		reconcile = func(ctx context.Context) (bool, error) {
+			return d.reconcile(ctx, propertiesBody.Channel, fromTime)
 		}
 	}
+	result, err := agora.Call[map[string]any, resp.JoinSuccessResp, resp.ErrResponse](ctx, d.client, &agora.Request{
+		Module: d.module,
+		Method: http.MethodPost,
+		Path:   path,
+		// Join starts a billable agent, it is only retried when the agent list shows
+		// that no agent was started in the channel since the first attempt
+		Idempotent: false,
+		Reconcile:  reconcile,
+		Options:    opts,
+	}, &request)
+	if err != nil {
+		return nil, err
+	}
 
 	var joinResp resp.JoinResp
-
-	joinResp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse resp.JoinSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		joinResp.SuccessResp = successResponse
-	} else {
-		var errResponse resp.ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		joinResp.ErrResponse = errResponse
-	}
+	joinResp.BaseResponse = result.Response
+	joinResp.SuccessResp = result.SuccessRes
+	joinResp.ErrResponse = result.ErrResponse
 
 	return &joinResp, nil
 }
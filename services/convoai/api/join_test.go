@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/auth"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
+	"github.com/AgoraIO-Community/agora-rest-client-go/services/convoai/req"
+)
+
+const testPrefixPath = "/api/conversational-ai-agent/v2/projects/appid"
+
+// joinServer stubs the Join and List APIs, the first Join request times out.
+type joinServer struct {
+	// Body of the List responses
+	listBody string
+	// Closed to end the timed-out Join request
+	release chan struct{}
+
+	locker    sync.Mutex
+	joins     int
+	listQuery []string
+}
+
+func (s *joinServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch r.URL.Path {
+	case testPrefixPath + "/join":
+		s.locker.Lock()
+		s.joins++
+		joins := s.joins
+		s.locker.Unlock()
+		if joins == 1 {
+			// The agent may have been started, but the client gives up before the response
+			select {
+			case <-r.Context().Done():
+			case <-s.release:
+			}
+			return
+		}
+		_, _ = w.Write([]byte(`{"agent_id":"agent-` + strconv.Itoa(joins) + `","create_ts":1735035893,"status":"RUNNING"}`))
+	case testPrefixPath + "/agents":
+		s.locker.Lock()
+		s.listQuery = append(s.listQuery, r.URL.RawQuery)
+		s.locker.Unlock()
+		_, _ = w.Write([]byte(s.listBody))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestJoin(t *testing.T, handler http.Handler) *Join {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := client.New(&agora.Config{
+		AppID:      "appid",
+		BaseURL:    server.URL,
+		Credential: auth.NewBasicAuthCredential("customer", "secret"),
+		Logger:     log.DiscardLogger,
+		RetryPolicy: &retry.Policy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("client.New() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	return NewJoin("convoai:join", log.DiscardLogger, c, testPrefixPath)
+}
+
+func TestJoinReconcile(t *testing.T) {
+	now := time.Now().Unix()
+	tests := []struct {
+		name        string
+		listBody    string
+		channel     string
+		wantAgentId string
+		wantJoins   int
+		wantLists   int
+	}{
+		{
+			name:        "no agent started, retried",
+			listBody:    `{"data":{"count":0,"list":[]},"meta":{"total":0},"status":"ok"}`,
+			channel:     "channel",
+			wantAgentId: "agent-2",
+			wantJoins:   2,
+			wantLists:   1,
+		},
+		{
+			name: "only earlier or stopped agents, retried",
+			listBody: `{"data":{"count":2,"list":[` +
+				`{"start_ts":` + strconv.FormatInt(now-3600, 10) + `,"status":"RUNNING","agent_id":"earlier"},` +
+				`{"start_ts":` + strconv.FormatInt(now, 10) + `,"status":"STOPPED","agent_id":"stopped"}` +
+				`]},"meta":{"total":2},"status":"ok"}`,
+			channel:     "channel",
+			wantAgentId: "agent-2",
+			wantJoins:   2,
+			wantLists:   1,
+		},
+		{
+			name: "agent started in the channel, not retried",
+			listBody: `{"data":{"count":1,"list":[` +
+				`{"start_ts":` + strconv.FormatInt(now, 10) + `,"status":"STARTING","agent_id":"started"}` +
+				`]},"meta":{"total":1},"status":"ok"}`,
+			channel:   "channel",
+			wantJoins: 1,
+			wantLists: 1,
+		},
+		{
+			name:      "list failed, not retried",
+			listBody:  `not json`,
+			channel:   "channel",
+			wantJoins: 1,
+			wantLists: 1,
+		},
+		{
+			name:      "no channel to list, not retried",
+			channel:   "",
+			wantJoins: 1,
+			wantLists: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &joinServer{listBody: tt.listBody, release: make(chan struct{})}
+			join := newTestJoin(t, server)
+			// Runs before the server is closed
+			t.Cleanup(func() {
+				close(server.release)
+			})
+
+			joinResp, err := join.Do(context.Background(), "agent", &req.JoinPropertiesReqBody{
+				Channel:     tt.channel,
+				AgentRtcUId: "1001",
+			}, agora.AttemptTimeout(100*time.Millisecond))
+
+			if tt.wantAgentId != "" {
+				if err != nil {
+					t.Fatalf("Do() error = %v", err)
+				}
+				if joinResp.SuccessResp.AgentId != tt.wantAgentId {
+					t.Errorf("agent id = %s, want %s", joinResp.SuccessResp.AgentId, tt.wantAgentId)
+				}
+			} else if err == nil {
+				t.Fatalf("Do() = %+v, want the error of the timed-out attempt", joinResp)
+			}
+
+			server.locker.Lock()
+			defer server.locker.Unlock()
+			if server.joins != tt.wantJoins || len(server.listQuery) != tt.wantLists {
+				t.Errorf("join requests = %d, list requests = %d, want %d and %d", server.joins, len(server.listQuery), tt.wantJoins, tt.wantLists)
+			}
+			for _, query := range server.listQuery {
+				values, _ := url.ParseQuery(query)
+				fromTime, _ := strconv.ParseInt(values.Get("from_time"), 10, 64)
+				if values.Get("channel") != tt.channel || fromTime > now || fromTime < now-int64(joinReconcileClockSkew/time.Second)-1 {
+					t.Errorf("list query = %s, want the channel %s and a from_time at most %s before the Join", query, tt.channel, joinReconcileClockSkew)
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -16,12 +15,11 @@ type Leave struct {
 }
 
 // NewLeave Creates a new Leave instance
-func NewLeave(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Leave {
+func NewLeave(module string, logger log.Logger, client client.Client, prefixPath string) *Leave {
 	return &Leave{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -34,27 +32,22 @@ func (d *Leave) buildPath(agentId string) string {
 	return d.prefixPath + "/agents/" + agentId + "/leave"
 }
 
-func (d *Leave) Do(ctx context.Context, agentId string) (*resp.LeaveResp, error) {
+func (d *Leave) Do(ctx context.Context, agentId string, opts ...agora.CallOption) (*resp.LeaveResp, error) {
 	path := d.buildPath(agentId)
-	responseData, err := doRESTWithRetry(ctx, d.module, d.logger, d.retryCount, d.client, path, http.MethodPost, nil)
+	result, err := agora.Call[agora.NoBody, agora.NoBody, resp.ErrResponse](ctx, d.client, &agora.Request{
+		Module:     d.module,
+		Method:     http.MethodPost,
+		Path:       path,
+		Idempotent: true,
+		Options:    opts,
+	}, nil)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var response resp.LeaveResp
-
-	response.BaseResponse = responseData
-
-	if responseData.HttpStatusCode != http.StatusOK {
-		var errResponse resp.ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		response.ErrResponse = errResponse
-	}
+	response.BaseResponse = result.Response
+	response.ErrResponse = result.ErrResponse
 
 	return &response, nil
 }
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -18,12 +17,11 @@ type List struct {
 	baseHandler
 }
 
-func NewList(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *List {
+func NewList(module string, logger log.Logger, client client.Client, prefixPath string) *List {
 	return &List{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -83,33 +81,27 @@ func buildQueryFields(options ...req.ListOption) map[string]any {
 }
 
 func (l *List) Do(ctx context.Context, options ...req.ListOption) (*resp.ListResp, error) {
+	listOptions := req.ListOptions{}
+	for _, option := range options {
+		option(&listOptions)
+	}
 	queryFields := buildQueryFields(options...)
 	path := l.buildPath(queryFields)
-	responseData, err := doRESTWithRetry(ctx, l.module, l.logger, l.retryCount, l.client, path, http.MethodGet, nil)
+	result, err := agora.Call[agora.NoBody, resp.ListSuccessResp, resp.ErrResponse](ctx, l.client, &agora.Request{
+		Module:     l.module,
+		Method:     http.MethodGet,
+		Path:       path,
+		Idempotent: true,
+		Options:    listOptions.CallOptions,
+	}, nil)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var listResp resp.ListResp
-
-	listResp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse resp.ListSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		listResp.SuccessRes = successResponse
-	} else {
-		var errResponse resp.ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		listResp.ErrResponse = errResponse
-	}
+	listResp.BaseResponse = result.Response
+	listResp.SuccessRes = result.SuccessRes
+	listResp.ErrResponse = result.ErrResponse
 
 	return &listResp, nil
 }
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -15,12 +14,11 @@ type Query struct {
 	baseHandler
 }
 
-func NewQuery(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Query {
+func NewQuery(module string, logger log.Logger, client client.Client, prefixPath string) *Query {
 	return &Query{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -33,33 +31,23 @@ func (q *Query) buildPath(agentId string) string {
 	return q.prefixPath + "/agents/" + agentId
 }
 
-func (q *Query) Do(ctx context.Context, agentId string) (*resp.QueryResp, error) {
+func (q *Query) Do(ctx context.Context, agentId string, opts ...agora.CallOption) (*resp.QueryResp, error) {
 	path := q.buildPath(agentId)
-	responseData, err := doRESTWithRetry(ctx, q.module, q.logger, q.retryCount, q.client, path, http.MethodGet, nil)
+	result, err := agora.Call[agora.NoBody, resp.QuerySuccessResp, resp.ErrResponse](ctx, q.client, &agora.Request{
+		Module:     q.module,
+		Method:     http.MethodGet,
+		Path:       path,
+		Idempotent: true,
+		Options:    opts,
+	}, nil)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var queryResp resp.QueryResp
-
-	queryResp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse resp.QuerySuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		queryResp.SuccessRes = successResponse
-	} else {
-		var errResponse resp.ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		queryResp.ErrResponse = errResponse
-	}
+	queryResp.BaseResponse = result.Response
+	queryResp.SuccessRes = result.SuccessRes
+	queryResp.ErrResponse = result.ErrResponse
 
 	return &queryResp, nil
 }
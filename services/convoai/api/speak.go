@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -16,12 +15,11 @@ type Speak struct {
 	baseHandler
 }
 
-func NewSpeak(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Speak {
+func NewSpeak(module string, logger log.Logger, client client.Client, prefixPath string) *Speak {
 	return &Speak{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -35,33 +33,24 @@ func (s *Speak) buildPath(agentId string) string {
 	return s.prefixPath + "/agents/" + agentId + "/speak"
 }
 
-func (s *Speak) Do(ctx context.Context, agentId string, body *req.SpeakBody) (*resp.SpeakResp, error) {
+func (s *Speak) Do(ctx context.Context, agentId string, body *req.SpeakBody, opts ...agora.CallOption) (*resp.SpeakResp, error) {
 	path := s.buildPath(agentId)
-	responseData, err := doRESTWithRetry(ctx, s.module, s.logger, s.retryCount, s.client, path, http.MethodPost, body)
+	result, err := agora.Call[req.SpeakBody, resp.SpeakSuccessResp, resp.ErrResponse](ctx, s.client, &agora.Request{
+		Module: s.module,
+		Method: http.MethodPost,
+		Path:   path,
+		// A duplicated request makes the agent speak twice
+		Idempotent: false,
+		Options:    opts,
+	}, body)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var speakResp resp.SpeakResp
-
-	speakResp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse resp.SpeakSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		speakResp.SuccessRes = successResponse
-	} else {
-		var errResponse resp.ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		speakResp.ErrResponse = errResponse
-	}
+	speakResp.BaseResponse = result.Response
+	speakResp.SuccessRes = result.SuccessRes
+	speakResp.ErrResponse = result.ErrResponse
 
 	return &speakResp, nil
 }
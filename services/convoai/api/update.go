@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"net/http"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
@@ -16,12 +15,11 @@ type Update struct {
 	baseHandler
 }
 
-func NewUpdate(module string, logger log.Logger, retryCount int, client client.Client, prefixPath string) *Update {
+func NewUpdate(module string, logger log.Logger, client client.Client, prefixPath string) *Update {
 	return &Update{
 		baseHandler: baseHandler{
 			module:     module,
 			logger:     logger,
-			retryCount: retryCount,
 			client:     client,
 			prefixPath: prefixPath,
 		},
@@ -34,35 +32,24 @@ func (u *Update) buildPath(agentId string) string {
 	return u.prefixPath + "/agents/" + agentId + "/update"
 }
 
-func (u *Update) Do(ctx context.Context, agentId string, payload *req.UpdateReqBody) (*resp.UpdateResp, error) {
+func (u *Update) Do(ctx context.Context, agentId string, payload *req.UpdateReqBody, opts ...agora.CallOption) (*resp.UpdateResp, error) {
 	path := u.buildPath(agentId)
 
-	responseData, err := doRESTWithRetry(ctx, u.module, u.logger, u.retryCount, u.client, path, http.MethodPost, payload)
+	result, err := agora.Call[req.UpdateReqBody, resp.UpdateSuccessResp, resp.ErrResponse](ctx, u.client, &agora.Request{
+		Module:     u.module,
+		Method:     http.MethodPost,
+		Path:       path,
+		Idempotent: true,
+		Options:    opts,
+	}, payload)
 	if err != nil {
-		var internalErr *agora.InternalErr
-		if !errors.As(err, &internalErr) {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	var updateResp resp.UpdateResp
-
-	updateResp.BaseResponse = responseData
-
-	if responseData.HttpStatusCode == http.StatusOK {
-		var successResponse resp.UpdateSuccessResp
-		if err = responseData.UnmarshalToTarget(&successResponse); err != nil {
-			return nil, err
-		}
-		updateResp.SuccessResp = successResponse
-		return &updateResp, nil
-	} else {
-		var errResponse resp.ErrResponse
-		if err = responseData.UnmarshalToTarget(&errResponse); err != nil {
-			return nil, err
-		}
-		updateResp.ErrResponse = errResponse
-	}
+	updateResp.BaseResponse = result.Response
+	updateResp.SuccessResp = result.SuccessRes
+	updateResp.ErrResponse = result.ErrResponse
 
 	return &updateResp, nil
 }
@@ -3,13 +3,17 @@ package convoai
 import (
 	"context"
 	"errors"
+	"net/http"
 	"time"
 
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/auth"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/breaker"
 	agoraClient "github.com/AgoraIO-Community/agora-rest-client-go/agora/client"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/domain"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/limit"
 	"github.com/AgoraIO-Community/agora-rest-client-go/agora/log"
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora/retry"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/convoai/api"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/convoai/req"
 	"github.com/AgoraIO-Community/agora-rest-client-go/services/convoai/resp"
@@ -18,6 +22,8 @@ import (
 const projectName = "conversational-ai-agent"
 
 type Client struct {
+	client agoraClient.Client
+
 	joinAPI      *api.Join
 	leaveAPI     *api.Leave
 	listAPI      *api.List
@@ -49,29 +55,122 @@ const (
 type Config struct {
 	// Agora AppID
 	AppID string
-	// Timeout for HTTP requests
+	// App certificate of the project.(Optional)
+	//
+	// When it is set, an RTC or RTM token is minted for the requests that need one but are given none, see the token package.
+	AppCertificate string
+	// Timeout for each HTTP attempt of a call without a deadline. The default value is 10 seconds.
+	//
+	// Pass a context with a deadline, or use agora.WithCallTimeout and agora.WithAttemptTimeout,
+	// to control the timeouts of a single call.
 	HttpTimeout time.Duration
+	// HTTP client used to send requests.(Optional)
+	//
+	// Set it to use your own proxy, mTLS, CA bundle or connection pool settings.
+	// The client is copied, HttpTimeout and Transport are ignored when it is set.
+	HttpClient *http.Client
+	// Transport used by the default HTTP client.(Optional)
+	//
+	// Only takes effect when HttpClient is nil. The default value is http.DefaultTransport.
+	Transport http.RoundTripper
+	// Middlewares applied to every HTTP request.(Optional)
+	//
+	// The first middleware is the outermost one. See agora.Middleware for details.
+	Middlewares []agora.Middleware
+	// Maximum size of a response body in bytes.(Optional)
+	//
+	// A larger response fails the call with an agora.ErrResponseTooLarge. The size is not limited when it is 0.
+	MaxResponseBodySize int64
+	// Whether to request gzip compressed responses and decompress them.(Optional)
+	//
+	// MaxResponseBodySize applies to the decompressed body.
+	EnableGzip bool
+	// Whether to drop agora.BaseResponse.RawBody once the response is decoded, to reduce memory usage of callers that
+	// keep many responses, e.g. when polling Query.(Optional)
+	DiscardRawBody bool
+	// Hooks run around every HTTP attempt, with metadata about the service operation.(Optional)
+	//
+	// See agora.Hook for details.
+	Hooks []agora.Hook
+	// Retry policy for failed requests.(Optional)
+	//
+	// When it is nil, retry.DefaultPolicy() is used with RetryCount as the maximum number of attempts.
+	// See retry.Policy for details.
+	RetryPolicy *retry.Policy
+
+	// Client-side rate and concurrency limits of the requests, e.g. to stay below the QPS limit of the AppID.(Optional)
+	//
+	// Nothing is limited by default. See limit.Policy for details.
+	RateLimit *limit.Policy
+	// Instrumentation that observes every API call and HTTP attempt, e.g. for tracing and metrics.(Optional)
+	//
+	// See agora.Instrumentation for details.
+	Instrumentation agora.Instrumentation
 	// Credential for accessing the Agora service.
 	//
 	// Available credential types:
 	//
 	//  - BasicAuthCredential: See auth.NewBasicAuthCredential for details
+	//
+	//  - TokenCredential: See auth.NewTokenCredential for details
 	Credential auth.Credential
+	// Provider of the credential used by each request, it takes precedence over Credential.(Optional)
+	//
+	// Use it when the credential rotates. See auth.CredentialProvider for details.
+	CredentialProvider auth.CredentialProvider
 
 	// Domain area for the REST Client. See domain.Area for details.
 	DomainArea domain.Area
+	// Region prefixes and domain suffixes used instead of the ones of DomainArea.(Optional)
+	//
+	// See domain.WithDomain for details.
+	Domain *domain.Domain
+	// Resolver that selects the domain suffix.(Optional)
+	//
+	// The default resolver resolves the domain of every suffix concurrently and selects the first one resolved.
+	Resolver domain.Resolver
+	// Fixed base URL all requests are sent to, e.g. for a private deployment or a local stand-in server.(Optional)
+	//
+	// DomainArea, Domain and Resolver are ignored when it is set. See domain.WithBaseURL for details.
+	BaseURL string
+	// Policy of the endpoint health tracking, which selects the endpoint requests are sent to.(Optional)
+	//
+	// The default value is domain.DefaultHealthPolicy(). See domain.HealthPolicy for details.
+	HealthPolicy *domain.HealthPolicy
+	// Policy of the circuit breaker of every endpoint host.(Optional)
+	//
+	// Requests fail fast with an agora.ErrCircuitOpen while the circuits of all the endpoints are open,
+	// and they are sent to another endpoint while the circuit of the best one is open.
+	//
+	// The default value is breaker.DefaultPolicy(). See breaker.Policy for details.
+	CircuitBreaker *breaker.Policy
+	// Interval of the background domain refresh.(Optional)
+	//
+	// When it is set, the domain is resolved by a background goroutine instead of when a request is sent,
+	// call Close to stop it. See domain.WithBackgroundRefresh for details.
+	DomainRefreshInterval time.Duration
+	// Handler of domain resolution errors.(Optional)
+	//
+	// The last known-good domain is kept when the resolution fails, requests are not affected.
+	OnDomainResolveError func(err error)
 
 	// Logger for the REST Client
 	//
 	// Implement the log.Logger interface in your project to output REST Client logs to your logging component.
 	//
 	// Alternatively, you can use the default logging component. See log.NewDefaultLogger for details.
+	//
+	// To output structured logs, wrap a log.StructuredLogger, e.g. log.NewSlogLogger, with log.NewLogger.
+	// The values of sensitive fields in logged request and response bodies are masked, see log.SensitiveFields.
 	Logger log.Logger
 
 	// Service version. See ServiceRegion for details.
 	ServiceRegion ServiceRegion
 }
 
+// Deprecated: Use Config.RetryPolicy, or agora.MaxAttempts for a single call, instead.
+//
+// RetryCount is the maximum number of attempts used when Config.RetryPolicy is nil, see retry.PolicyOrDefault.
 var RetryCount = 3
 
 // NewClient
@@ -80,12 +179,14 @@ var RetryCount = 3
 //
 // @param config Configuration of the Conversational AI engine client. See Config for details.
 //
+// @param options Options of the underlying client, e.g. a domain pool shared with other clients. See agoraClient.Option for details.
+//
 // @return Returns the Conversational AI engine client.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
 //
 // @since v0.7.0
-func NewClient(config *Config) (*Client, error) {
+func NewClient(config *Config, options ...agoraClient.Option) (*Client, error) {
 	var prefixPath string
 
 	switch config.ServiceRegion {
@@ -98,25 +199,45 @@ func NewClient(config *Config) (*Client, error) {
 	}
 
 	c, err := agoraClient.New(&agora.Config{
-		AppID:       config.AppID,
-		HttpTimeout: config.HttpTimeout,
-		Credential:  config.Credential,
-		DomainArea:  config.DomainArea,
-		Logger:      config.Logger,
-	})
+		AppID:                 config.AppID,
+		AppCertificate:        config.AppCertificate,
+		HttpTimeout:           config.HttpTimeout,
+		HttpClient:            config.HttpClient,
+		Transport:             config.Transport,
+		Middlewares:           config.Middlewares,
+		MaxResponseBodySize:   config.MaxResponseBodySize,
+		EnableGzip:            config.EnableGzip,
+		DiscardRawBody:        config.DiscardRawBody,
+		Hooks:                 config.Hooks,
+		RetryPolicy:           retry.PolicyOrDefault(config.RetryPolicy, RetryCount),
+		Instrumentation:       config.Instrumentation,
+		RateLimit:             config.RateLimit,
+		CredentialProvider:    config.CredentialProvider,
+		Credential:            config.Credential,
+		DomainArea:            config.DomainArea,
+		Domain:                config.Domain,
+		Resolver:              config.Resolver,
+		BaseURL:               config.BaseURL,
+		HealthPolicy:          config.HealthPolicy,
+		CircuitBreaker:        config.CircuitBreaker,
+		DomainRefreshInterval: config.DomainRefreshInterval,
+		OnDomainResolveError:  config.OnDomainResolveError,
+		Logger:                config.Logger,
+	}, options...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
-		joinAPI:      api.NewJoin("convoai:join", config.Logger, RetryCount, c, prefixPath),
-		leaveAPI:     api.NewLeave("convoai:leave", config.Logger, RetryCount, c, prefixPath),
-		listAPI:      api.NewList("convoai:list", config.Logger, RetryCount, c, prefixPath),
-		queryAPI:     api.NewQuery("convoai:query", config.Logger, RetryCount, c, prefixPath),
-		updateAPI:    api.NewUpdate("convoai:update", config.Logger, RetryCount, c, prefixPath),
-		interruptAPI: api.NewInterrupt("convoai:interrupt", config.Logger, RetryCount, c, prefixPath),
-		historyAPI:   api.NewHistory("convoai:history", config.Logger, RetryCount, c, prefixPath),
-		speakAPI:     api.NewSpeak("convoai:speak", config.Logger, RetryCount, c, prefixPath),
+		client:       c,
+		joinAPI:      api.NewJoin("convoai:join", config.Logger, c, prefixPath),
+		leaveAPI:     api.NewLeave("convoai:leave", config.Logger, c, prefixPath),
+		listAPI:      api.NewList("convoai:list", config.Logger, c, prefixPath),
+		queryAPI:     api.NewQuery("convoai:query", config.Logger, c, prefixPath),
+		updateAPI:    api.NewUpdate("convoai:update", config.Logger, c, prefixPath),
+		interruptAPI: api.NewInterrupt("convoai:interrupt", config.Logger, c, prefixPath),
+		historyAPI:   api.NewHistory("convoai:history", config.Logger, c, prefixPath),
+		speakAPI:     api.NewSpeak("convoai:speak", config.Logger, c, prefixPath),
 	}, nil
 }
 
@@ -135,11 +256,13 @@ func NewClient(config *Config) (*Client, error) {
 //
 // @param propertiesBody Configuration properties of the agent, including channel information, token, LLM settings, TTS settings, etc. See api.JoinPropertiesReqBody for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *JoinResp. See api.JoinResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (c *Client) Join(ctx context.Context, name string, payload *req.JoinPropertiesReqBody) (*resp.JoinResp, error) {
-	return c.joinAPI.Do(ctx, name, payload)
+func (c *Client) Join(ctx context.Context, name string, payload *req.JoinPropertiesReqBody, opts ...agora.CallOption) (*resp.JoinResp, error) {
+	return c.joinAPI.Do(ctx, name, payload, opts...)
 }
 
 // Leave
@@ -158,11 +281,13 @@ func (c *Client) Join(ctx context.Context, name string, payload *req.JoinPropert
 //
 // @param agentId Agent ID.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *LeaveResp. See api.LeaveResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (c *Client) Leave(ctx context.Context, agentId string) (*resp.LeaveResp, error) {
-	return c.leaveAPI.Do(ctx, agentId)
+func (c *Client) Leave(ctx context.Context, agentId string, opts ...agora.CallOption) (*resp.LeaveResp, error) {
+	return c.leaveAPI.Do(ctx, agentId, opts...)
 }
 
 // Query
@@ -181,11 +306,13 @@ func (c *Client) Leave(ctx context.Context, agentId string) (*resp.LeaveResp, er
 //
 // @param agentId Agent ID.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *QueryResp. See api.QueryResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (c *Client) Query(ctx context.Context, agentId string) (*resp.QueryResp, error) {
-	return c.queryAPI.Do(ctx, agentId)
+func (c *Client) Query(ctx context.Context, agentId string, opts ...agora.CallOption) (*resp.QueryResp, error) {
+	return c.queryAPI.Do(ctx, agentId, opts...)
 }
 
 // List
@@ -226,11 +353,13 @@ func (c *Client) List(ctx context.Context, options ...req.ListOption) (*resp.Lis
 //
 // @param payload Parameters to be adjusted. See api.UpdateReqBody for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *UpdateResp. See api.UpdateResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (c *Client) Update(ctx context.Context, agentId string, payload *req.UpdateReqBody) (*resp.UpdateResp, error) {
-	return c.updateAPI.Do(ctx, agentId, payload)
+func (c *Client) Update(ctx context.Context, agentId string, payload *req.UpdateReqBody, opts ...agora.CallOption) (*resp.UpdateResp, error) {
+	return c.updateAPI.Do(ctx, agentId, payload, opts...)
 }
 
 // Interrupt
@@ -245,11 +374,13 @@ func (c *Client) Update(ctx context.Context, agentId string, payload *req.Update
 //
 // @param agentId Agent ID.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *InterruptResp. See api.InterruptResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (c *Client) Interrupt(ctx context.Context, agentId string) (*resp.InterruptResp, error) {
-	return c.interruptAPI.Do(ctx, agentId)
+func (c *Client) Interrupt(ctx context.Context, agentId string, opts ...agora.CallOption) (*resp.InterruptResp, error) {
+	return c.interruptAPI.Do(ctx, agentId, opts...)
 }
 
 // GetHistory
@@ -264,11 +395,13 @@ func (c *Client) Interrupt(ctx context.Context, agentId string) (*resp.Interrupt
 //
 // @param agentId Agent ID.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *HistoryResp. See api.HistoryResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (c *Client) GetHistory(ctx context.Context, agentId string) (*resp.HistoryResp, error) {
-	return c.historyAPI.Do(ctx, agentId)
+func (c *Client) GetHistory(ctx context.Context, agentId string, opts ...agora.CallOption) (*resp.HistoryResp, error) {
+	return c.historyAPI.Do(ctx, agentId, opts...)
 }
 
 // Speak
@@ -283,9 +416,45 @@ func (c *Client) GetHistory(ctx context.Context, agentId string) (*resp.HistoryR
 //
 // @param payload Request body for the specified agent to speak a custom message. See api.SpeakBody for details.
 //
+// @param opts Options of the call, e.g. a timeout or extra headers. See agora.CallOption for details.
+//
 // @return Returns the response *SpeakResp. See api.SpeakResp for details.
 //
 // @return Returns an error object. If the request fails, the error object is not nil and contains error information.
-func (c *Client) Speak(ctx context.Context, agentId string, payload *req.SpeakBody) (*resp.SpeakResp, error) {
-	return c.speakAPI.Do(ctx, agentId, payload)
+func (c *Client) Speak(ctx context.Context, agentId string, payload *req.SpeakBody, opts ...agora.CallOption) (*resp.SpeakResp, error) {
+	return c.speakAPI.Do(ctx, agentId, payload, opts...)
+}
+
+// @brief Returns the endpoint health state of the domain pool, e.g. for dashboards
+//
+// @return Returns the snapshot. See domain.PoolSnapshot for details.
+//
+// @since v0.13.0
+func (c *Client) DomainPoolSnapshot() domain.PoolSnapshot {
+	return c.client.GetDomainPoolSnapshot()
+}
+
+// @brief Stops the background domain refresh of the client, see Config.DomainRefreshInterval
+//
+// @note The client must not be used after it is closed.
+//
+// @since v0.13.0
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// @brief Returns the statistics of the rate and concurrency limiters of the client, e.g. the time requests waited
+//
+// @return Returns the statistics. See limit.GroupStats for details.
+//
+// @since v0.13.0
+func (c *Client) LimiterStats() limit.GroupStats {
+	return c.client.GetLimiterStats()
+}
+
+// @brief Returns the state of the circuit breaker of every endpoint host used so far, keyed by the host
+//
+// @since v0.13.0
+func (c *Client) CircuitBreakerStates() map[string]breaker.State {
+	return c.client.GetCircuitBreakerStates()
 }
@@ -1,12 +1,17 @@
 package req
 
+import (
+	"github.com/AgoraIO-Community/agora-rest-client-go/agora"
+)
+
 type ListOptions struct {
-	Limit    *int
-	State    *int
-	FromTime *int
-	ToTime   *int
-	Cursor   *string
-	Channel  *string
+	Limit       *int
+	State       *int
+	FromTime    *int
+	ToTime      *int
+	Cursor      *string
+	Channel     *string
+	CallOptions []agora.CallOption
 }
 
 // @brief ListOption Define the filter condition type used to query the list of intelligent agents
@@ -105,3 +110,18 @@ func WithChannel(channel string) ListOption {
 		opts.Channel = &channel
 	}
 }
+
+// @brief WithCallOptions Set the options of the List call, e.g. a timeout.
+//
+// @note List takes list options, the call options are passed through this list option.
+//
+// @param opts Call options, see agora.CallOption for details
+//
+// @return Returns the ListOption function
+//
+// @since v0.13.0
+func WithCallOptions(opts ...agora.CallOption) ListOption {
+	return func(listOpts *ListOptions) {
+		listOpts.CallOptions = append(listOpts.CallOptions, opts...)
+	}
+}